@@ -18,11 +18,14 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
+	"time"
 
 	certmanv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta1"
+	clustersetv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 	workv1 "open-cluster-management.io/api/work/v1"
 
 	corev1 "k8s.io/api/core/v1"
@@ -33,7 +36,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
-	"k8s.io/client-go/tools/cache"
+	toolscache "k8s.io/client-go/tools/cache"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -52,6 +55,11 @@ var (
 	setupLog = ctrl.Log.WithName("setup")
 )
 
+const (
+	placementOCM           = "ocm"
+	placementClusterSecret = "cluster-secret"
+)
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme.Scheme))
 
@@ -59,6 +67,7 @@ func init() {
 	utilruntime.Must(certmanv1.AddToScheme(scheme.Scheme))
 	utilruntime.Must(gatewayapiv1.AddToScheme(scheme.Scheme))
 	utilruntime.Must(clusterv1beta2.AddToScheme(scheme.Scheme))
+	utilruntime.Must(clustersetv1beta2.AddToScheme(scheme.Scheme))
 	utilruntime.Must(workv1.AddToScheme(scheme.Scheme))
 	utilruntime.Must(clusterv1.AddToScheme(scheme.Scheme))
 
@@ -69,11 +78,28 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var placementMode string
+	var clusterSecretNamespace string
+	var clusterStatusSyncPeriod time.Duration
+	var manifestWorkMode string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&placementMode, "placement", placementOCM,
+		"How gateways are placed on clusters: \""+placementOCM+"\" through Open Cluster Management, or "+
+			"\""+placementClusterSecret+"\" directly through Argo CD style cluster secrets.")
+	flag.StringVar(&clusterSecretNamespace, "cluster-secret-namespace", "",
+		"The namespace of the cluster secrets when placing through cluster secrets. "+
+			"Defaults to the namespace of each gateway.")
+	flag.DurationVar(&clusterStatusSyncPeriod, "cluster-status-sync-period", 30*time.Second,
+		"How often the status of the downstream gateways is read back when placing through cluster secrets.")
+	flag.StringVar(&manifestWorkMode, "manifestwork-mode", string(placement.WorkPerObject),
+		"How the objects placed through Open Cluster Management are grouped into ManifestWorks: "+
+			"\""+string(placement.WorkPerObject)+"\" for a ManifestWork per gateway and synced policy on each cluster, "+
+			"\""+string(placement.WorkPerCluster)+"\" for a single ManifestWork per cluster, or "+
+			"\""+string(placement.WorkPerNamespace)+"\" for a ManifestWork per downstream namespace on each cluster.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -82,6 +108,16 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if placementMode != placementOCM && placementMode != placementClusterSecret {
+		setupLog.Error(fmt.Errorf("unknown placement %q", placementMode), "invalid flags")
+		os.Exit(1)
+	}
+	workMode, err := placement.ParseWorkMode(manifestWorkMode)
+	if err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+
 	ctx := ctrl.SetupSignalHandler()
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme.Scheme,
@@ -96,7 +132,12 @@ func main() {
 		os.Exit(1)
 	}
 
-	placer := placement.NewOCMPlacer(mgr.GetClient())
+	var placer gateway.GatewayPlacer = placement.NewOCMPlacerWithWorkMode(mgr.GetClient(), workMode)
+	if placementMode == placementClusterSecret {
+		// without ManifestWork feedback, the status of the downstream
+		// gateways is polled by reconciling the placed gateways periodically
+		placer = placement.NewClusterSecretPlacer(mgr.GetClient(), clusterSecretNamespace, clusterStatusSyncPeriod)
+	}
 	if err = (&gateway.GatewayClassReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
@@ -119,18 +160,35 @@ func main() {
 		os.Exit(1)
 	}
 
+	// policies are synced through ManifestWorks, so only with OCM placement
+	var policySyncer policysync.Syncer
+	if placementMode == placementOCM {
+		policySyncer = policysync.NewManifestWorkSyncer(gateway.DownstreamGateway, workMode)
+	}
+
 	if err = (&gateway.GatewayReconciler{
 		Client:                 mgr.GetClient(),
 		Scheme:                 mgr.GetScheme(),
 		Placement:              placer,
 		PolicyInformersManager: policyInformersManager,
 		DynamicClient:          dynamicClient,
-		WatchedPolicies:        map[schema.GroupVersionResource]cache.ResourceEventHandlerRegistration{},
+		WatchedPolicies:        map[schema.GroupVersionResource]toolscache.ResourceEventHandlerRegistration{},
+		DisableOCM:             placementMode != placementOCM,
+		PolicySyncer:           policySyncer,
 	}).SetupWithManager(mgr, ctx); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Gateway")
 		os.Exit(1)
 	}
 
+	if placementMode == placementOCM {
+		if err = (&gateway.ClusterRBACReconciler{
+			Client: mgr.GetClient(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterRBAC")
+			os.Exit(1)
+		}
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -9,16 +9,22 @@ import (
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"open-cluster-management.io/addon-framework/pkg/addonfactory"
 	"open-cluster-management.io/addon-framework/pkg/addonmanager"
+	"open-cluster-management.io/addon-framework/pkg/agent"
+	"open-cluster-management.io/addon-framework/pkg/utils"
 	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
 
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/env"
 	hub "github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/hub"
 )
 
@@ -27,6 +33,8 @@ var FS embed.FS
 
 const (
 	addonName = "kuadrant-addon"
+
+	defaultProbeAgentImage = "quay.io/kuadrant/policy-controller:main"
 )
 
 func GetDefaultValues(cluster *clusterv1.ManagedCluster,
@@ -47,6 +55,7 @@ func GetDefaultValues(cluster *clusterv1.ManagedCluster,
 		CatalogSource          string
 		CatalogSourceNS        string
 		Channel                string
+		ProbeAgentImage        string
 	}{
 		ClusterName:            cluster.Name,
 		IstioOperator:          defaultIstioOperator,
@@ -55,11 +64,42 @@ func GetDefaultValues(cluster *clusterv1.ManagedCluster,
 		CatalogSource:          defaultCatalog,
 		CatalogSourceNS:        defaultCatalogNS,
 		Channel:                defaultChannel,
+		ProbeAgentImage:        env.GetEnvString("PROBE_AGENT_IMAGE", defaultProbeAgentImage),
 	}
 
 	return addonfactory.StructToValues(manifestConfig), nil
 }
 
+// probeAgentRegistrationOption provides the probe agent on each spoke with a
+// hub kubeconfig, allowing it to watch the DNSHealthCheckProbes and report its
+// results in their status. Access to the Secrets referenced by the probes is
+// granted per namespace and by name by the policy controller
+func probeAgentRegistrationOption(kubeClient kubernetes.Interface) *agent.RegistrationOption {
+	probeAgentRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "kuadrant-addon-probe-agent"},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"kuadrant.io"},
+				Resources: []string{"dnshealthcheckprobes"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{"kuadrant.io"},
+				Resources: []string{"dnshealthcheckprobes/status"},
+				Verbs:     []string{"get", "update", "patch"},
+			},
+		},
+	}
+
+	return &agent.RegistrationOption{
+		CSRConfigurations: agent.KubeClientSignerConfigurations(addonName, addonName),
+		CSRApproveCheck:   utils.DefaultCSRApprover(addonName),
+		PermissionConfig: utils.NewRBACPermissionConfigBuilder(kubeClient).
+			BindClusterRoleToGroup(probeAgentRole, fmt.Sprintf("system:open-cluster-management:addon:%s", addonName)).
+			Build(),
+	}
+}
+
 func main() {
 	fmt.Println("starting add-on manager")
 	addonScheme := runtime.NewScheme()
@@ -77,6 +117,7 @@ func main() {
 
 	agentAddon, err := addonfactory.NewAgentAddonFactory(addonName, FS, "addon-manager/manifests").
 		WithAgentHealthProber(hub.AddonHealthProber()).
+		WithAgentRegistrationOption(probeAgentRegistrationOption(kubernetes.NewForConfigOrDie(kubeConfig))).
 		WithScheme(addonScheme).
 		WithGetValuesFuncs(GetDefaultValues, addonfactory.GetValuesFromAddonAnnotation).
 		BuildTemplateAgentAddon()
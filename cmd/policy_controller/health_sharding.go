@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/health"
+)
+
+// healthCheckShardGroup names the Leases of the replicas sharing the probes
+const healthCheckShardGroup = "dns-health-check"
+
+// newHealthCheckShard creates the shard of this replica, identified by its
+// hostname, which is the pod name when deployed
+func newHealthCheckShard(mgr ctrl.Manager, namespace string) (*health.Shard, error) {
+	if namespace == "" {
+		return nil, fmt.Errorf("--health-check-shard-namespace is required when sharding health checks")
+	}
+
+	identity, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the identity of the replica: %w", err)
+	}
+
+	return health.NewShard(mgr.GetClient(), mgr.GetAPIReader(), namespace, healthCheckShardGroup, identity), nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/health"
+)
+
+// globalWebhooks parses the comma separated webhook URLs notified of the
+// transitions of every probe. When keyFile is set, its content is the HMAC key
+// used to sign the payloads
+func globalWebhooks(urls, keyFile string) ([]health.Webhook, error) {
+	var key []byte
+	if keyFile != "" {
+		content, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading webhook key file: %w", err)
+		}
+		key = []byte(strings.TrimSpace(string(content)))
+	}
+
+	webhooks := []health.Webhook{}
+	for _, webhookURL := range strings.Split(urls, ",") {
+		webhookURL = strings.TrimSpace(webhookURL)
+		if webhookURL == "" {
+			continue
+		}
+		u, err := url.Parse(webhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL %s, it must be an absolute HTTP or HTTPS URL", webhookURL)
+		}
+		webhooks = append(webhooks, health.Webhook{URL: webhookURL, Key: key})
+	}
+	return webhooks, nil
+}
@@ -19,11 +19,11 @@ package main
 import (
 	"flag"
 	"os"
-	"time"
 
 	certmanv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
+	"golang.org/x/time/rate"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -38,6 +38,7 @@ import (
 	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
 
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1beta1"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/controllers/dnshealthcheckprobe"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/controllers/dnspolicy"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/controllers/dnsrecord"
@@ -45,6 +46,7 @@ import (
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/controllers/tlspolicy"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/dns/dnsprovider"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/health"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/webhooks"
 )
 
 var (
@@ -55,6 +57,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme.Scheme))
 	utilruntime.Must(gatewayapiv1.AddToScheme(scheme.Scheme))
 	utilruntime.Must(v1alpha1.AddToScheme(scheme.Scheme))
+	utilruntime.Must(v1beta1.AddToScheme(scheme.Scheme))
 	utilruntime.Must(certmanv1.AddToScheme(scheme.Scheme))
 	//this is need for now but will be removed soon
 	utilruntime.Must(clusterv1.AddToScheme(scheme.Scheme))
@@ -64,11 +67,44 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var enableWebhooks bool
+	var healthCheckWorkers int
+	var healthCheckRate float64
+	var healthCheckBurst int
+	var probeAgentVantagePoint string
+	var healthCheckWebhookURLs string
+	var healthCheckWebhookKeyFile string
+	var healthCheckSharding bool
+	var healthCheckShardNamespace string
+	var probeAgentGroup string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Enable the validating, defaulting and conversion webhooks for kuadrant.io resources. "+
+			"Requires a serving certificate to be mounted for the webhook server.")
+	flag.IntVar(&healthCheckWorkers, "health-check-workers", 10,
+		"The maximum number of DNS health checks performed concurrently.")
+	flag.Float64Var(&healthCheckRate, "health-check-rate", 20,
+		"The maximum number of DNS health checks started per second.")
+	flag.IntVar(&healthCheckBurst, "health-check-burst", 10,
+		"The maximum number of DNS health checks started at once when under the rate limit.")
+	flag.StringVar(&probeAgentVantagePoint, "probe-agent-vantage-point", "",
+		"Run as a DNS health check probe agent on a spoke cluster, reporting results under this vantage point name. "+
+			"The agent watches the DNSHealthCheckProbes of the hub cluster set by --kubeconfig.")
+	flag.StringVar(&probeAgentGroup, "probe-agent-group", dnshealthcheckprobe.ProbeAgentGroup,
+		"The group of the DNS health check probe agents, granted access to the Secrets referenced by the probes of each namespace. "+
+			"Set to an empty value when the probe agents are not deployed.")
+	flag.StringVar(&healthCheckWebhookURLs, "health-check-webhook-urls", "",
+		"Comma separated URLs notified when any DNS health check probe becomes healthy or unhealthy.")
+	flag.StringVar(&healthCheckWebhookKeyFile, "health-check-webhook-key-file", "",
+		"File holding the HMAC key used to sign the payloads sent to --health-check-webhook-urls.")
+	flag.BoolVar(&healthCheckSharding, "health-check-sharding", false,
+		"Split the DNS health check probes between all the replicas rather than checking them on the leader only.")
+	flag.StringVar(&healthCheckShardNamespace, "health-check-shard-namespace", "",
+		"The namespace of the Leases the replicas use to discover each other when --health-check-sharding is set.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -78,6 +114,16 @@ func main() {
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
 	ctx := ctrl.SetupSignalHandler()
+
+	if probeAgentVantagePoint != "" {
+		if err := runProbeAgent(ctx, probeAgentVantagePoint, metricsAddr, probeAddr,
+			health.NewRequestQueue(healthCheckWorkers, rate.Limit(healthCheckRate), healthCheckBurst)); err != nil {
+			setupLog.Error(err, "problem running probe agent")
+			os.Exit(1)
+		}
+		return
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme.Scheme,
 		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
@@ -85,6 +131,9 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "fb80029c-policy-controller.kuadrant.io",
+		// Release the lease on shutdown so that the next leader takes over the
+		// health checks without waiting for it to expire
+		LeaderElectionReleaseOnCancel: true,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -93,7 +142,21 @@ func main() {
 	provider := dnsprovider.NewProvider(mgr.GetClient())
 
 	healthMonitor := health.NewMonitor()
-	healthCheckQueue := health.NewRequestQueue(time.Second * 5)
+	healthCheckQueue := health.NewRequestQueue(healthCheckWorkers, rate.Limit(healthCheckRate), healthCheckBurst)
+
+	if healthCheckSharding {
+		shard, err := newHealthCheckShard(mgr, healthCheckShardNamespace)
+		if err != nil {
+			setupLog.Error(err, "unable to set up health check sharding")
+			os.Exit(1)
+		}
+		if err := mgr.Add(shard); err != nil {
+			setupLog.Error(err, "unable to start health check shard")
+			os.Exit(1)
+		}
+		healthMonitor.Shard = shard
+		healthCheckQueue.Sharded = true
+	}
 
 	if err := mgr.Add(healthMonitor); err != nil {
 		setupLog.Error(err, "unable to start health monitor")
@@ -105,6 +168,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	webhookSender := health.NewWebhookSender()
+	webhookSender.Sharded = healthCheckSharding
+	if err := mgr.Add(webhookSender); err != nil {
+		setupLog.Error(err, "unable to start health check webhook sender")
+		os.Exit(1)
+	}
+
 	if err = (&dnsrecord.DNSRecordReconciler{
 		Client:      mgr.GetClient(),
 		Scheme:      mgr.GetScheme(),
@@ -130,10 +200,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	healthCheckWebhooks, err := globalWebhooks(healthCheckWebhookURLs, healthCheckWebhookKeyFile)
+	if err != nil {
+		setupLog.Error(err, "unable to load health check webhooks")
+		os.Exit(1)
+	}
+
 	if err = (&dnshealthcheckprobe.DNSHealthCheckProbeReconciler{
-		Client:        mgr.GetClient(),
-		HealthMonitor: healthMonitor,
-		Queue:         healthCheckQueue,
+		Client:          mgr.GetClient(),
+		HealthMonitor:   healthMonitor,
+		Queue:           healthCheckQueue,
+		EventRecorder:   mgr.GetEventRecorderFor("DNSHealthCheckProbe"),
+		WebhookSender:   webhookSender,
+		Webhooks:        healthCheckWebhooks,
+		ProbeAgentGroup: probeAgentGroup,
+		SecretReader:    mgr.GetAPIReader(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DNSHealthCheckProbe")
 		os.Exit(1)
@@ -165,6 +246,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	if enableWebhooks {
+		if err = webhooks.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhooks")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
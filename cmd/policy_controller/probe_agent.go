@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/controllers/dnshealthcheckprobe"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/health"
+)
+
+// runProbeAgent runs the health check engine only, reporting the results of
+// the hub DNSHealthCheckProbes under the given vantage point. It is deployed
+// to the spoke clusters by the OCM add-on
+func runProbeAgent(ctx context.Context, vantagePoint, metricsAddr, probeAddr string, queue *health.QueuedProbeWorker) error {
+	if vantagePoint == dnshealthcheckprobe.HubVantagePoint {
+		return fmt.Errorf("vantage point name %q is reserved for the hub", vantagePoint)
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+		Scheme:                 scheme.Scheme,
+		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
+		HealthProbeBindAddress: probeAddr,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to start manager: %w", err)
+	}
+
+	healthMonitor := health.NewMonitor()
+	if err := mgr.Add(healthMonitor); err != nil {
+		return fmt.Errorf("unable to start health monitor: %w", err)
+	}
+	if err := mgr.Add(queue); err != nil {
+		return fmt.Errorf("unable to start health check queue: %w", err)
+	}
+
+	if err := (&dnshealthcheckprobe.DNSHealthCheckProbeReconciler{
+		Client:        mgr.GetClient(),
+		HealthMonitor: healthMonitor,
+		Queue:         queue,
+		VantagePoint:  vantagePoint,
+		SecretReader:  mgr.GetAPIReader(),
+	}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to create DNSHealthCheckProbe controller: %w", err)
+	}
+
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		return fmt.Errorf("unable to set up health check: %w", err)
+	}
+	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		return fmt.Errorf("unable to set up ready check: %w", err)
+	}
+
+	setupLog.Info("starting probe agent", "vantagePoint", vantagePoint)
+	return mgr.Start(ctx)
+}
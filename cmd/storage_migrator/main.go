@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The MultiCluster Traffic Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/storageversion"
+)
+
+var (
+	scheme = runtime.NewScheme()
+	logger = ctrl.Log.WithName("storage-migrator")
+)
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+}
+
+func main() {
+	var crds string
+	flag.StringVar(&crds, "crds", strings.Join(storageversion.KuadrantCRDs, ","),
+		"Comma separated list of CustomResourceDefinitions to migrate to their storage version.")
+	opts := zap.Options{
+		Development: true,
+	}
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		logger.Error(err, "unable to create client")
+		os.Exit(1)
+	}
+
+	migrator := &storageversion.Migrator{Client: c}
+	ctx := ctrl.LoggerInto(ctrl.SetupSignalHandler(), logger)
+	for _, crd := range strings.Split(crds, ",") {
+		if err := migrator.Migrate(ctx, strings.TrimSpace(crd)); err != nil {
+			logger.Error(err, "unable to migrate", "crd", crd)
+			os.Exit(1)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/json"
@@ -12,6 +13,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// ClientTimeout bounds each request made to a cluster, so that an unreachable
+// cluster doesn't hold up the reconciles reaching the other clusters
+const ClientTimeout = 10 * time.Second
+
 type TLSClientConfig struct {
 	Insecure bool   `json:"insecure"`
 	CaData   []byte `json:"caData,omitempty"`
@@ -82,6 +87,7 @@ func RestConfigFromSecret(secret *v1.Secret) (*rest.Config, error) {
 		Username:    clusterClientConfig.Username,
 		Password:    clusterClientConfig.Password,
 		BearerToken: clusterClientConfig.BearerToken,
+		Timeout:     ClientTimeout,
 		TLSClientConfig: rest.TLSClientConfig{
 			ServerName: strings.SplitN(hostUrl.Host, ":", 2)[0],
 			CertData:   clusterClientConfig.TlsClientConfig.CertData,
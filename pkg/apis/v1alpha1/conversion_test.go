@@ -0,0 +1,373 @@
+//go:build unit
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	certmanv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	certmanmetav1 "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1beta1"
+)
+
+func TestConversion_RoundTrip(t *testing.T) {
+	objectMeta := metav1.ObjectMeta{Name: "test", Namespace: "test", Generation: 2, Labels: map[string]string{"foo": "bar"}}
+	conditions := []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Accepted", LastTransitionTime: metav1.Now()}}
+	targetRef := gatewayapiv1alpha2.PolicyTargetReference{Group: "gateway.networking.k8s.io", Kind: "Gateway", Name: "test"}
+	protocol := HttpsProtocol
+
+	testCases := []struct {
+		name     string
+		obj      conversion.Convertible
+		hub      conversion.Hub
+		newSpoke func() conversion.Convertible
+	}{
+		{
+			name: "DNSPolicy",
+			obj: &DNSPolicy{
+				ObjectMeta: objectMeta,
+				Spec: DNSPolicySpec{
+					TargetRef: targetRef,
+					HealthCheck: &HealthCheckSpec{
+						Mode:                       ProbesAndProviderHealthCheckMode,
+						Endpoint:                   "/health",
+						Port:                       pointer.Int(443),
+						Protocol:                   &protocol,
+						FailureThreshold:           pointer.Int(3),
+						SuccessThreshold:           pointer.Int(2),
+						VantagePointQuorum:         pointer.Int(2),
+						CACertificateRef:           &CertificateRef{Name: "ca"},
+						ClientCertificateRef:       &CertificateRef{Name: "client-cert"},
+						Webhooks:                   []ProbeWebhook{{URL: "https://oncall.example.com/hooks", SigningSecretRef: &SigningSecretRef{Name: "webhook-key"}}},
+						ExpectedResponses:          []int{200, 201},
+						AllowInsecureCertificates:  true,
+						Interval:                   &metav1.Duration{Duration: time.Minute},
+						Timeout:                    &metav1.Duration{Duration: 5 * time.Second},
+						AdditionalHeadersRef:       &AdditionalHeadersRef{Name: "headers"},
+						ServerName:                 "test.example.com",
+						CertificateExpiryThreshold: &metav1.Duration{Duration: 24 * time.Hour},
+						GRPCService:                "grpc.health.v1.Health",
+						ResponseAssertions: &ResponseAssertions{
+							Body:    []BodyAssertion{{JSONPath: "{.status}", Value: "ok"}, {Contains: "ok"}},
+							Headers: []HeaderAssertion{{Name: "Content-Type", Matches: "^application/json"}},
+						},
+						FlapDamping: &FlapDamping{
+							Transitions: 4,
+							Window:      metav1.Duration{Duration: 10 * time.Minute},
+							CoolDown:    metav1.Duration{Duration: 15 * time.Minute},
+						},
+					},
+					LoadBalancing: &LoadBalancingSpec{
+						Weighted: &LoadBalancingWeighted{
+							DefaultWeight: 120,
+							Custom: []*CustomWeight{{
+								Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "1"}},
+								Weight:   200,
+							}},
+						},
+						Geo: &LoadBalancingGeo{DefaultGeo: "IE"},
+					},
+					RoutingStrategy: LoadBalancedRoutingStrategy,
+				},
+				Status: DNSPolicyStatus{
+					Conditions:         conditions,
+					ObservedGeneration: 2,
+					HealthCheck:        &HealthCheckStatus{Conditions: conditions},
+				},
+			},
+			hub:      &v1beta1.DNSPolicy{},
+			newSpoke: func() conversion.Convertible { return &DNSPolicy{} },
+		},
+		{
+			name: "TLSPolicy",
+			obj: &TLSPolicy{
+				ObjectMeta: objectMeta,
+				Spec: TLSPolicySpec{
+					TargetRef: targetRef,
+					CertificateSpec: CertificateSpec{
+						IssuerRef:   certmanmetav1.ObjectReference{Name: "issuer", Kind: "ClusterIssuer"},
+						CommonName:  "example.com",
+						Duration:    &metav1.Duration{Duration: time.Hour},
+						Usages:      []certmanv1.KeyUsage{certmanv1.UsageServerAuth},
+						PrivateKey:  &certmanv1.CertificatePrivateKey{Algorithm: certmanv1.ECDSAKeyAlgorithm},
+						RenewBefore: &metav1.Duration{Duration: time.Minute},
+					},
+				},
+				Status: TLSPolicyStatus{Conditions: conditions, ObservedGeneration: 2},
+			},
+			hub:      &v1beta1.TLSPolicy{},
+			newSpoke: func() conversion.Convertible { return &TLSPolicy{} },
+		},
+		{
+			name: "DNSRecord",
+			obj: &DNSRecord{
+				ObjectMeta: objectMeta,
+				Spec: DNSRecordSpec{
+					ManagedZoneRef: &ManagedZoneReference{Name: "mz"},
+					Endpoints: []*Endpoint{
+						{
+							DNSName:       "test.example.com",
+							Targets:       Targets{"lb.example.com"},
+							RecordType:    "CNAME",
+							SetIdentifier: "default",
+							RecordTTL:     300,
+							Labels:        Labels{"owner": "test"},
+							ProviderSpecific: ProviderSpecific{
+								{Name: "weight", Value: "120"},
+								{Name: "geo-code", Value: "IE"},
+								{Name: "aws/evaluate-target-health", Value: "true"},
+							},
+						},
+						{
+							DNSName:          "weird.example.com",
+							Targets:          Targets{"172.32.200.1"},
+							RecordType:       "A",
+							ProviderSpecific: ProviderSpecific{{Name: "weight", Value: "0120"}},
+						},
+					},
+				},
+				Status: DNSRecordStatus{Conditions: conditions, ObservedGeneration: 2},
+			},
+			hub:      &v1beta1.DNSRecord{},
+			newSpoke: func() conversion.Convertible { return &DNSRecord{} },
+		},
+		{
+			name: "ManagedZone",
+			obj: &ManagedZone{
+				ObjectMeta: objectMeta,
+				Spec: ManagedZoneSpec{
+					ID:                "ABC123",
+					DomainName:        "sub.example.com",
+					Description:       "test zone",
+					ParentManagedZone: &ManagedZoneReference{Name: "parent"},
+					SecretRef:         &SecretRef{Name: "secret"},
+				},
+				Status: ManagedZoneStatus{
+					Conditions:  conditions,
+					ID:          "ABC123",
+					RecordCount: 4,
+					NameServers: []*string{pointer.String("ns1.example.com")},
+				},
+			},
+			hub:      &v1beta1.ManagedZone{},
+			newSpoke: func() conversion.Convertible { return &ManagedZone{} },
+		},
+		{
+			name: "DNSHealthCheckProbe",
+			obj: &DNSHealthCheckProbe{
+				ObjectMeta: objectMeta,
+				Spec: DNSHealthCheckProbeSpec{
+					Port:                       443,
+					Host:                       "test.example.com",
+					Address:                    "172.32.200.1",
+					Path:                       "/health",
+					Protocol:                   HttpsProtocol,
+					Interval:                   metav1.Duration{Duration: time.Minute},
+					Timeout:                    &metav1.Duration{Duration: 5 * time.Second},
+					AdditionalHeadersRef:       &AdditionalHeadersRef{Name: "headers"},
+					FailureThreshold:           pointer.Int(3),
+					SuccessThreshold:           pointer.Int(2),
+					VantagePointQuorum:         pointer.Int(2),
+					CACertificateRef:           &CertificateRef{Name: "ca"},
+					ClientCertificateRef:       &CertificateRef{Name: "client-cert"},
+					Webhooks:                   []ProbeWebhook{{URL: "https://oncall.example.com/hooks"}},
+					ExpectedResponses:          []int{200},
+					AllowInsecureCertificate:   true,
+					ServerName:                 "test.example.com",
+					CertificateExpiryThreshold: &metav1.Duration{Duration: 24 * time.Hour},
+					GRPCService:                "grpc.health.v1.Health",
+					GRPCPlaintext:              true,
+					ResponseAssertions: &ResponseAssertions{
+						Body: []BodyAssertion{{Matches: "^ok$"}},
+					},
+					FlapDamping: &FlapDamping{
+						Transitions: 4,
+						Window:      metav1.Duration{Duration: 10 * time.Minute},
+						CoolDown:    metav1.Duration{Duration: 15 * time.Minute},
+					},
+				},
+				Status: DNSHealthCheckProbeStatus{
+					LastCheckedAt:       metav1.Now(),
+					ConsecutiveFailures: 1,
+					Reason:              "Status code: 503",
+					Status:              503,
+					Healthy:             pointer.Bool(false),
+					LastTransitionTime:  &metav1.Time{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+					RecentTransitions:   []metav1.Time{{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}},
+					VantagePoints: []VantagePointStatus{
+						{Name: "hub", LastCheckedAt: metav1.Now(), Reason: "Status code: 503", Status: 503},
+						{Name: "spoke-1", LastCheckedAt: metav1.Now(), Healthy: true, Status: 200},
+					},
+				},
+			},
+			hub:      &v1beta1.DNSHealthCheckProbe{},
+			newSpoke: func() conversion.Convertible { return &DNSHealthCheckProbe{} },
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if err := testCase.obj.ConvertTo(testCase.hub); err != nil {
+				t.Fatalf("failed to convert to hub: %s", err)
+			}
+			spoke := testCase.newSpoke()
+			if err := spoke.ConvertFrom(testCase.hub); err != nil {
+				t.Fatalf("failed to convert from hub: %s", err)
+			}
+			if !equality.Semantic.DeepEqual(testCase.obj, spoke) {
+				t.Errorf("round trip mismatch:\nexpected: %+v\ngot:      %+v", testCase.obj, spoke)
+			}
+		})
+	}
+}
+
+func TestDNSRecord_ConvertTo_ProviderSpecific(t *testing.T) {
+	record := &DNSRecord{
+		Spec: DNSRecordSpec{
+			Endpoints: []*Endpoint{{
+				DNSName: "test.example.com",
+				ProviderSpecific: ProviderSpecific{
+					{Name: "geo-code", Value: "IE"},
+					{Name: "weight", Value: "120"},
+				},
+			}},
+		},
+	}
+	hub := &v1beta1.DNSRecord{}
+	if err := record.ConvertTo(hub); err != nil {
+		t.Fatalf("failed to convert to hub: %s", err)
+	}
+	ps := hub.Spec.Endpoints[0].ProviderSpecific
+	if ps == nil || ps.Weight == nil || *ps.Weight != 120 || ps.GeoCode != "IE" || len(ps.Properties) != 0 {
+		t.Errorf("unexpected provider specific %+v", ps)
+	}
+}
+
+func TestDNSHealthCheckProbe_ConvertTo_Conditions(t *testing.T) {
+	probe := &DNSHealthCheckProbe{
+		Status: DNSHealthCheckProbeStatus{Healthy: pointer.Bool(true)},
+	}
+	hub := &v1beta1.DNSHealthCheckProbe{}
+	if err := probe.ConvertTo(hub); err != nil {
+		t.Fatalf("failed to convert to hub: %s", err)
+	}
+	if len(hub.Status.Conditions) != 1 || hub.Status.Conditions[0].Type != v1beta1.ProbeConditionHealthy || hub.Status.Conditions[0].Status != metav1.ConditionTrue {
+		t.Errorf("unexpected conditions %+v", hub.Status.Conditions)
+	}
+}
+
+func TestDNSHealthCheckProbe_ConvertFrom_Conditions(t *testing.T) {
+	hub := &v1beta1.DNSHealthCheckProbe{
+		ObjectMeta: metav1.ObjectMeta{Name: "probe", Annotations: map[string]string{"example.com/note": "kept"}},
+		Status: v1beta1.DNSHealthCheckProbeStatus{
+			Healthy: pointer.Bool(true),
+			Conditions: []metav1.Condition{
+				{Type: v1beta1.ProbeConditionHealthy, Status: metav1.ConditionTrue, Reason: "Healthy", LastTransitionTime: metav1.Now()},
+				{Type: "CertificateExpiring", Status: metav1.ConditionTrue, Reason: "ExpiresSoon", LastTransitionTime: metav1.Now()},
+			},
+		},
+	}
+	spoke := &DNSHealthCheckProbe{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("failed to convert from hub: %s", err)
+	}
+	if len(spoke.Status.Conditions) != 1 || spoke.Status.Conditions[0].Type != "CertificateExpiring" {
+		t.Fatalf("expected only the CertificateExpiring condition to be kept in the status, got %+v", spoke.Status.Conditions)
+	}
+	if !equality.Semantic.DeepEqual(hub.Annotations, spoke.Annotations) {
+		t.Errorf("expected annotations %v, got %v", hub.Annotations, spoke.Annotations)
+	}
+
+	converted := &v1beta1.DNSHealthCheckProbe{}
+	if err := spoke.ConvertTo(converted); err != nil {
+		t.Fatalf("failed to convert to hub: %s", err)
+	}
+	if !equality.Semantic.DeepEqual(hub.Annotations, converted.Annotations) {
+		t.Errorf("expected annotations %v, got %v", hub.Annotations, converted.Annotations)
+	}
+	if len(converted.Status.Conditions) != 2 || converted.Status.Conditions[0].Type != "CertificateExpiring" || converted.Status.Conditions[1].Type != v1beta1.ProbeConditionHealthy {
+		t.Errorf("unexpected conditions %+v", converted.Status.Conditions)
+	}
+}
+
+func TestDNSRecord_ProviderSpecific_RoundTrip(t *testing.T) {
+	testCases := []struct {
+		name             string
+		providerSpecific ProviderSpecific
+		// expected is the provider specific after the round trip, when it isn't the original
+		expected ProviderSpecific
+	}{
+		{
+			name: "weight and geo-code first",
+			providerSpecific: ProviderSpecific{
+				{Name: "weight", Value: "120"},
+				{Name: "geo-code", Value: "IE"},
+				{Name: "aws/health-check-id", Value: "abc"},
+				{Name: "custom", Value: "b"},
+				{Name: "custom", Value: "a"},
+			},
+		},
+		{
+			name: "weight that isn't an integer",
+			providerSpecific: ProviderSpecific{
+				{Name: "weight", Value: "heavy"},
+				{Name: "weight", Value: "10"},
+			},
+		},
+		{
+			name: "weight and geo-code after other properties",
+			providerSpecific: ProviderSpecific{
+				{Name: "aws/health-check-id", Value: "abc"},
+				{Name: "geo-code", Value: "IE"},
+				{Name: "weight", Value: "10"},
+				{Name: "weight", Value: "20"},
+				{Name: "custom", Value: "a"},
+			},
+			// the first weight and geo-code are listed first, which doesn't change the property
+			// a provider reads for any name
+			expected: ProviderSpecific{
+				{Name: "weight", Value: "10"},
+				{Name: "geo-code", Value: "IE"},
+				{Name: "aws/health-check-id", Value: "abc"},
+				{Name: "weight", Value: "20"},
+				{Name: "custom", Value: "a"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			endpoint := &Endpoint{DNSName: "test.example.com", ProviderSpecific: testCase.providerSpecific}
+			record := &DNSRecord{Spec: DNSRecordSpec{Endpoints: []*Endpoint{endpoint}}}
+			hub := &v1beta1.DNSRecord{}
+			if err := record.ConvertTo(hub); err != nil {
+				t.Fatalf("failed to convert to hub: %s", err)
+			}
+			converted := &DNSRecord{}
+			if err := converted.ConvertFrom(hub); err != nil {
+				t.Fatalf("failed to convert from hub: %s", err)
+			}
+
+			expected := testCase.expected
+			if expected == nil {
+				expected = testCase.providerSpecific
+			}
+			got := converted.Spec.Endpoints[0]
+			if !equality.Semantic.DeepEqual(expected, got.ProviderSpecific) {
+				t.Errorf("expected %v, got %v", expected, got.ProviderSpecific)
+			}
+			for _, p := range testCase.providerSpecific {
+				want, _ := endpoint.GetProviderSpecificProperty(p.Name)
+				if property, _ := got.GetProviderSpecificProperty(p.Name); property != want {
+					t.Errorf("expected %s to be %q, got %q", p.Name, want.Value, property.Value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1beta1"
+)
+
+var _ conversion.Convertible = &DNSHealthCheckProbe{}
+
+// ConvertTo converts this DNSHealthCheckProbe to the Hub version (v1beta1). The Healthy
+// condition is derived from the probe result.
+func (src *DNSHealthCheckProbe) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.DNSHealthCheckProbe)
+	src = src.DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1beta1.DNSHealthCheckProbeSpec{
+		Port:                       src.Spec.Port,
+		Host:                       src.Spec.Host,
+		Address:                    src.Spec.Address,
+		Path:                       src.Spec.Path,
+		Protocol:                   v1beta1.HealthProtocol(src.Spec.Protocol),
+		Interval:                   src.Spec.Interval,
+		Timeout:                    src.Spec.Timeout,
+		FailureThreshold:           src.Spec.FailureThreshold,
+		SuccessThreshold:           src.Spec.SuccessThreshold,
+		FlapDamping:                src.Spec.FlapDamping.convertTo(),
+		VantagePointQuorum:         src.Spec.VantagePointQuorum,
+		ExpectedResponses:          src.Spec.ExpectedResponses,
+		AllowInsecureCertificate:   src.Spec.AllowInsecureCertificate,
+		ServerName:                 src.Spec.ServerName,
+		CertificateExpiryThreshold: src.Spec.CertificateExpiryThreshold,
+		GRPCService:                src.Spec.GRPCService,
+		GRPCPlaintext:              src.Spec.GRPCPlaintext,
+		ResponseAssertions:         src.Spec.ResponseAssertions.convertTo(),
+		CACertificateRef:           src.Spec.CACertificateRef.convertTo(),
+		ClientCertificateRef:       src.Spec.ClientCertificateRef.convertTo(),
+		Webhooks:                   convertWebhooksTo(src.Spec.Webhooks),
+	}
+	if src.Spec.AdditionalHeadersRef != nil {
+		dst.Spec.AdditionalHeadersRef = &v1beta1.AdditionalHeadersRef{Name: src.Spec.AdditionalHeadersRef.Name}
+	}
+	dst.Status = v1beta1.DNSHealthCheckProbeStatus{
+		Conditions:           src.Status.Conditions,
+		LastCheckedAt:        src.Status.LastCheckedAt,
+		ConsecutiveFailures:  src.Status.ConsecutiveFailures,
+		ConsecutiveSuccesses: src.Status.ConsecutiveSuccesses,
+		Reason:               src.Status.Reason,
+		Status:               src.Status.Status,
+		Healthy:              src.Status.Healthy,
+		LastTransitionTime:   src.Status.LastTransitionTime,
+		RecentTransitions:    src.Status.RecentTransitions,
+		DampedUntil:          src.Status.DampedUntil,
+	}
+	for _, vp := range src.Status.VantagePoints {
+		dst.Status.VantagePoints = append(dst.Status.VantagePoints, v1beta1.VantagePointStatus(vp))
+	}
+	for _, result := range src.Status.RecentResults {
+		dst.Status.RecentResults = append(dst.Status.RecentResults, v1beta1.ProbeResult(result))
+	}
+	if src.Status.Healthy != nil {
+		condition := metav1.Condition{
+			Type:               v1beta1.ProbeConditionHealthy,
+			Status:             metav1.ConditionFalse,
+			Reason:             "Unhealthy",
+			Message:            src.Status.Reason,
+			ObservedGeneration: src.Generation,
+			LastTransitionTime: src.Status.LastCheckedAt,
+		}
+		if src.Status.LastTransitionTime != nil {
+			condition.LastTransitionTime = *src.Status.LastTransitionTime
+		}
+		if *src.Status.Healthy {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "Healthy"
+		}
+		meta.SetStatusCondition(&dst.Status.Conditions, condition)
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version. The Healthy condition is
+// dropped as it can be derived again from the probe result.
+func (dst *DNSHealthCheckProbe) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.DNSHealthCheckProbe).DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = DNSHealthCheckProbeSpec{
+		Port:                       src.Spec.Port,
+		Host:                       src.Spec.Host,
+		Address:                    src.Spec.Address,
+		Path:                       src.Spec.Path,
+		Protocol:                   HealthProtocol(src.Spec.Protocol),
+		Interval:                   src.Spec.Interval,
+		Timeout:                    src.Spec.Timeout,
+		FailureThreshold:           src.Spec.FailureThreshold,
+		SuccessThreshold:           src.Spec.SuccessThreshold,
+		FlapDamping:                convertFlapDampingFrom(src.Spec.FlapDamping),
+		VantagePointQuorum:         src.Spec.VantagePointQuorum,
+		ExpectedResponses:          src.Spec.ExpectedResponses,
+		AllowInsecureCertificate:   src.Spec.AllowInsecureCertificate,
+		ServerName:                 src.Spec.ServerName,
+		CertificateExpiryThreshold: src.Spec.CertificateExpiryThreshold,
+		GRPCService:                src.Spec.GRPCService,
+		GRPCPlaintext:              src.Spec.GRPCPlaintext,
+		ResponseAssertions:         convertResponseAssertionsFrom(src.Spec.ResponseAssertions),
+		CACertificateRef:           convertCertificateRefFrom(src.Spec.CACertificateRef),
+		ClientCertificateRef:       convertCertificateRefFrom(src.Spec.ClientCertificateRef),
+		Webhooks:                   convertWebhooksFrom(src.Spec.Webhooks),
+	}
+	if src.Spec.AdditionalHeadersRef != nil {
+		dst.Spec.AdditionalHeadersRef = &AdditionalHeadersRef{Name: src.Spec.AdditionalHeadersRef.Name}
+	}
+	dst.Status = DNSHealthCheckProbeStatus{
+		Conditions: slice.Filter(src.Status.Conditions, func(c metav1.Condition) bool {
+			return c.Type != v1beta1.ProbeConditionHealthy
+		}),
+		LastCheckedAt:        src.Status.LastCheckedAt,
+		ConsecutiveFailures:  src.Status.ConsecutiveFailures,
+		ConsecutiveSuccesses: src.Status.ConsecutiveSuccesses,
+		Reason:               src.Status.Reason,
+		Status:               src.Status.Status,
+		Healthy:              src.Status.Healthy,
+		LastTransitionTime:   src.Status.LastTransitionTime,
+		RecentTransitions:    src.Status.RecentTransitions,
+		DampedUntil:          src.Status.DampedUntil,
+	}
+	for _, vp := range src.Status.VantagePoints {
+		dst.Status.VantagePoints = append(dst.Status.VantagePoints, VantagePointStatus(vp))
+	}
+	for _, result := range src.Status.RecentResults {
+		dst.Status.RecentResults = append(dst.Status.RecentResults, ProbeResult(result))
+	}
+	return nil
+}
@@ -17,27 +17,49 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // DNSHealthCheckProbeSpec defines the desired state of DNSHealthCheckProbe
 type DNSHealthCheckProbeSpec struct {
-	Port                     int                   `json:"port,omitempty"`
-	Host                     string                `json:"host,omitempty"`
-	Address                  string                `json:"address,omitempty"`
-	Path                     string                `json:"path,omitempty"`
-	Protocol                 HealthProtocol        `json:"protocol,omitempty"`
-	Interval                 metav1.Duration       `json:"interval,omitempty"`
-	AdditionalHeadersRef     *AdditionalHeadersRef `json:"additionalHeadersRef,omitempty"`
-	FailureThreshold         *int                  `json:"failureThreshold,omitempty"`
-	ExpectedResponses        []int                 `json:"expectedResponses,omitempty"`
-	AllowInsecureCertificate bool                  `json:"allowInsecureCertificate,omitempty"`
+	Port                       int                   `json:"port,omitempty"`
+	Host                       string                `json:"host,omitempty"`
+	Address                    string                `json:"address,omitempty"`
+	Path                       string                `json:"path,omitempty"`
+	Protocol                   HealthProtocol        `json:"protocol,omitempty"`
+	Interval                   metav1.Duration       `json:"interval,omitempty"`
+	Timeout                    *metav1.Duration      `json:"timeout,omitempty"`
+	AdditionalHeadersRef       *AdditionalHeadersRef `json:"additionalHeadersRef,omitempty"`
+	CACertificateRef           *CertificateRef       `json:"caCertificateRef,omitempty"`
+	ClientCertificateRef       *CertificateRef       `json:"clientCertificateRef,omitempty"`
+	FailureThreshold           *int                  `json:"failureThreshold,omitempty"`
+	SuccessThreshold           *int                  `json:"successThreshold,omitempty"`
+	FlapDamping                *FlapDamping          `json:"flapDamping,omitempty"`
+	ExpectedResponses          []int                 `json:"expectedResponses,omitempty"`
+	AllowInsecureCertificate   bool                  `json:"allowInsecureCertificate,omitempty"`
+	ServerName                 string                `json:"serverName,omitempty"`
+	CertificateExpiryThreshold *metav1.Duration      `json:"certificateExpiryThreshold,omitempty"`
+	GRPCService                string                `json:"grpcService,omitempty"`
+	GRPCPlaintext              bool                  `json:"grpcPlaintext,omitempty"`
+	ResponseAssertions         *ResponseAssertions   `json:"responseAssertions,omitempty"`
+	VantagePointQuorum         *int                  `json:"vantagePointQuorum,omitempty"`
+	Webhooks                   []ProbeWebhook        `json:"webhooks,omitempty"`
 }
 
 type AdditionalHeadersRef struct {
 	Name string `json:"name"`
 }
 
+// CertificateRef references a Secret in the namespace of the probe. A CA
+// bundle is read from the ca.crt key, a client certificate and its key from
+// the tls.crt and tls.key keys
+type CertificateRef struct {
+	Name string `json:"name"`
+}
+
 type AdditionalHeaders []AdditionalHeader
 
 type AdditionalHeader struct {
@@ -47,11 +69,48 @@ type AdditionalHeader struct {
 
 // DNSHealthCheckProbeStatus defines the observed state of DNSHealthCheckProbe
 type DNSHealthCheckProbeStatus struct {
-	LastCheckedAt       metav1.Time `json:"lastCheckedAt"`
-	ConsecutiveFailures int         `json:"consecutiveFailures,omitempty"`
-	Reason              string      `json:"reason,omitempty"`
-	Status              int         `json:"status,omitempty"`
-	Healthy             *bool       `json:"healthy"`
+	// Conditions are any status conditions of the probe other than Healthy, which is
+	// reported by the healthy field
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions           []metav1.Condition `json:"conditions,omitempty"`
+	LastCheckedAt        metav1.Time        `json:"lastCheckedAt"`
+	ConsecutiveFailures  int                `json:"consecutiveFailures,omitempty"`
+	ConsecutiveSuccesses int                `json:"consecutiveSuccesses,omitempty"`
+	Reason               string             `json:"reason,omitempty"`
+	Status               int                `json:"status,omitempty"`
+	Healthy              *bool              `json:"healthy"`
+	// LastTransitionTime is when Healthy last changed
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	// RecentTransitions are the times Healthy changed within the flap damping
+	// window
+	RecentTransitions []metav1.Time `json:"recentTransitions,omitempty"`
+	// DampedUntil is set while the probe is held unhealthy for flapping
+	DampedUntil *metav1.Time `json:"dampedUntil,omitempty"`
+	// VantagePoints are the latest results reported by the hub and by each
+	// probe agent
+	VantagePoints []VantagePointStatus `json:"vantagePoints,omitempty"`
+	// RecentResults are the latest checks performed by the hub, oldest first
+	RecentResults []ProbeResult `json:"recentResults,omitempty"`
+}
+
+// ProbeResult is the outcome of a single check
+type ProbeResult struct {
+	CheckedAt metav1.Time     `json:"checkedAt"`
+	Healthy   bool            `json:"healthy"`
+	Status    int             `json:"status,omitempty"`
+	Latency   metav1.Duration `json:"latency"`
+	Reason    string          `json:"reason,omitempty"`
+}
+
+// VantagePointStatus is the latest result of the probe from one vantage point
+type VantagePointStatus struct {
+	Name          string      `json:"name"`
+	LastCheckedAt metav1.Time `json:"lastCheckedAt"`
+	Healthy       bool        `json:"healthy"`
+	Reason        string      `json:"reason,omitempty"`
+	Status        int         `json:"status,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -83,6 +142,69 @@ func (p *DNSHealthCheckProbe) Default() {
 	}
 }
 
+// Validate ensures the resource is valid. Compatible with the validating interface
+// used by webhooks
+func (p *DNSHealthCheckProbe) Validate() error {
+	if p.Spec.Host == "" {
+		return fmt.Errorf("spec.host is required")
+	}
+	if p.Spec.Address == "" {
+		return fmt.Errorf("spec.address is required")
+	}
+	if p.Spec.Port < 0 || p.Spec.Port > 65535 {
+		return fmt.Errorf("invalid value for spec.port %d, it must be between 0 and 65535", p.Spec.Port)
+	}
+	if p.Spec.Interval.Duration < (time.Second * 5) {
+		return fmt.Errorf("invalid value for spec.interval %v, it cannot be shorter than 5s", p.Spec.Interval.Duration)
+	}
+	if p.Spec.Timeout != nil && p.Spec.Timeout.Duration <= 0 {
+		return fmt.Errorf("invalid value for spec.timeout %v, it must be positive", p.Spec.Timeout.Duration)
+	}
+	if p.Spec.Timeout != nil && p.Spec.Timeout.Duration > p.Spec.Interval.Duration {
+		return fmt.Errorf("invalid value for spec.timeout %v, it cannot be longer than spec.interval", p.Spec.Timeout.Duration)
+	}
+	if p.Spec.Protocol != "" && !p.Spec.Protocol.IsValid() {
+		return fmt.Errorf("invalid value for spec.protocol %s", p.Spec.Protocol)
+	}
+	if p.Spec.Protocol.IsTcp() && p.Spec.Port == 0 {
+		return fmt.Errorf("spec.port is required for %s probes", p.Spec.Protocol)
+	}
+	if p.Spec.CertificateExpiryThreshold != nil && p.Spec.CertificateExpiryThreshold.Duration < 0 {
+		return fmt.Errorf("invalid value for spec.certificateExpiryThreshold %v, it cannot be negative", p.Spec.CertificateExpiryThreshold.Duration)
+	}
+	if p.Spec.FailureThreshold != nil && *p.Spec.FailureThreshold < 1 {
+		return fmt.Errorf("invalid value for spec.failureThreshold %d, it must be at least 1", *p.Spec.FailureThreshold)
+	}
+	if p.Spec.SuccessThreshold != nil && *p.Spec.SuccessThreshold < 1 {
+		return fmt.Errorf("invalid value for spec.successThreshold %d, it must be at least 1", *p.Spec.SuccessThreshold)
+	}
+	if err := validateCertificateRefs("spec", p.Spec.CACertificateRef, p.Spec.ClientCertificateRef, &p.Spec.Protocol, p.Spec.GRPCPlaintext); err != nil {
+		return err
+	}
+	if err := validateWebhooks("spec.webhooks", p.Spec.Webhooks); err != nil {
+		return err
+	}
+	if p.Spec.VantagePointQuorum != nil && *p.Spec.VantagePointQuorum < 1 {
+		return fmt.Errorf("invalid value for spec.vantagePointQuorum %d, it must be at least 1", *p.Spec.VantagePointQuorum)
+	}
+	if p.Spec.FlapDamping != nil {
+		if err := p.Spec.FlapDamping.Validate("spec.flapDamping"); err != nil {
+			return err
+		}
+	}
+	if p.Spec.ResponseAssertions != nil {
+		if err := p.Spec.ResponseAssertions.Validate("spec.responseAssertions"); err != nil {
+			return err
+		}
+	}
+	for _, code := range p.Spec.ExpectedResponses {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("invalid value in spec.expectedResponses %d, it must be a valid HTTP status code", code)
+		}
+	}
+	return nil
+}
+
 func init() {
 	SchemeBuilder.Register(&DNSHealthCheckProbe{}, &DNSHealthCheckProbeList{})
 }
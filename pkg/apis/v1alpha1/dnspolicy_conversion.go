@@ -0,0 +1,154 @@
+package v1alpha1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1beta1"
+)
+
+var _ conversion.Convertible = &DNSPolicy{}
+
+// ConvertTo converts this DNSPolicy to the Hub version (v1beta1)
+func (src *DNSPolicy) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.DNSPolicy)
+	src = src.DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1beta1.DNSPolicySpec{
+		TargetRef:       src.Spec.TargetRef,
+		HealthCheck:     src.Spec.HealthCheck.convertTo(),
+		RoutingStrategy: v1beta1.RoutingStrategy(src.Spec.RoutingStrategy),
+	}
+	if lb := src.Spec.LoadBalancing; lb != nil {
+		dst.Spec.LoadBalancing = &v1beta1.LoadBalancingSpec{}
+		if lb.Weighted != nil {
+			dst.Spec.LoadBalancing.Weighted = &v1beta1.LoadBalancingWeighted{
+				DefaultWeight: v1beta1.Weight(lb.Weighted.DefaultWeight),
+			}
+			for _, custom := range lb.Weighted.Custom {
+				dst.Spec.LoadBalancing.Weighted.Custom = append(dst.Spec.LoadBalancing.Weighted.Custom, &v1beta1.CustomWeight{
+					Selector: custom.Selector,
+					Weight:   v1beta1.Weight(custom.Weight),
+				})
+			}
+		}
+		if lb.Geo != nil {
+			dst.Spec.LoadBalancing.Geo = &v1beta1.LoadBalancingGeo{DefaultGeo: lb.Geo.DefaultGeo}
+		}
+	}
+
+	dst.Status = v1beta1.DNSPolicyStatus{
+		Conditions:         src.Status.Conditions,
+		ObservedGeneration: src.Status.ObservedGeneration,
+	}
+	if src.Status.HealthCheck != nil {
+		dst.Status.HealthCheck = &v1beta1.HealthCheckStatus{Conditions: src.Status.HealthCheck.Conditions}
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version
+func (dst *DNSPolicy) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.DNSPolicy).DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = DNSPolicySpec{
+		TargetRef:       src.Spec.TargetRef,
+		HealthCheck:     convertHealthCheckSpecFrom(src.Spec.HealthCheck),
+		RoutingStrategy: RoutingStrategy(src.Spec.RoutingStrategy),
+	}
+	if lb := src.Spec.LoadBalancing; lb != nil {
+		dst.Spec.LoadBalancing = &LoadBalancingSpec{}
+		if lb.Weighted != nil {
+			dst.Spec.LoadBalancing.Weighted = &LoadBalancingWeighted{
+				DefaultWeight: Weight(lb.Weighted.DefaultWeight),
+			}
+			for _, custom := range lb.Weighted.Custom {
+				dst.Spec.LoadBalancing.Weighted.Custom = append(dst.Spec.LoadBalancing.Weighted.Custom, &CustomWeight{
+					Selector: custom.Selector,
+					Weight:   Weight(custom.Weight),
+				})
+			}
+		}
+		if lb.Geo != nil {
+			dst.Spec.LoadBalancing.Geo = &LoadBalancingGeo{DefaultGeo: lb.Geo.DefaultGeo}
+		}
+	}
+
+	dst.Status = DNSPolicyStatus{
+		Conditions:         src.Status.Conditions,
+		ObservedGeneration: src.Status.ObservedGeneration,
+	}
+	if src.Status.HealthCheck != nil {
+		dst.Status.HealthCheck = &HealthCheckStatus{Conditions: src.Status.HealthCheck.Conditions}
+	}
+	return nil
+}
+
+func (s *HealthCheckSpec) convertTo() *v1beta1.HealthCheckSpec {
+	if s == nil {
+		return nil
+	}
+	dst := &v1beta1.HealthCheckSpec{
+		Mode:                       v1beta1.HealthCheckMode(s.Mode),
+		Endpoint:                   s.Endpoint,
+		Port:                       s.Port,
+		FailureThreshold:           s.FailureThreshold,
+		SuccessThreshold:           s.SuccessThreshold,
+		FlapDamping:                s.FlapDamping.convertTo(),
+		VantagePointQuorum:         s.VantagePointQuorum,
+		ExpectedResponses:          s.ExpectedResponses,
+		AllowInsecureCertificate:   s.AllowInsecureCertificates,
+		Interval:                   s.Interval,
+		Timeout:                    s.Timeout,
+		ServerName:                 s.ServerName,
+		CertificateExpiryThreshold: s.CertificateExpiryThreshold,
+		GRPCService:                s.GRPCService,
+		ResponseAssertions:         s.ResponseAssertions.convertTo(),
+		CACertificateRef:           s.CACertificateRef.convertTo(),
+		ClientCertificateRef:       s.ClientCertificateRef.convertTo(),
+		Webhooks:                   convertWebhooksTo(s.Webhooks),
+	}
+	if s.Protocol != nil {
+		protocol := v1beta1.HealthProtocol(*s.Protocol)
+		dst.Protocol = &protocol
+	}
+	if s.AdditionalHeadersRef != nil {
+		dst.AdditionalHeadersRef = &v1beta1.AdditionalHeadersRef{Name: s.AdditionalHeadersRef.Name}
+	}
+	return dst
+}
+
+func convertHealthCheckSpecFrom(s *v1beta1.HealthCheckSpec) *HealthCheckSpec {
+	if s == nil {
+		return nil
+	}
+	dst := &HealthCheckSpec{
+		Mode:                       HealthCheckMode(s.Mode),
+		Endpoint:                   s.Endpoint,
+		Port:                       s.Port,
+		FailureThreshold:           s.FailureThreshold,
+		SuccessThreshold:           s.SuccessThreshold,
+		FlapDamping:                convertFlapDampingFrom(s.FlapDamping),
+		VantagePointQuorum:         s.VantagePointQuorum,
+		ExpectedResponses:          s.ExpectedResponses,
+		AllowInsecureCertificates:  s.AllowInsecureCertificate,
+		Interval:                   s.Interval,
+		Timeout:                    s.Timeout,
+		ServerName:                 s.ServerName,
+		CertificateExpiryThreshold: s.CertificateExpiryThreshold,
+		GRPCService:                s.GRPCService,
+		ResponseAssertions:         convertResponseAssertionsFrom(s.ResponseAssertions),
+		CACertificateRef:           convertCertificateRefFrom(s.CACertificateRef),
+		ClientCertificateRef:       convertCertificateRefFrom(s.ClientCertificateRef),
+		Webhooks:                   convertWebhooksFrom(s.Webhooks),
+	}
+	if s.Protocol != nil {
+		protocol := HealthProtocol(*s.Protocol)
+		dst.Protocol = &protocol
+	}
+	if s.AdditionalHeadersRef != nil {
+		dst.AdditionalHeadersRef = &AdditionalHeadersRef{Name: s.AdditionalHeadersRef.Name}
+	}
+	return dst
+}
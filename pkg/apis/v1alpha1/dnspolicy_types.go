@@ -179,14 +179,30 @@ type DNSPolicyList struct {
 // By default, this health check will be applied to each unique DNS A Record for
 // the listeners assigned to the target gateway
 type HealthCheckSpec struct {
-	Endpoint                  string                `json:"endpoint,omitempty"`
-	Port                      *int                  `json:"port,omitempty"`
-	Protocol                  *HealthProtocol       `json:"protocol,omitempty"`
-	FailureThreshold          *int                  `json:"failureThreshold,omitempty"`
-	AdditionalHeadersRef      *AdditionalHeadersRef `json:"additionalHeadersRef,omitempty"`
-	ExpectedResponses         []int                 `json:"expectedResponses,omitempty"`
-	AllowInsecureCertificates bool                  `json:"allowInsecureCertificates,omitempty"`
-	Interval                  *metav1.Duration      `json:"interval,omitempty"`
+	// Mode chooses between in-cluster probes, health checks native to the DNS
+	// provider, or both. Defaults to Probes
+	// +kubebuilder:validation:Enum=Probes;Provider;ProbesAndProvider
+	// +optional
+	Mode                       HealthCheckMode       `json:"mode,omitempty"`
+	Endpoint                   string                `json:"endpoint,omitempty"`
+	Port                       *int                  `json:"port,omitempty"`
+	Protocol                   *HealthProtocol       `json:"protocol,omitempty"`
+	FailureThreshold           *int                  `json:"failureThreshold,omitempty"`
+	SuccessThreshold           *int                  `json:"successThreshold,omitempty"`
+	FlapDamping                *FlapDamping          `json:"flapDamping,omitempty"`
+	VantagePointQuorum         *int                  `json:"vantagePointQuorum,omitempty"`
+	AdditionalHeadersRef       *AdditionalHeadersRef `json:"additionalHeadersRef,omitempty"`
+	CACertificateRef           *CertificateRef       `json:"caCertificateRef,omitempty"`
+	ClientCertificateRef       *CertificateRef       `json:"clientCertificateRef,omitempty"`
+	ExpectedResponses          []int                 `json:"expectedResponses,omitempty"`
+	AllowInsecureCertificates  bool                  `json:"allowInsecureCertificates,omitempty"`
+	Interval                   *metav1.Duration      `json:"interval,omitempty"`
+	Timeout                    *metav1.Duration      `json:"timeout,omitempty"`
+	ServerName                 string                `json:"serverName,omitempty"`
+	CertificateExpiryThreshold *metav1.Duration      `json:"certificateExpiryThreshold,omitempty"`
+	GRPCService                string                `json:"grpcService,omitempty"`
+	ResponseAssertions         *ResponseAssertions   `json:"responseAssertions,omitempty"`
+	Webhooks                   []ProbeWebhook        `json:"webhooks,omitempty"`
 }
 
 func (s *HealthCheckSpec) Validate() error {
@@ -196,6 +212,59 @@ func (s *HealthCheckSpec) Validate() error {
 		}
 	}
 
+	if s.Timeout != nil {
+		if s.Timeout.Duration <= 0 {
+			return fmt.Errorf("invalid value for spec.healthCheckSpec.timeout %v, it must be positive", s.Timeout.Duration)
+		}
+		if s.Interval != nil && s.Timeout.Duration > s.Interval.Duration {
+			return fmt.Errorf("invalid value for spec.healthCheckSpec.timeout %v, it cannot be longer than the interval", s.Timeout.Duration)
+		}
+	}
+
+	if s.SuccessThreshold != nil && *s.SuccessThreshold < 1 {
+		return fmt.Errorf("invalid value for spec.healthCheckSpec.successThreshold %d, it must be at least 1", *s.SuccessThreshold)
+	}
+
+	if err := validateCertificateRefs("spec.healthCheckSpec", s.CACertificateRef, s.ClientCertificateRef, s.Protocol, false); err != nil {
+		return err
+	}
+
+	if err := validateWebhooks("spec.healthCheckSpec.webhooks", s.Webhooks); err != nil {
+		return err
+	}
+
+	if s.VantagePointQuorum != nil && *s.VantagePointQuorum < 1 {
+		return fmt.Errorf("invalid value for spec.healthCheckSpec.vantagePointQuorum %d, it must be at least 1", *s.VantagePointQuorum)
+	}
+
+	if s.FlapDamping != nil {
+		if err := s.FlapDamping.Validate("spec.healthCheckSpec.flapDamping"); err != nil {
+			return err
+		}
+	}
+
+	if s.Protocol != nil && !s.Protocol.IsValid() {
+		return fmt.Errorf("invalid value for spec.healthCheckSpec.protocol %s", *s.Protocol)
+	}
+
+	if !s.Mode.IsValid() {
+		return fmt.Errorf("invalid value for spec.healthCheckSpec.mode %s", s.Mode)
+	}
+
+	if s.Mode.UsesProvider() && s.Protocol != nil && !s.Protocol.IsHttp() && !s.Protocol.IsHttps() {
+		return fmt.Errorf("invalid value for spec.healthCheckSpec.protocol %s, provider health checks only support HTTP and HTTPS", *s.Protocol)
+	}
+
+	if s.CertificateExpiryThreshold != nil && s.CertificateExpiryThreshold.Duration < 0 {
+		return fmt.Errorf("invalid value for spec.healthCheckSpec.certificateExpiryThreshold %v, it cannot be negative", s.CertificateExpiryThreshold.Duration)
+	}
+
+	if s.ResponseAssertions != nil {
+		if err := s.ResponseAssertions.Validate("spec.healthCheckSpec.responseAssertions"); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -205,11 +274,6 @@ func (s *HealthCheckSpec) Default() {
 			Duration: time.Second * 30,
 		}
 	}
-
-	if s.Protocol == nil {
-		protocol := HttpsProtocol
-		s.Protocol = &protocol
-	}
 }
 
 type HealthCheckStatus struct {
@@ -0,0 +1,148 @@
+package v1alpha1
+
+import (
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1beta1"
+)
+
+// Provider specific property names that have a dedicated field in v1beta1. These mirror the
+// values used by the dns package, which can't be imported here without creating a cycle.
+const (
+	providerSpecificWeight  = "weight"
+	providerSpecificGeoCode = "geo-code"
+)
+
+var _ conversion.Convertible = &DNSRecord{}
+
+// ConvertTo converts this DNSRecord to the Hub version (v1beta1)
+func (src *DNSRecord) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.DNSRecord)
+	src = src.DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1beta1.DNSRecordSpec{
+		Endpoints: convertEndpointsTo(src.Spec.Endpoints),
+	}
+	if src.Spec.ManagedZoneRef != nil {
+		dst.Spec.ManagedZoneRef = &v1beta1.ManagedZoneReference{Name: src.Spec.ManagedZoneRef.Name}
+	}
+	dst.Status = v1beta1.DNSRecordStatus{
+		Conditions:         src.Status.Conditions,
+		ObservedGeneration: src.Status.ObservedGeneration,
+		Endpoints:          convertEndpointsTo(src.Status.Endpoints),
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version
+func (dst *DNSRecord) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.DNSRecord).DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = DNSRecordSpec{
+		Endpoints: convertEndpointsFrom(src.Spec.Endpoints),
+	}
+	if src.Spec.ManagedZoneRef != nil {
+		dst.Spec.ManagedZoneRef = &ManagedZoneReference{Name: src.Spec.ManagedZoneRef.Name}
+	}
+	dst.Status = DNSRecordStatus{
+		Conditions:         src.Status.Conditions,
+		ObservedGeneration: src.Status.ObservedGeneration,
+		Endpoints:          convertEndpointsFrom(src.Status.Endpoints),
+	}
+	return nil
+}
+
+func convertEndpointsTo(endpoints []*Endpoint) []*v1beta1.Endpoint {
+	if endpoints == nil {
+		return nil
+	}
+	converted := make([]*v1beta1.Endpoint, 0, len(endpoints))
+	for _, e := range endpoints {
+		if e == nil {
+			continue
+		}
+		converted = append(converted, &v1beta1.Endpoint{
+			DNSName:          e.DNSName,
+			Targets:          v1beta1.Targets(e.Targets),
+			RecordType:       e.RecordType,
+			SetIdentifier:    e.SetIdentifier,
+			RecordTTL:        v1beta1.TTL(e.RecordTTL),
+			Labels:           v1beta1.Labels(e.Labels),
+			ProviderSpecific: e.ProviderSpecific.convertTo(),
+		})
+	}
+	return converted
+}
+
+func convertEndpointsFrom(endpoints []*v1beta1.Endpoint) []*Endpoint {
+	if endpoints == nil {
+		return nil
+	}
+	converted := make([]*Endpoint, 0, len(endpoints))
+	for _, e := range endpoints {
+		if e == nil {
+			continue
+		}
+		converted = append(converted, &Endpoint{
+			DNSName:          e.DNSName,
+			Targets:          Targets(e.Targets),
+			RecordType:       e.RecordType,
+			SetIdentifier:    e.SetIdentifier,
+			RecordTTL:        TTL(e.RecordTTL),
+			Labels:           Labels(e.Labels),
+			ProviderSpecific: convertProviderSpecificFrom(e.ProviderSpecific),
+		})
+	}
+	return converted
+}
+
+// convertTo maps the well known weight and geo-code properties onto their typed v1beta1
+// fields. Providers read the first property of a name, so only the first of each is mapped, and
+// only when a weight is a plain integer. Every other property, including repeated names, is kept
+// in Properties in order so that it survives a round trip.
+func (ps ProviderSpecific) convertTo() *v1beta1.ProviderSpecific {
+	if len(ps) == 0 {
+		return nil
+	}
+	converted := &v1beta1.ProviderSpecific{}
+	seen := map[string]bool{}
+	for _, p := range ps {
+		first := !seen[p.Name]
+		seen[p.Name] = true
+		switch {
+		case first && p.Name == providerSpecificWeight:
+			if weight, err := strconv.ParseInt(p.Value, 10, 64); err == nil && strconv.FormatInt(weight, 10) == p.Value {
+				converted.Weight = &weight
+				continue
+			}
+		case first && p.Name == providerSpecificGeoCode:
+			converted.GeoCode = p.Value
+			continue
+		}
+		converted.Properties = append(converted.Properties, v1beta1.ProviderSpecificProperty{Name: p.Name, Value: p.Value})
+	}
+	return converted
+}
+
+// convertProviderSpecificFrom lists the typed weight and geo-code first, so that they stay the
+// first property of their name, followed by the other properties in order.
+func convertProviderSpecificFrom(ps *v1beta1.ProviderSpecific) ProviderSpecific {
+	if ps == nil {
+		return nil
+	}
+	var converted ProviderSpecific
+	if ps.Weight != nil {
+		converted = append(converted, ProviderSpecificProperty{Name: providerSpecificWeight, Value: strconv.FormatInt(*ps.Weight, 10)})
+	}
+	if ps.GeoCode != "" {
+		converted = append(converted, ProviderSpecificProperty{Name: providerSpecificGeoCode, Value: ps.GeoCode})
+	}
+	for _, p := range ps.Properties {
+		converted = append(converted, ProviderSpecificProperty{Name: p.Name, Value: p.Value})
+	}
+	return converted
+}
@@ -18,6 +18,9 @@ package v1alpha1
 
 import (
 	"fmt"
+	"net"
+	"regexp"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -241,6 +244,97 @@ func (endpoint *Endpoint) DeleteProviderSpecific(name string) bool {
 	return deleted
 }
 
+// Validate ensures the resource is valid. Compatible with the validating interface
+// used by webhooks
+func (r *DNSRecord) Validate() error {
+	if r.Spec.ManagedZoneRef == nil || r.Spec.ManagedZoneRef.Name == "" {
+		return fmt.Errorf("spec.managedZone.name is required")
+	}
+
+	setIDs := map[string]struct{}{}
+	recordTypes := map[string]map[string]struct{}{}
+	for i, endpoint := range r.Spec.Endpoints {
+		if err := ValidateDNSName(endpoint.DNSName, true); err != nil {
+			return fmt.Errorf("invalid spec.endpoints[%d].dnsName: %w", i, err)
+		}
+		if _, ok := setIDs[endpoint.SetID()]; ok {
+			return fmt.Errorf("invalid spec.endpoints[%d]: duplicate endpoint for dnsName %s and setIdentifier '%s'", i, endpoint.DNSName, endpoint.SetIdentifier)
+		}
+		setIDs[endpoint.SetID()] = struct{}{}
+
+		if len(endpoint.Targets) == 0 {
+			return fmt.Errorf("invalid spec.endpoints[%d]: at least one target is required", i)
+		}
+		switch DNSRecordType(endpoint.RecordType) {
+		case ARecordType:
+			for _, target := range endpoint.Targets {
+				if ip := net.ParseIP(target); ip == nil || ip.To4() == nil {
+					return fmt.Errorf("invalid spec.endpoints[%d].targets: %s is not a valid IPv4 address for an A record", i, target)
+				}
+			}
+		case CNAMERecordType:
+			if len(endpoint.Targets) > 1 {
+				return fmt.Errorf("invalid spec.endpoints[%d].targets: a CNAME record must have exactly one target, got %d", i, len(endpoint.Targets))
+			}
+			fallthrough
+		case NSRecordType:
+			for _, target := range endpoint.Targets {
+				if err := ValidateDNSName(target, false); err != nil {
+					return fmt.Errorf("invalid spec.endpoints[%d].targets: %s record target %s: %w", i, endpoint.RecordType, target, err)
+				}
+			}
+		default:
+			return fmt.Errorf("invalid spec.endpoints[%d].recordType %s", i, endpoint.RecordType)
+		}
+
+		name := strings.ToLower(endpoint.DNSName)
+		if recordTypes[name] == nil {
+			recordTypes[name] = map[string]struct{}{}
+		}
+		recordTypes[name][endpoint.RecordType] = struct{}{}
+	}
+
+	// a CNAME record can not coexist with any other record type for the same name
+	for name, types := range recordTypes {
+		if _, ok := types[string(CNAMERecordType)]; ok && len(types) > 1 {
+			return fmt.Errorf("invalid spec.endpoints: dnsName %s has a CNAME record and other record types", name)
+		}
+	}
+
+	return nil
+}
+
+const (
+	maxDNSNameLength  = 253
+	maxDNSLabelLength = 63
+)
+
+var dnsLabelRegexp = regexp.MustCompile(`^[a-zA-Z0-9_]([-a-zA-Z0-9_]*[a-zA-Z0-9])?$`)
+
+// ValidateDNSName checks the given name is a valid fully qualified DNS name, with no label longer than 63 characters
+// and a total length of no more than 253 characters. A leading wildcard label is accepted if allowWildcard is true.
+func ValidateDNSName(name string, allowWildcard bool) error {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+	if len(name) > maxDNSNameLength {
+		return fmt.Errorf("name %s must be no more than %d characters", name, maxDNSNameLength)
+	}
+	for i, label := range strings.Split(name, ".") {
+		if i == 0 && allowWildcard && label == "*" {
+			continue
+		}
+		if len(label) > maxDNSLabelLength {
+			return fmt.Errorf("label %s of name %s must be no more than %d characters", label, name, maxDNSLabelLength)
+		}
+		if !dnsLabelRegexp.MatchString(label) {
+			return fmt.Errorf("label '%s' of name %s must consist of alphanumeric characters or '-', and must start and end with an alphanumeric character", label, name)
+		}
+	}
+	return nil
+}
+
 func init() {
 	SchemeBuilder.Register(&DNSRecord{}, &DNSRecordList{})
 }
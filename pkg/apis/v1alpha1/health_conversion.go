@@ -0,0 +1,117 @@
+package v1alpha1
+
+import (
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1beta1"
+)
+
+func (a *ResponseAssertions) convertTo() *v1beta1.ResponseAssertions {
+	if a == nil {
+		return nil
+	}
+	dst := &v1beta1.ResponseAssertions{}
+	for _, b := range a.Body {
+		dst.Body = append(dst.Body, v1beta1.BodyAssertion{
+			Contains: b.Contains,
+			Matches:  b.Matches,
+			JSONPath: b.JSONPath,
+			Value:    b.Value,
+		})
+	}
+	for _, h := range a.Headers {
+		dst.Headers = append(dst.Headers, v1beta1.HeaderAssertion{
+			Name:    h.Name,
+			Value:   h.Value,
+			Matches: h.Matches,
+		})
+	}
+	return dst
+}
+
+func convertResponseAssertionsFrom(a *v1beta1.ResponseAssertions) *ResponseAssertions {
+	if a == nil {
+		return nil
+	}
+	dst := &ResponseAssertions{}
+	for _, b := range a.Body {
+		dst.Body = append(dst.Body, BodyAssertion{
+			Contains: b.Contains,
+			Matches:  b.Matches,
+			JSONPath: b.JSONPath,
+			Value:    b.Value,
+		})
+	}
+	for _, h := range a.Headers {
+		dst.Headers = append(dst.Headers, HeaderAssertion{
+			Name:    h.Name,
+			Value:   h.Value,
+			Matches: h.Matches,
+		})
+	}
+	return dst
+}
+
+func (d *FlapDamping) convertTo() *v1beta1.FlapDamping {
+	if d == nil {
+		return nil
+	}
+	return &v1beta1.FlapDamping{
+		Transitions: d.Transitions,
+		Window:      d.Window,
+		CoolDown:    d.CoolDown,
+	}
+}
+
+func convertFlapDampingFrom(d *v1beta1.FlapDamping) *FlapDamping {
+	if d == nil {
+		return nil
+	}
+	return &FlapDamping{
+		Transitions: d.Transitions,
+		Window:      d.Window,
+		CoolDown:    d.CoolDown,
+	}
+}
+
+func (r *CertificateRef) convertTo() *v1beta1.CertificateRef {
+	if r == nil {
+		return nil
+	}
+	return &v1beta1.CertificateRef{Name: r.Name}
+}
+
+func convertCertificateRefFrom(r *v1beta1.CertificateRef) *CertificateRef {
+	if r == nil {
+		return nil
+	}
+	return &CertificateRef{Name: r.Name}
+}
+
+func convertWebhooksTo(webhooks []ProbeWebhook) []v1beta1.ProbeWebhook {
+	if webhooks == nil {
+		return nil
+	}
+	dst := make([]v1beta1.ProbeWebhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		converted := v1beta1.ProbeWebhook{URL: webhook.URL}
+		if webhook.SigningSecretRef != nil {
+			converted.SigningSecretRef = &v1beta1.SigningSecretRef{Name: webhook.SigningSecretRef.Name}
+		}
+		dst = append(dst, converted)
+	}
+	return dst
+}
+
+func convertWebhooksFrom(webhooks []v1beta1.ProbeWebhook) []ProbeWebhook {
+	if webhooks == nil {
+		return nil
+	}
+	dst := make([]ProbeWebhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		converted := ProbeWebhook{URL: webhook.URL}
+		if webhook.SigningSecretRef != nil {
+			converted.SigningSecretRef = &SigningSecretRef{Name: webhook.SigningSecretRef.Name}
+		}
+		dst = append(dst, converted)
+	}
+	return dst
+}
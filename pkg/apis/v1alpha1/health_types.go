@@ -1,6 +1,28 @@
 package v1alpha1
 
-import "strings"
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// HealthCheckMode represents how the endpoints of a DNSPolicy are health checked
+type HealthCheckMode string
+
+const (
+	// ProbesHealthCheckMode checks endpoints with DNSHealthCheckProbes
+	// performed by the controller
+	ProbesHealthCheckMode HealthCheckMode = "Probes"
+	// ProviderHealthCheckMode checks endpoints with health checks created in
+	// the DNS provider, attached to the weighted records
+	ProviderHealthCheckMode HealthCheckMode = "Provider"
+	// ProbesAndProviderHealthCheckMode uses both probes and provider health checks
+	ProbesAndProviderHealthCheckMode HealthCheckMode = "ProbesAndProvider"
+)
 
 // HealthProtocol represents the protocol to use when making a health check request
 type HealthProtocol string
@@ -8,6 +30,9 @@ type HealthProtocol string
 const (
 	HttpProtocol  HealthProtocol = "HTTP"
 	HttpsProtocol HealthProtocol = "HTTPS"
+	TcpProtocol   HealthProtocol = "TCP"
+	TlsProtocol   HealthProtocol = "TLS"
+	GrpcProtocol  HealthProtocol = "GRPC"
 )
 
 func NewHealthProtocol(p string) HealthProtocol {
@@ -16,6 +41,12 @@ func NewHealthProtocol(p string) HealthProtocol {
 		return HttpsProtocol
 	case "HTTP":
 		return HttpProtocol
+	case "TCP":
+		return TcpProtocol
+	case "TLS":
+		return TlsProtocol
+	case "GRPC":
+		return GrpcProtocol
 	}
 	return HttpProtocol
 }
@@ -38,3 +69,186 @@ func (p HealthProtocol) IsHttp() bool {
 func (p HealthProtocol) IsHttps() bool {
 	return p == HttpsProtocol
 }
+
+func (p HealthProtocol) IsTcp() bool {
+	return p == TcpProtocol
+}
+
+func (p HealthProtocol) IsTls() bool {
+	return p == TlsProtocol
+}
+
+func (p HealthProtocol) IsGrpc() bool {
+	return p == GrpcProtocol
+}
+
+// UsesProbes returns true if endpoints are checked with DNSHealthCheckProbes.
+// This is the default when no mode is set
+func (m HealthCheckMode) UsesProbes() bool {
+	return m == "" || m == ProbesHealthCheckMode || m == ProbesAndProviderHealthCheckMode
+}
+
+// UsesProvider returns true if endpoints are checked by the DNS provider
+func (m HealthCheckMode) UsesProvider() bool {
+	return m == ProviderHealthCheckMode || m == ProbesAndProviderHealthCheckMode
+}
+
+// IsValid returns true if m is empty or one of the supported modes
+func (m HealthCheckMode) IsValid() bool {
+	return m.UsesProbes() || m.UsesProvider()
+}
+
+// IsValid returns true if p is one of the supported health check protocols
+func (p HealthProtocol) IsValid() bool {
+	return p.IsHttp() || p.IsHttps() || p.IsTcp() || p.IsTls() || p.IsGrpc()
+}
+
+// ResponseAssertions are evaluated against the response of HTTP and HTTPS
+// health checks, in addition to the expected response codes
+type ResponseAssertions struct {
+	Body    []BodyAssertion   `json:"body,omitempty"`
+	Headers []HeaderAssertion `json:"headers,omitempty"`
+}
+
+// BodyAssertion checks the response body. Exactly one of Contains, Matches or
+// JSONPath must be set
+type BodyAssertion struct {
+	// Contains is a substring the body must contain
+	Contains string `json:"contains,omitempty"`
+	// Matches is a regular expression the body must match
+	Matches string `json:"matches,omitempty"`
+	// JSONPath is an expression evaluated against the JSON body, e.g. {.status}
+	JSONPath string `json:"jsonPath,omitempty"`
+	// Value is the value the JSONPath expression must evaluate to
+	Value string `json:"value,omitempty"`
+}
+
+// HeaderAssertion checks a response header. If neither Value nor Matches is
+// set, the header must be present
+type HeaderAssertion struct {
+	Name string `json:"name"`
+	// Value is the exact value the header must have
+	Value string `json:"value,omitempty"`
+	// Matches is a regular expression the header value must match
+	Matches string `json:"matches,omitempty"`
+}
+
+// Validate ensures the assertions are well formed. field is the path of the
+// assertions in the resource, used in error messages
+func (a *ResponseAssertions) Validate(field string) error {
+	for i, b := range a.Body {
+		set := 0
+		for _, v := range []string{b.Contains, b.Matches, b.JSONPath} {
+			if v != "" {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("%s.body[%d] must set exactly one of contains, matches or jsonPath", field, i)
+		}
+		if b.Matches != "" {
+			if _, err := regexp.Compile(b.Matches); err != nil {
+				return fmt.Errorf("invalid value for %s.body[%d].matches: %w", field, i, err)
+			}
+		}
+		if b.JSONPath != "" {
+			if _, err := jsonpath.Parse("", RelaxedJSONPath(b.JSONPath)); err != nil {
+				return fmt.Errorf("invalid value for %s.body[%d].jsonPath: %w", field, i, err)
+			}
+		}
+	}
+	for i, h := range a.Headers {
+		if h.Name == "" {
+			return fmt.Errorf("%s.headers[%d].name is required", field, i)
+		}
+		if h.Matches != "" {
+			if _, err := regexp.Compile(h.Matches); err != nil {
+				return fmt.Errorf("invalid value for %s.headers[%d].matches: %w", field, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// FlapDamping holds an endpoint unhealthy for CoolDown when its health changes
+// Transitions times within Window
+type FlapDamping struct {
+	Transitions int             `json:"transitions"`
+	Window      metav1.Duration `json:"window"`
+	CoolDown    metav1.Duration `json:"coolDown"`
+}
+
+// ProbeWebhook is an HTTP endpoint notified with a JSON payload when a probe
+// becomes healthy or unhealthy
+type ProbeWebhook struct {
+	URL string `json:"url"`
+	// SigningSecretRef references a Secret in the namespace of the probe
+	// holding the HMAC key used to sign the payload under the signingKey key
+	SigningSecretRef *SigningSecretRef `json:"signingSecretRef,omitempty"`
+}
+
+type SigningSecretRef struct {
+	Name string `json:"name"`
+}
+
+// Validate ensures the flap damping settings are usable. field is the path of
+// the settings in the resource, used in error messages
+func (d *FlapDamping) Validate(field string) error {
+	if d.Transitions < 2 {
+		return fmt.Errorf("invalid value for %s.transitions %d, it must be at least 2", field, d.Transitions)
+	}
+	if d.Window.Duration <= 0 {
+		return fmt.Errorf("invalid value for %s.window %v, it must be positive", field, d.Window.Duration)
+	}
+	if d.CoolDown.Duration <= 0 {
+		return fmt.Errorf("invalid value for %s.coolDown %v, it must be positive", field, d.CoolDown.Duration)
+	}
+	return nil
+}
+
+// validateCertificateRefs ensures the CA and client certificate references are
+// set and only used with protocols that use TLS. field is the path of the
+// health check settings in the resource, used in error messages
+func validateCertificateRefs(field string, caRef, clientRef *CertificateRef, protocol *HealthProtocol, plaintext bool) error {
+	if caRef != nil && caRef.Name == "" {
+		return fmt.Errorf("%s.caCertificateRef.name is required", field)
+	}
+	if clientRef != nil && clientRef.Name == "" {
+		return fmt.Errorf("%s.clientCertificateRef.name is required", field)
+	}
+	if (caRef == nil && clientRef == nil) || protocol == nil {
+		return nil
+	}
+	if protocol.IsHttp() || protocol.IsTcp() || (protocol.IsGrpc() && plaintext) {
+		return fmt.Errorf("%s.caCertificateRef and %s.clientCertificateRef are only supported for HTTPS, TLS and GRPC over TLS", field, field)
+	}
+	return nil
+}
+
+// validateWebhooks ensures the webhooks are absolute HTTP or HTTPS URLs. field
+// is the path of the webhooks in the resource, used in error messages
+func validateWebhooks(field string, webhooks []ProbeWebhook) error {
+	for i, webhook := range webhooks {
+		u, err := url.Parse(webhook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid value for %s[%d].url %s, it must be an absolute HTTP or HTTPS URL", field, i, webhook.URL)
+		}
+		if webhook.SigningSecretRef != nil && webhook.SigningSecretRef.Name == "" {
+			return fmt.Errorf("%s[%d].signingSecretRef.name is required", field, i)
+		}
+	}
+	return nil
+}
+
+// RelaxedJSONPath wraps a JSONPath expression in braces if required, so that
+// both ".status" and "{.status}" are accepted
+func RelaxedJSONPath(expression string) string {
+	expression = strings.TrimSpace(expression)
+	if strings.HasPrefix(expression, "{") {
+		return expression
+	}
+	if !strings.HasPrefix(expression, ".") {
+		expression = "." + expression
+	}
+	return "{" + expression + "}"
+}
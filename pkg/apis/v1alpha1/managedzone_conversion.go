@@ -0,0 +1,62 @@
+package v1alpha1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1beta1"
+)
+
+var _ conversion.Convertible = &ManagedZone{}
+
+// ConvertTo converts this ManagedZone to the Hub version (v1beta1)
+func (src *ManagedZone) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.ManagedZone)
+	src = src.DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1beta1.ManagedZoneSpec{
+		ID:          src.Spec.ID,
+		DomainName:  src.Spec.DomainName,
+		Description: src.Spec.Description,
+	}
+	if src.Spec.ParentManagedZone != nil {
+		dst.Spec.ParentManagedZone = &v1beta1.ManagedZoneReference{Name: src.Spec.ParentManagedZone.Name}
+	}
+	if src.Spec.SecretRef != nil {
+		dst.Spec.SecretRef = &v1beta1.SecretRef{Name: src.Spec.SecretRef.Name}
+	}
+	dst.Status = v1beta1.ManagedZoneStatus{
+		Conditions:         src.Status.Conditions,
+		ObservedGeneration: src.Status.ObservedGeneration,
+		ID:                 src.Status.ID,
+		RecordCount:        src.Status.RecordCount,
+		NameServers:        src.Status.NameServers,
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version
+func (dst *ManagedZone) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.ManagedZone).DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = ManagedZoneSpec{
+		ID:          src.Spec.ID,
+		DomainName:  src.Spec.DomainName,
+		Description: src.Spec.Description,
+	}
+	if src.Spec.ParentManagedZone != nil {
+		dst.Spec.ParentManagedZone = &ManagedZoneReference{Name: src.Spec.ParentManagedZone.Name}
+	}
+	if src.Spec.SecretRef != nil {
+		dst.Spec.SecretRef = &SecretRef{Name: src.Spec.SecretRef.Name}
+	}
+	dst.Status = ManagedZoneStatus{
+		Conditions:         src.Status.Conditions,
+		ObservedGeneration: src.Status.ObservedGeneration,
+		ID:                 src.Status.ID,
+		RecordCount:        src.Status.RecordCount,
+		NameServers:        src.Status.NameServers,
+	}
+	return nil
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -105,6 +108,34 @@ type ManagedHost struct {
 	DnsRecord   *DNSRecord
 }
 
+// Validate ensures the resource is valid. Compatible with the validating interface
+// used by webhooks
+func (mz *ManagedZone) Validate() error {
+	if err := ValidateDNSName(mz.Spec.DomainName, false); err != nil {
+		return fmt.Errorf("invalid spec.domainName: %w", err)
+	}
+	if !strings.Contains(strings.TrimSuffix(mz.Spec.DomainName, "."), ".") {
+		return fmt.Errorf("invalid spec.domainName %s: a managed zone can not be a top level domain", mz.Spec.DomainName)
+	}
+	if mz.Spec.SecretRef == nil || mz.Spec.SecretRef.Name == "" {
+		return fmt.Errorf("spec.dnsProviderSecretRef.name is required")
+	}
+	if mz.Spec.ParentManagedZone != nil && mz.Spec.ParentManagedZone.Name == mz.Name {
+		return fmt.Errorf("invalid spec.parentManagedZone %s: a managed zone can not be its own parent", mz.Spec.ParentManagedZone.Name)
+	}
+	return nil
+}
+
+// ValidateParent ensures the domain of the given parent managed zone is an ancestor of this zones domain
+func (mz *ManagedZone) ValidateParent(parent *ManagedZone) error {
+	domain := strings.ToLower(strings.TrimSuffix(mz.Spec.DomainName, "."))
+	parentDomain := strings.ToLower(strings.TrimSuffix(parent.Spec.DomainName, "."))
+	if !strings.HasSuffix(domain, "."+parentDomain) {
+		return fmt.Errorf("invalid spec.parentManagedZone %s: domain %s is not a subdomain of the parent domain %s", parent.Name, mz.Spec.DomainName, parent.Spec.DomainName)
+	}
+	return nil
+}
+
 func init() {
 	SchemeBuilder.Register(&ManagedZone{}, &ManagedZoneList{})
 }
@@ -0,0 +1,58 @@
+package v1alpha1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1beta1"
+)
+
+var _ conversion.Convertible = &TLSPolicy{}
+
+// ConvertTo converts this TLSPolicy to the Hub version (v1beta1)
+func (src *TLSPolicy) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.TLSPolicy)
+	src = src.DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1beta1.TLSPolicySpec{
+		TargetRef: src.Spec.TargetRef,
+		CertificateSpec: v1beta1.CertificateSpec{
+			IssuerRef:            src.Spec.IssuerRef,
+			CommonName:           src.Spec.CommonName,
+			Duration:             src.Spec.Duration,
+			RenewBefore:          src.Spec.RenewBefore,
+			Usages:               src.Spec.Usages,
+			RevisionHistoryLimit: src.Spec.RevisionHistoryLimit,
+			PrivateKey:           src.Spec.PrivateKey,
+		},
+	}
+	dst.Status = v1beta1.TLSPolicyStatus{
+		Conditions:         src.Status.Conditions,
+		ObservedGeneration: src.Status.ObservedGeneration,
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version
+func (dst *TLSPolicy) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.TLSPolicy).DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = TLSPolicySpec{
+		TargetRef: src.Spec.TargetRef,
+		CertificateSpec: CertificateSpec{
+			IssuerRef:            src.Spec.IssuerRef,
+			CommonName:           src.Spec.CommonName,
+			Duration:             src.Spec.Duration,
+			RenewBefore:          src.Spec.RenewBefore,
+			Usages:               src.Spec.Usages,
+			RevisionHistoryLimit: src.Spec.RevisionHistoryLimit,
+			PrivateKey:           src.Spec.PrivateKey,
+		},
+	}
+	dst.Status = TLSPolicyStatus{
+		Conditions:         src.Status.Conditions,
+		ObservedGeneration: src.Status.ObservedGeneration,
+	}
+	return nil
+}
@@ -77,6 +77,36 @@ func (in *AdditionalHeadersRef) DeepCopy() *AdditionalHeadersRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BodyAssertion) DeepCopyInto(out *BodyAssertion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BodyAssertion.
+func (in *BodyAssertion) DeepCopy() *BodyAssertion {
+	if in == nil {
+		return nil
+	}
+	out := new(BodyAssertion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateRef) DeepCopyInto(out *CertificateRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRef.
+func (in *CertificateRef) DeepCopy() *CertificateRef {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateSpec) DeepCopyInto(out *CertificateSpec) {
 	*out = *in
@@ -201,6 +231,11 @@ func (in *DNSHealthCheckProbeList) DeepCopyObject() runtime.Object {
 func (in *DNSHealthCheckProbeSpec) DeepCopyInto(out *DNSHealthCheckProbeSpec) {
 	*out = *in
 	out.Interval = in.Interval
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.AdditionalHeadersRef != nil {
 		in, out := &in.AdditionalHeadersRef, &out.AdditionalHeadersRef
 		*out = new(AdditionalHeadersRef)
@@ -211,11 +246,53 @@ func (in *DNSHealthCheckProbeSpec) DeepCopyInto(out *DNSHealthCheckProbeSpec) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.SuccessThreshold != nil {
+		in, out := &in.SuccessThreshold, &out.SuccessThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.FlapDamping != nil {
+		in, out := &in.FlapDamping, &out.FlapDamping
+		*out = new(FlapDamping)
+		**out = **in
+	}
 	if in.ExpectedResponses != nil {
 		in, out := &in.ExpectedResponses, &out.ExpectedResponses
 		*out = make([]int, len(*in))
 		copy(*out, *in)
 	}
+	if in.CertificateExpiryThreshold != nil {
+		in, out := &in.CertificateExpiryThreshold, &out.CertificateExpiryThreshold
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ResponseAssertions != nil {
+		in, out := &in.ResponseAssertions, &out.ResponseAssertions
+		*out = new(ResponseAssertions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VantagePointQuorum != nil {
+		in, out := &in.VantagePointQuorum, &out.VantagePointQuorum
+		*out = new(int)
+		**out = **in
+	}
+	if in.CACertificateRef != nil {
+		in, out := &in.CACertificateRef, &out.CACertificateRef
+		*out = new(CertificateRef)
+		**out = **in
+	}
+	if in.ClientCertificateRef != nil {
+		in, out := &in.ClientCertificateRef, &out.ClientCertificateRef
+		*out = new(CertificateRef)
+		**out = **in
+	}
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]ProbeWebhook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSHealthCheckProbeSpec.
@@ -231,12 +308,48 @@ func (in *DNSHealthCheckProbeSpec) DeepCopy() *DNSHealthCheckProbeSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSHealthCheckProbeStatus) DeepCopyInto(out *DNSHealthCheckProbeStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.LastCheckedAt.DeepCopyInto(&out.LastCheckedAt)
 	if in.Healthy != nil {
 		in, out := &in.Healthy, &out.Healthy
 		*out = new(bool)
 		**out = **in
 	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RecentTransitions != nil {
+		in, out := &in.RecentTransitions, &out.RecentTransitions
+		*out = make([]v1.Time, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DampedUntil != nil {
+		in, out := &in.DampedUntil, &out.DampedUntil
+		*out = (*in).DeepCopy()
+	}
+	if in.VantagePoints != nil {
+		in, out := &in.VantagePoints, &out.VantagePoints
+		*out = make([]VantagePointStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RecentResults != nil {
+		in, out := &in.RecentResults, &out.RecentResults
+		*out = make([]ProbeResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSHealthCheckProbeStatus.
@@ -531,6 +644,38 @@ func (in *Endpoint) DeepCopy() *Endpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlapDamping) DeepCopyInto(out *FlapDamping) {
+	*out = *in
+	out.Window = in.Window
+	out.CoolDown = in.CoolDown
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlapDamping.
+func (in *FlapDamping) DeepCopy() *FlapDamping {
+	if in == nil {
+		return nil
+	}
+	out := new(FlapDamping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeaderAssertion) DeepCopyInto(out *HeaderAssertion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderAssertion.
+func (in *HeaderAssertion) DeepCopy() *HeaderAssertion {
+	if in == nil {
+		return nil
+	}
+	out := new(HeaderAssertion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HealthCheckSpec) DeepCopyInto(out *HealthCheckSpec) {
 	*out = *in
@@ -549,6 +694,16 @@ func (in *HealthCheckSpec) DeepCopyInto(out *HealthCheckSpec) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.SuccessThreshold != nil {
+		in, out := &in.SuccessThreshold, &out.SuccessThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.FlapDamping != nil {
+		in, out := &in.FlapDamping, &out.FlapDamping
+		*out = new(FlapDamping)
+		**out = **in
+	}
 	if in.AdditionalHeadersRef != nil {
 		in, out := &in.AdditionalHeadersRef, &out.AdditionalHeadersRef
 		*out = new(AdditionalHeadersRef)
@@ -564,6 +719,43 @@ func (in *HealthCheckSpec) DeepCopyInto(out *HealthCheckSpec) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.CertificateExpiryThreshold != nil {
+		in, out := &in.CertificateExpiryThreshold, &out.CertificateExpiryThreshold
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ResponseAssertions != nil {
+		in, out := &in.ResponseAssertions, &out.ResponseAssertions
+		*out = new(ResponseAssertions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VantagePointQuorum != nil {
+		in, out := &in.VantagePointQuorum, &out.VantagePointQuorum
+		*out = new(int)
+		**out = **in
+	}
+	if in.CACertificateRef != nil {
+		in, out := &in.CACertificateRef, &out.CACertificateRef
+		*out = new(CertificateRef)
+		**out = **in
+	}
+	if in.ClientCertificateRef != nil {
+		in, out := &in.ClientCertificateRef, &out.ClientCertificateRef
+		*out = new(CertificateRef)
+		**out = **in
+	}
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]ProbeWebhook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckSpec.
@@ -861,6 +1053,43 @@ func (in ProviderSpecific) DeepCopy() ProviderSpecific {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeResult) DeepCopyInto(out *ProbeResult) {
+	*out = *in
+	in.CheckedAt.DeepCopyInto(&out.CheckedAt)
+	out.Latency = in.Latency
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeResult.
+func (in *ProbeResult) DeepCopy() *ProbeResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeWebhook) DeepCopyInto(out *ProbeWebhook) {
+	*out = *in
+	if in.SigningSecretRef != nil {
+		in, out := &in.SigningSecretRef, &out.SigningSecretRef
+		*out = new(SigningSecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeWebhook.
+func (in *ProbeWebhook) DeepCopy() *ProbeWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderSpecificProperty) DeepCopyInto(out *ProviderSpecificProperty) {
 	*out = *in
@@ -876,6 +1105,31 @@ func (in *ProviderSpecificProperty) DeepCopy() *ProviderSpecificProperty {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResponseAssertions) DeepCopyInto(out *ResponseAssertions) {
+	*out = *in
+	if in.Body != nil {
+		in, out := &in.Body, &out.Body
+		*out = make([]BodyAssertion, len(*in))
+		copy(*out, *in)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]HeaderAssertion, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResponseAssertions.
+func (in *ResponseAssertions) DeepCopy() *ResponseAssertions {
+	if in == nil {
+		return nil
+	}
+	out := new(ResponseAssertions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretRef) DeepCopyInto(out *SecretRef) {
 	*out = *in
@@ -891,6 +1145,21 @@ func (in *SecretRef) DeepCopy() *SecretRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SigningSecretRef) DeepCopyInto(out *SigningSecretRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningSecretRef.
+func (in *SigningSecretRef) DeepCopy() *SigningSecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SigningSecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSPolicy) DeepCopyInto(out *TLSPolicy) {
 	*out = *in
@@ -1022,3 +1291,19 @@ func (in Targets) DeepCopy() Targets {
 	in.DeepCopyInto(out)
 	return *out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VantagePointStatus) DeepCopyInto(out *VantagePointStatus) {
+	*out = *in
+	in.LastCheckedAt.DeepCopyInto(&out.LastCheckedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VantagePointStatus.
+func (in *VantagePointStatus) DeepCopy() *VantagePointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VantagePointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,13 @@
+package v1beta1
+
+// v1beta1 is the hub version that all other served versions of the kuadrant.io resources are converted to and from.
+
+func (*DNSPolicy) Hub() {}
+
+func (*TLSPolicy) Hub() {}
+
+func (*DNSRecord) Hub() {}
+
+func (*ManagedZone) Hub() {}
+
+func (*DNSHealthCheckProbe) Hub() {}
@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The MultiCluster Traffic Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DNSHealthCheckProbeSpec defines the desired state of DNSHealthCheckProbe
+type DNSHealthCheckProbeSpec struct {
+	Port                       int                   `json:"port,omitempty"`
+	Host                       string                `json:"host,omitempty"`
+	Address                    string                `json:"address,omitempty"`
+	Path                       string                `json:"path,omitempty"`
+	Protocol                   HealthProtocol        `json:"protocol,omitempty"`
+	Interval                   metav1.Duration       `json:"interval,omitempty"`
+	Timeout                    *metav1.Duration      `json:"timeout,omitempty"`
+	AdditionalHeadersRef       *AdditionalHeadersRef `json:"additionalHeadersRef,omitempty"`
+	CACertificateRef           *CertificateRef       `json:"caCertificateRef,omitempty"`
+	ClientCertificateRef       *CertificateRef       `json:"clientCertificateRef,omitempty"`
+	FailureThreshold           *int                  `json:"failureThreshold,omitempty"`
+	SuccessThreshold           *int                  `json:"successThreshold,omitempty"`
+	FlapDamping                *FlapDamping          `json:"flapDamping,omitempty"`
+	ExpectedResponses          []int                 `json:"expectedResponses,omitempty"`
+	AllowInsecureCertificate   bool                  `json:"allowInsecureCertificate,omitempty"`
+	ServerName                 string                `json:"serverName,omitempty"`
+	CertificateExpiryThreshold *metav1.Duration      `json:"certificateExpiryThreshold,omitempty"`
+	GRPCService                string                `json:"grpcService,omitempty"`
+	GRPCPlaintext              bool                  `json:"grpcPlaintext,omitempty"`
+	ResponseAssertions         *ResponseAssertions   `json:"responseAssertions,omitempty"`
+	VantagePointQuorum         *int                  `json:"vantagePointQuorum,omitempty"`
+	Webhooks                   []ProbeWebhook        `json:"webhooks,omitempty"`
+}
+
+type AdditionalHeadersRef struct {
+	Name string `json:"name"`
+}
+
+// CertificateRef references a Secret in the namespace of the probe. A CA
+// bundle is read from the ca.crt key, a client certificate and its key from
+// the tls.crt and tls.key keys
+type CertificateRef struct {
+	Name string `json:"name"`
+}
+
+const (
+	// ProbeConditionHealthy is True when the probed address is considered healthy
+	ProbeConditionHealthy = "Healthy"
+)
+
+// DNSHealthCheckProbeStatus defines the observed state of DNSHealthCheckProbe
+type DNSHealthCheckProbeStatus struct {
+	// conditions are any conditions associated with the probe. Known condition types are `Healthy`.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions           []metav1.Condition `json:"conditions,omitempty"`
+	LastCheckedAt        metav1.Time        `json:"lastCheckedAt"`
+	ConsecutiveFailures  int                `json:"consecutiveFailures,omitempty"`
+	ConsecutiveSuccesses int                `json:"consecutiveSuccesses,omitempty"`
+	Reason               string             `json:"reason,omitempty"`
+	Status               int                `json:"status,omitempty"`
+	Healthy              *bool              `json:"healthy"`
+	// LastTransitionTime is when Healthy last changed
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	// RecentTransitions are the times Healthy changed within the flap damping
+	// window
+	RecentTransitions []metav1.Time `json:"recentTransitions,omitempty"`
+	// DampedUntil is set while the probe is held unhealthy for flapping
+	DampedUntil *metav1.Time `json:"dampedUntil,omitempty"`
+	// VantagePoints are the latest results reported by the hub and by each
+	// probe agent
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	VantagePoints []VantagePointStatus `json:"vantagePoints,omitempty"`
+	// RecentResults are the latest checks performed by the hub, oldest first
+	RecentResults []ProbeResult `json:"recentResults,omitempty"`
+}
+
+// ProbeResult is the outcome of a single check
+type ProbeResult struct {
+	CheckedAt metav1.Time     `json:"checkedAt"`
+	Healthy   bool            `json:"healthy"`
+	Status    int             `json:"status,omitempty"`
+	Latency   metav1.Duration `json:"latency"`
+	Reason    string          `json:"reason,omitempty"`
+}
+
+// VantagePointStatus is the latest result of the probe from one vantage point
+type VantagePointStatus struct {
+	Name          string      `json:"name"`
+	LastCheckedAt metav1.Time `json:"lastCheckedAt"`
+	Healthy       bool        `json:"healthy"`
+	Reason        string      `json:"reason,omitempty"`
+	Status        int         `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+//+kubebuilder:printcolumn:name="Healthy",type="boolean",JSONPath=".status.healthy",description="DNSHealthCheckProbe healthy."
+//+kubebuilder:printcolumn:name="Last Checked",type="date",JSONPath=".status.lastCheckedAt",description="Last checked at."
+
+// DNSHealthCheckProbe is the Schema for the dnshealthcheckprobes API
+type DNSHealthCheckProbe struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DNSHealthCheckProbeSpec   `json:"spec,omitempty"`
+	Status DNSHealthCheckProbeStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DNSHealthCheckProbeList contains a list of DNSHealthCheckProbe
+type DNSHealthCheckProbeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DNSHealthCheckProbe `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DNSHealthCheckProbe{}, &DNSHealthCheckProbeList{})
+}
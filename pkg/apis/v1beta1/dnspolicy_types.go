@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The MultiCluster Traffic Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+type RoutingStrategy string
+
+const (
+	SimpleRoutingStrategy       RoutingStrategy = "simple"
+	LoadBalancedRoutingStrategy RoutingStrategy = "loadbalanced"
+)
+
+// DNSPolicySpec defines the desired state of DNSPolicy
+type DNSPolicySpec struct {
+
+	// +kubebuilder:validation:Required
+	// +required
+	TargetRef gatewayapiv1alpha2.PolicyTargetReference `json:"targetRef"`
+
+	// +optional
+	HealthCheck *HealthCheckSpec `json:"healthCheck,omitempty"`
+
+	// loadBalancing configures the weighting and geo routing of the loadbalanced routing strategy. It is ignored by
+	// the simple routing strategy.
+	// +optional
+	LoadBalancing *LoadBalancingSpec `json:"loadBalancing,omitempty"`
+
+	// +required
+	// +kubebuilder:validation:Enum=simple;loadbalanced
+	// +kubebuilder:default=loadbalanced
+	RoutingStrategy RoutingStrategy `json:"routingStrategy"`
+}
+
+type LoadBalancingSpec struct {
+	// +optional
+	Weighted *LoadBalancingWeighted `json:"weighted,omitempty"`
+	// +optional
+	Geo *LoadBalancingGeo `json:"geo,omitempty"`
+}
+
+// +kubebuilder:validation:Minimum=0
+type Weight int
+
+type CustomWeight struct {
+	// Label selector used by MGC to match resource storing custom weight attribute values e.g. kuadrant.io/lb-attribute-custom-weight: AWS
+	// +required
+	Selector *metav1.LabelSelector `json:"selector"`
+	// +required
+	Weight Weight `json:"weight,omitempty"`
+}
+
+type LoadBalancingWeighted struct {
+	// defaultWeight is the record weight to use when no other can be determined for a dns target cluster.
+	//
+	// The maximum value accepted is determined by the target dns provider, please refer to the appropriate docs below.
+	//
+	// Route53: https://docs.aws.amazon.com/Route53/latest/DeveloperGuide/routing-policy-weighted.html
+	// +kubebuilder:default=120
+	DefaultWeight Weight `json:"defaultWeight,omitempty"`
+	// +optional
+	Custom []*CustomWeight `json:"custom,omitempty"`
+}
+
+type LoadBalancingGeo struct {
+	// defaultGeo is the country/continent/region code to use when no other can be determined for a dns target cluster.
+	//
+	// The values accepted are determined by the target dns provider, please refer to the appropriate docs below.
+	//
+	// Route53: https://docs.aws.amazon.com/Route53/latest/DeveloperGuide/resource-record-sets-values-geo.html
+	// +required
+	DefaultGeo string `json:"defaultGeo,omitempty"`
+}
+
+// DNSPolicyStatus defines the observed state of DNSPolicy
+type DNSPolicyStatus struct {
+
+	// conditions are any conditions associated with the policy
+	//
+	// If configuring the policy fails, the "Failed" condition will be set with a
+	// reason and message describing the cause of the failure.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// observedGeneration is the most recently observed generation of the
+	// DNSPolicy.  When the DNSPolicy is updated, the controller updates the
+	// corresponding configuration. If an update fails, that failure is
+	// recorded in the status condition
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// +optional
+	HealthCheck *HealthCheckStatus `json:"healthCheck,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="DNSPolicy ready."
+
+// DNSPolicy is the Schema for the dnspolicies API
+type DNSPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DNSPolicySpec   `json:"spec,omitempty"`
+	Status DNSPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DNSPolicyList contains a list of DNSPolicy
+type DNSPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DNSPolicy `json:"items"`
+}
+
+// HealthCheckSpec configures health checks in the DNS provider.
+// By default, this health check will be applied to each unique DNS A Record for
+// the listeners assigned to the target gateway
+type HealthCheckSpec struct {
+	// Mode chooses between in-cluster probes, health checks native to the DNS
+	// provider, or both. Defaults to Probes
+	// +kubebuilder:validation:Enum=Probes;Provider;ProbesAndProvider
+	// +optional
+	Mode                       HealthCheckMode       `json:"mode,omitempty"`
+	Endpoint                   string                `json:"endpoint,omitempty"`
+	Port                       *int                  `json:"port,omitempty"`
+	Protocol                   *HealthProtocol       `json:"protocol,omitempty"`
+	FailureThreshold           *int                  `json:"failureThreshold,omitempty"`
+	SuccessThreshold           *int                  `json:"successThreshold,omitempty"`
+	FlapDamping                *FlapDamping          `json:"flapDamping,omitempty"`
+	VantagePointQuorum         *int                  `json:"vantagePointQuorum,omitempty"`
+	AdditionalHeadersRef       *AdditionalHeadersRef `json:"additionalHeadersRef,omitempty"`
+	CACertificateRef           *CertificateRef       `json:"caCertificateRef,omitempty"`
+	ClientCertificateRef       *CertificateRef       `json:"clientCertificateRef,omitempty"`
+	ExpectedResponses          []int                 `json:"expectedResponses,omitempty"`
+	AllowInsecureCertificate   bool                  `json:"allowInsecureCertificate,omitempty"`
+	Interval                   *metav1.Duration      `json:"interval,omitempty"`
+	Timeout                    *metav1.Duration      `json:"timeout,omitempty"`
+	ServerName                 string                `json:"serverName,omitempty"`
+	CertificateExpiryThreshold *metav1.Duration      `json:"certificateExpiryThreshold,omitempty"`
+	GRPCService                string                `json:"grpcService,omitempty"`
+	ResponseAssertions         *ResponseAssertions   `json:"responseAssertions,omitempty"`
+	Webhooks                   []ProbeWebhook        `json:"webhooks,omitempty"`
+}
+
+type HealthCheckStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DNSPolicy{}, &DNSPolicyList{})
+}
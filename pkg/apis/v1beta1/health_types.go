@@ -0,0 +1,81 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HealthCheckMode represents how the endpoints of a DNSPolicy are health checked
+type HealthCheckMode string
+
+const (
+	// ProbesHealthCheckMode checks endpoints with DNSHealthCheckProbes
+	// performed by the controller
+	ProbesHealthCheckMode HealthCheckMode = "Probes"
+	// ProviderHealthCheckMode checks endpoints with health checks created in
+	// the DNS provider, attached to the weighted records
+	ProviderHealthCheckMode HealthCheckMode = "Provider"
+	// ProbesAndProviderHealthCheckMode uses both probes and provider health checks
+	ProbesAndProviderHealthCheckMode HealthCheckMode = "ProbesAndProvider"
+)
+
+// HealthProtocol represents the protocol to use when making a health check request
+type HealthProtocol string
+
+const (
+	HttpProtocol  HealthProtocol = "HTTP"
+	HttpsProtocol HealthProtocol = "HTTPS"
+	TcpProtocol   HealthProtocol = "TCP"
+	TlsProtocol   HealthProtocol = "TLS"
+	GrpcProtocol  HealthProtocol = "GRPC"
+)
+
+// ResponseAssertions are evaluated against the response of HTTP and HTTPS
+// health checks, in addition to the expected response codes
+type ResponseAssertions struct {
+	Body    []BodyAssertion   `json:"body,omitempty"`
+	Headers []HeaderAssertion `json:"headers,omitempty"`
+}
+
+// BodyAssertion checks the response body. Exactly one of Contains, Matches or
+// JSONPath must be set
+type BodyAssertion struct {
+	// Contains is a substring the body must contain
+	Contains string `json:"contains,omitempty"`
+	// Matches is a regular expression the body must match
+	Matches string `json:"matches,omitempty"`
+	// JSONPath is an expression evaluated against the JSON body, e.g. {.status}
+	JSONPath string `json:"jsonPath,omitempty"`
+	// Value is the value the JSONPath expression must evaluate to
+	Value string `json:"value,omitempty"`
+}
+
+// HeaderAssertion checks a response header. If neither Value nor Matches is
+// set, the header must be present
+type HeaderAssertion struct {
+	Name string `json:"name"`
+	// Value is the exact value the header must have
+	Value string `json:"value,omitempty"`
+	// Matches is a regular expression the header value must match
+	Matches string `json:"matches,omitempty"`
+}
+
+// FlapDamping holds an endpoint unhealthy for CoolDown when its health changes
+// Transitions times within Window
+type FlapDamping struct {
+	Transitions int             `json:"transitions"`
+	Window      metav1.Duration `json:"window"`
+	CoolDown    metav1.Duration `json:"coolDown"`
+}
+
+// ProbeWebhook is an HTTP endpoint notified with a JSON payload when a probe
+// becomes healthy or unhealthy
+type ProbeWebhook struct {
+	URL string `json:"url"`
+	// SigningSecretRef references a Secret in the namespace of the probe
+	// holding the HMAC key used to sign the payload under the signingKey key
+	SigningSecretRef *SigningSecretRef `json:"signingSecretRef,omitempty"`
+}
+
+type SigningSecretRef struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,75 @@
+package dnshealthcheckprobe
+
+import (
+	"context"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+)
+
+const (
+	// ProbeAgentGroup is the group of the probe agents deployed to the spoke
+	// clusters by the kuadrant-addon OCM add-on
+	ProbeAgentGroup = "system:open-cluster-management:addon:kuadrant-addon"
+
+	// probeAgentRoleName names the Role and RoleBinding granting the probe
+	// agents access to the Secrets referenced by the probes of a namespace
+	probeAgentRoleName = "kuadrant-probe-agent"
+)
+
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
+
+// reconcileAgentRBAC grants the probe agents get access to the Secrets the
+// probes in the namespace check with, by name, so that an agent can't read any
+// other Secret of the hub. The Role and RoleBinding are removed once no probe
+// in the namespace references a Secret
+func (r *DNSHealthCheckProbeReconciler) reconcileAgentRBAC(ctx context.Context, namespace string) error {
+	if r.ProbeAgentGroup == "" {
+		return nil
+	}
+
+	probes := &v1alpha1.DNSHealthCheckProbeList{}
+	if err := r.Client.List(ctx, probes, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	names := sets.New[string]()
+	for i := range probes.Items {
+		if probes.Items[i].DeletionTimestamp != nil {
+			continue
+		}
+		names.Insert(checkSecretNames(&probes.Items[i])...)
+	}
+
+	role := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: probeAgentRoleName, Namespace: namespace}}
+	binding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: probeAgentRoleName, Namespace: namespace}}
+
+	if names.Len() == 0 {
+		if err := r.Client.Delete(ctx, binding); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		return client.IgnoreNotFound(r.Client.Delete(ctx, role))
+	}
+
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, role, func() error {
+		role.Rules = []rbacv1.PolicyRule{{
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			ResourceNames: sets.List(names),
+			Verbs:         []string{"get"},
+		}}
+		return nil
+	}); err != nil {
+		return err
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, binding, func() error {
+		binding.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: probeAgentRoleName}
+		binding.Subjects = []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: r.ProbeAgentGroup}}
+		return nil
+	})
+	return err
+}
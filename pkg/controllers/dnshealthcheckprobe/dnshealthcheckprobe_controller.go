@@ -2,18 +2,25 @@ package dnshealthcheckprobe
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
@@ -23,6 +30,14 @@ import (
 
 const (
 	DNSHealthCheckProbeFinalizer = "kuadrant.io/dns-health-check-probe"
+
+	// WebhookSigningKey is the key of the webhook signing Secrets that holds
+	// the HMAC key
+	WebhookSigningKey = "signingKey"
+
+	// agentSecretResyncPeriod is how often probe agents read the Secrets
+	// referenced by a probe again, as they can't watch them
+	agentSecretResyncPeriod = 5 * time.Minute
 )
 
 var (
@@ -33,11 +48,32 @@ type DNSHealthCheckProbeReconciler struct {
 	client.Client
 	HealthMonitor *health.Monitor
 	Queue         *health.QueuedProbeWorker
+	// EventRecorder, if set, records an Event when the probe becomes healthy
+	// or unhealthy
+	EventRecorder record.EventRecorder
+	// VantagePoint is set when running as a probe agent on a spoke cluster.
+	// The agent reports its results for the vantage point and leaves the
+	// lifecycle and overall health of the probe to the hub
+	VantagePoint string
+	// WebhookSender, if set, notifies the webhooks of the probes and the
+	// global Webhooks when a probe becomes healthy or unhealthy
+	WebhookSender *health.WebhookSender
+	// Webhooks are notified of the transitions of every probe
+	Webhooks []health.Webhook
+	// ProbeAgentGroup, if set, is the group of the probe agents granted
+	// access to the Secrets referenced by the probes, see reconcileAgentRBAC
+	ProbeAgentGroup string
+	// SecretReader, if set, reads the Secrets referenced by the probes
+	// instead of the Client. Secrets are only watched for their metadata, and
+	// probe agents are only allowed to get them by name, so both read them
+	// from the API server rather than caching every Secret
+	SecretReader client.Reader
 }
 
 // +kubebuilder:rbac:groups=kuadrant.io,resources=dnshealthcheckprobes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=kuadrant.io,resources=dnshealthcheckprobes/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=kuadrant.io,resources=dnshealthcheckprobes/finalizers,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *DNSHealthCheckProbeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
@@ -56,10 +92,24 @@ func (r *DNSHealthCheckProbeReconciler) Reconcile(ctx context.Context, req ctrl.
 
 	probeObj := previous.DeepCopy()
 
+	// When the probes are sharded, the probe is left to the replica that owns
+	// it, including its finalizer
+	if !r.HealthMonitor.Owns(probeId(probeObj)) {
+		logger.V(3).Info("probe owned by another shard")
+		r.deleteProbe(probeObj)
+		return ctrl.Result{}, nil
+	}
+
 	if probeObj.DeletionTimestamp != nil && !probeObj.DeletionTimestamp.IsZero() {
 		logger.Info("deleting probe", "probe", probeObj)
 
 		r.deleteProbe(probeObj)
+		if r.VantagePoint != "" {
+			return ctrl.Result{}, nil
+		}
+		if err := r.reconcileAgentRBAC(ctx, probeObj.Namespace); err != nil {
+			return ctrl.Result{}, err
+		}
 		controllerutil.RemoveFinalizer(probeObj, DNSHealthCheckProbeFinalizer)
 
 		if err := r.Update(ctx, probeObj); err != nil {
@@ -69,12 +119,17 @@ func (r *DNSHealthCheckProbeReconciler) Reconcile(ctx context.Context, req ctrl.
 		return ctrl.Result{}, nil
 	}
 
-	if !controllerutil.ContainsFinalizer(probeObj, DNSHealthCheckProbeFinalizer) {
+	if r.VantagePoint == "" && !controllerutil.ContainsFinalizer(probeObj, DNSHealthCheckProbeFinalizer) {
 		controllerutil.AddFinalizer(probeObj, DNSHealthCheckProbeFinalizer)
 		if err := r.Update(ctx, probeObj); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
+	if r.VantagePoint == "" {
+		if err := r.reconcileAgentRBAC(ctx, probeObj.Namespace); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 
 	// Set the interval
 	interval := probeObj.Spec.Interval.Duration
@@ -87,9 +142,19 @@ func (r *DNSHealthCheckProbeReconciler) Reconcile(ctx context.Context, req ctrl.
 
 	protocol = v1alpha1.NewHealthProtocol(string(probeObj.Spec.Protocol))
 
+	var timeout time.Duration
+	if probeObj.Spec.Timeout != nil {
+		timeout = probeObj.Spec.Timeout.Duration
+	}
+
+	var certificateExpiryThreshold time.Duration
+	if probeObj.Spec.CertificateExpiryThreshold != nil {
+		certificateExpiryThreshold = probeObj.Spec.CertificateExpiryThreshold.Duration
+	}
+
 	probeId := probeId(probeObj)
 
-	additionalHeaders, err := getAdditionalHeaders(ctx, r.Client, probeObj)
+	additionalHeaders, err := getAdditionalHeaders(ctx, r.secretReader(), probeObj)
 
 	if err != nil {
 		f := false
@@ -97,19 +162,68 @@ func (r *DNSHealthCheckProbeReconciler) Reconcile(ctx context.Context, req ctrl.
 			"error getting additional headers for probe",
 			"secret name", probeObj.Spec.AdditionalHeadersRef.Name,
 			"error", err)
+		if r.VantagePoint != "" {
+			return ctrl.Result{}, err
+		}
+		//update probe status
+		probeObj.Status.Healthy = &f
+		probeObj.Status.LastCheckedAt = metav1.Now()
+		updateErr := r.Client.Status().Update(ctx, probeObj)
+		if updateErr != nil {
+			logger.V(1).Info("error updating probe status", "error", updateErr)
+		}
+		return ctrl.Result{}, err
+	}
+
+	tlsConfig, err := getTLSConfig(ctx, r.secretReader(), probeObj)
+	if err != nil {
+		f := false
+		logger.V(1).Info("error loading certificates for probe", "error", err)
+		if r.VantagePoint != "" {
+			return ctrl.Result{}, err
+		}
+		//update probe status
+		probeObj.Status.Healthy = &f
+		probeObj.Status.ConsecutiveFailures = 0
+		probeObj.Status.Reason = fmt.Sprintf("%s: %s", health.CertificateErrorReason, err.Error())
+		probeObj.Status.LastCheckedAt = metav1.Now()
+		updateErr := r.Client.Status().Update(ctx, probeObj)
+		if updateErr != nil {
+			logger.V(1).Info("error updating probe status", "error", updateErr)
+		}
+		return ctrl.Result{}, err
+	}
+
+	assertions, err := health.CompileAssertions(probeObj.Spec.ResponseAssertions)
+	if err != nil {
+		f := false
+		logger.V(1).Info("invalid response assertions for probe", "error", err)
+		r.HealthMonitor.RemoveProbe(probeId)
+		if r.VantagePoint != "" {
+			return ctrl.Result{}, nil
+		}
 		//update probe status
 		probeObj.Status.Healthy = &f
+		probeObj.Status.ConsecutiveFailures = 0
+		probeObj.Status.Reason = fmt.Sprintf("invalid response assertions: %s", err.Error())
 		probeObj.Status.LastCheckedAt = metav1.Now()
 		updateErr := r.Client.Status().Update(ctx, probeObj)
 		if updateErr != nil {
 			logger.V(1).Info("error updating probe status", "error", updateErr)
 		}
+		// the probe is reconciled again when its spec is fixed
+		return ctrl.Result{}, nil
+	}
+
+	notifier, err := r.newProbeNotifierFor(ctx, logger, previous)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
 
 	if r.HealthMonitor.HasProbe(probeId) {
 		r.HealthMonitor.UpdateProbe(probeId, func(p *health.ProbeQueuer) {
 			p.Interval = interval
+			p.Timeout = timeout
 			p.Host = probeObj.Spec.Host
 			p.IPAddress = probeObj.Spec.Address
 			p.Path = probeObj.Spec.Path
@@ -118,37 +232,149 @@ func (r *DNSHealthCheckProbeReconciler) Reconcile(ctx context.Context, req ctrl.
 			p.AdditionalHeaders = additionalHeaders
 			p.ExpectedResponses = probeObj.Spec.ExpectedResponses
 			p.AllowInsecureCertificate = probeObj.Spec.AllowInsecureCertificate
+			p.ServerName = probeObj.Spec.ServerName
+			p.CertificateExpiryThreshold = certificateExpiryThreshold
+			p.GRPCService = probeObj.Spec.GRPCService
+			p.GRPCPlaintext = probeObj.Spec.GRPCPlaintext
+			p.ResponseAssertions = assertions
+			p.TLSConfig = tlsConfig
+			p.Notifier = notifier
 		})
 	} else {
-		notifier, err := r.newProbeNotifierFor(ctx, logger, previous)
-		if err != nil {
-			return ctrl.Result{}, err
-		}
-
 		r.HealthMonitor.AddProbeQueuer(&health.ProbeQueuer{
-			ID:                       probeId,
-			Interval:                 interval,
-			Host:                     probeObj.Spec.Host,
-			Path:                     probeObj.Spec.Path,
-			Port:                     probeObj.Spec.Port,
-			Protocol:                 protocol,
-			IPAddress:                probeObj.Spec.Address,
-			AdditionalHeaders:        additionalHeaders,
-			ExpectedResponses:        probeObj.Spec.ExpectedResponses,
-			AllowInsecureCertificate: probeObj.Spec.AllowInsecureCertificate,
-			Notifier:                 notifier,
-			Queue:                    r.Queue,
+			ID:                         probeId,
+			Interval:                   interval,
+			Timeout:                    timeout,
+			Host:                       probeObj.Spec.Host,
+			Path:                       probeObj.Spec.Path,
+			Port:                       probeObj.Spec.Port,
+			Protocol:                   protocol,
+			IPAddress:                  probeObj.Spec.Address,
+			AdditionalHeaders:          additionalHeaders,
+			ExpectedResponses:          probeObj.Spec.ExpectedResponses,
+			AllowInsecureCertificate:   probeObj.Spec.AllowInsecureCertificate,
+			ServerName:                 probeObj.Spec.ServerName,
+			CertificateExpiryThreshold: certificateExpiryThreshold,
+			GRPCService:                probeObj.Spec.GRPCService,
+			GRPCPlaintext:              probeObj.Spec.GRPCPlaintext,
+			ResponseAssertions:         assertions,
+			TLSConfig:                  tlsConfig,
+			LastCheckedAt:              lastCheckedAt(previous, r.VantagePoint),
+			Notifier:                   notifier,
+			Queue:                      r.Queue,
 		})
 	}
 
+	if r.VantagePoint != "" && len(checkSecretNames(probeObj)) > 0 {
+		return ctrl.Result{RequeueAfter: agentSecretResyncPeriod}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
 // SetupWithManager sets up the controller with the manager
 func (r *DNSHealthCheckProbeReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.DNSHealthCheckProbe{}).
-		Complete(r)
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.DNSHealthCheckProbe{})
+
+	// Probe agents can't list Secrets, and read them again periodically
+	// instead. The hub only caches the metadata of the Secrets, and reads the
+	// referenced ones on demand
+	if r.VantagePoint == "" {
+		b = b.WatchesMetadata(&v1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.probesForSecret))
+	}
+
+	// Sharded probes are reconciled by every replica, and reassigned when
+	// replicas join or leave
+	if shard := r.HealthMonitor.Shard; shard != nil {
+		needLeaderElection := false
+		b = b.WithOptions(controller.Options{NeedLeaderElection: &needLeaderElection}).
+			WatchesRawSource(&source.Channel{Source: shard.Events()}, handler.EnqueueRequestsFromMapFunc(r.allProbes))
+	}
+
+	return b.Complete(r)
+}
+
+// allProbes maps a change of the shard members to every probe
+func (r *DNSHealthCheckProbeReconciler) allProbes(ctx context.Context, _ client.Object) []reconcile.Request {
+	probes := &v1alpha1.DNSHealthCheckProbeList{}
+	if err := r.Client.List(ctx, probes); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list probes to rebalance")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(probes.Items))
+	for _, probe := range probes.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&probe)})
+	}
+	return requests
+}
+
+// probesForSecret maps a Secret to the probes in its namespace that reference
+// it, so that headers, certificates and webhook keys are reloaded when it
+// changes
+func (r *DNSHealthCheckProbeReconciler) probesForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	probes := &v1alpha1.DNSHealthCheckProbeList{}
+	if err := r.Client.List(ctx, probes, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list probes for secret", "secret", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, probe := range probes.Items {
+		if slice.ContainsString(secretNames(&probe), obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&probe)})
+		}
+	}
+	return requests
+}
+
+// secretNames returns the names of the Secrets referenced by the probe
+func secretNames(probeObj *v1alpha1.DNSHealthCheckProbe) []string {
+	names := checkSecretNames(probeObj)
+	for _, webhook := range probeObj.Spec.Webhooks {
+		if webhook.SigningSecretRef != nil {
+			names = append(names, webhook.SigningSecretRef.Name)
+		}
+	}
+	return names
+}
+
+// checkSecretNames returns the names of the Secrets the probe is checked with,
+// which are read by the probe agents as well as the hub
+func checkSecretNames(probeObj *v1alpha1.DNSHealthCheckProbe) []string {
+	names := []string{}
+	if probeObj.Spec.AdditionalHeadersRef != nil {
+		names = append(names, probeObj.Spec.AdditionalHeadersRef.Name)
+	}
+	if probeObj.Spec.CACertificateRef != nil {
+		names = append(names, probeObj.Spec.CACertificateRef.Name)
+	}
+	if probeObj.Spec.ClientCertificateRef != nil {
+		names = append(names, probeObj.Spec.ClientCertificateRef.Name)
+	}
+	return names
+}
+
+func (r *DNSHealthCheckProbeReconciler) secretReader() client.Reader {
+	if r.SecretReader != nil {
+		return r.SecretReader
+	}
+	return r.Client
+}
+
+// lastCheckedAt returns when the probe was last checked from the vantage
+// point, as persisted in its status, so that a restarted controller or a new
+// leader keeps the schedule of the probe
+func lastCheckedAt(probeObj *v1alpha1.DNSHealthCheckProbe, vantagePoint string) time.Time {
+	if vantagePoint == "" {
+		return probeObj.Status.LastCheckedAt.Time
+	}
+	for _, status := range probeObj.Status.VantagePoints {
+		if status.Name == vantagePoint {
+			return status.LastCheckedAt.Time
+		}
+	}
+	return time.Time{}
 }
 
 func (r *DNSHealthCheckProbeReconciler) deleteProbe(probeObj *v1alpha1.DNSHealthCheckProbe) {
@@ -159,7 +385,7 @@ func probeId(probeObj *v1alpha1.DNSHealthCheckProbe) string {
 	return fmt.Sprintf("%s/%s", probeObj.Namespace, probeObj.Name)
 }
 
-func getAdditionalHeaders(ctx context.Context, clt client.Client, probeObj *v1alpha1.DNSHealthCheckProbe) (v1alpha1.AdditionalHeaders, error) {
+func getAdditionalHeaders(ctx context.Context, clt client.Reader, probeObj *v1alpha1.DNSHealthCheckProbe) (v1alpha1.AdditionalHeaders, error) {
 	additionalHeaders := v1alpha1.AdditionalHeaders{}
 
 	if probeObj.Spec.AdditionalHeadersRef != nil {
@@ -188,6 +414,50 @@ func getAdditionalHeaders(ctx context.Context, clt client.Client, probeObj *v1al
 	return additionalHeaders, nil
 }
 
+// getTLSConfig loads the CA bundle and client certificate referenced by the
+// probe. It returns nil when the probe references neither
+func getTLSConfig(ctx context.Context, clt client.Reader, probeObj *v1alpha1.DNSHealthCheckProbe) (*tls.Config, error) {
+	if probeObj.Spec.CACertificateRef == nil && probeObj.Spec.ClientCertificateRef == nil {
+		return nil, nil
+	}
+
+	var caBundle, clientCert, clientKey []byte
+	if probeObj.Spec.CACertificateRef != nil {
+		secret, err := getCertificateSecret(ctx, clt, probeObj.Namespace, probeObj.Spec.CACertificateRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		caBundle = secret.Data["ca.crt"]
+		if len(caBundle) == 0 {
+			return nil, fmt.Errorf("CA certificate secret '%s' has no ca.crt key", secret.Name)
+		}
+	}
+	if probeObj.Spec.ClientCertificateRef != nil {
+		secret, err := getCertificateSecret(ctx, clt, probeObj.Namespace, probeObj.Spec.ClientCertificateRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		clientCert = secret.Data[v1.TLSCertKey]
+		clientKey = secret.Data[v1.TLSPrivateKeyKey]
+		if len(clientCert) == 0 || len(clientKey) == 0 {
+			return nil, fmt.Errorf("client certificate secret '%s' must have %s and %s keys", secret.Name, v1.TLSCertKey, v1.TLSPrivateKeyKey)
+		}
+	}
+
+	return health.NewTLSConfig(caBundle, clientCert, clientKey)
+}
+
+func getCertificateSecret(ctx context.Context, clt client.Reader, namespace, name string) (*v1.Secret, error) {
+	secret := &v1.Secret{}
+	if err := clt.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, secret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("certificate secret '%s' not found", name)
+		}
+		return nil, fmt.Errorf("error retrieving certificate secret %v/%v: %w", namespace, name, err)
+	}
+	return secret, nil
+}
+
 func (r *DNSHealthCheckProbeReconciler) getGatewayFor(ctx context.Context, probe *v1alpha1.DNSHealthCheckProbe) (*gatewayapiv1.Gateway, bool, error) {
 	if probe.Labels == nil {
 		return nil, false, nil
@@ -214,29 +484,62 @@ func (r *DNSHealthCheckProbeReconciler) getGatewayFor(ctx context.Context, probe
 }
 
 func (r *DNSHealthCheckProbeReconciler) newProbeNotifierFor(ctx context.Context, logger logr.Logger, probe *v1alpha1.DNSHealthCheckProbe) (health.ProbeNotifier, error) {
+	if r.VantagePoint != "" {
+		logger.V(3).Info("creating vantage point notifier for probe", "vantagePoint", r.VantagePoint)
+		return NewVantagePointProbeNotifier(r.Client, probe, r.VantagePoint), nil
+	}
+
 	// Base notifier to update the probe CR
-	notifier := NewStatusUpdateProbeNotifier(r.Client, probe)
+	var notifier health.ProbeNotifier = NewStatusUpdateProbeNotifier(r.Client, r.EventRecorder, probe)
 
-	// Try to find the associated Gateway, if not fount, return the base
-	// notifier
-	gateway, ok, err := r.getGatewayFor(ctx, probe)
+	gateway, listener, ok, err := r.getListenerFor(ctx, logger, probe)
 	if err != nil {
 		return nil, err
 	}
+
+	payload := health.WebhookPayload{
+		Probe:   probeId(probe),
+		Address: probe.Spec.Address,
+	}
+	if ok {
+		payload.Gateway = fmt.Sprintf("%s/%s", gateway.Namespace, gateway.Name)
+		payload.Listener = string(listener.Name)
+	}
+	notifier = r.withWebhooks(ctx, logger, probe, payload, notifier)
+
 	if !ok {
-		logger.V(3).Info("no gateway associated to probe. Creating status update notifier")
 		return notifier, nil
 	}
 
-	// Try to find the associated DNSRecord, if not found, return the base
-	// notifier
+	logger.V(3).Info("creating instrumented probe notifier for probe")
+
+	// Wrap the notifier with the instrumented one that updates metrics
+	return health.NewInstrumentedProbeNotifier(
+		gateway.Name, gateway.Namespace, string(listener.Name), probe.Spec.Address,
+		notifier,
+	), nil
+}
+
+// getListenerFor finds the Gateway listener the probe checks, through the
+// Gateway labels and the DNSRecord annotations of the probe. It returns false
+// when any of them is not found
+func (r *DNSHealthCheckProbeReconciler) getListenerFor(ctx context.Context, logger logr.Logger, probe *v1alpha1.DNSHealthCheckProbe) (*gatewayapiv1.Gateway, gatewayapiv1.Listener, bool, error) {
+	gateway, ok, err := r.getGatewayFor(ctx, probe)
+	if err != nil {
+		return nil, gatewayapiv1.Listener{}, false, err
+	}
+	if !ok {
+		logger.V(3).Info("no gateway associated to probe")
+		return nil, gatewayapiv1.Listener{}, false, nil
+	}
+
 	dnsRecord, ok, err := getDNSRecord(ctx, r.Client, probe)
 	if err != nil {
-		return nil, err
+		return nil, gatewayapiv1.Listener{}, false, err
 	}
 	if !ok {
-		logger.V(3).Info("no DNSRecord associated to probe. Creating status update notifier")
-		return notifier, nil
+		logger.V(3).Info("no DNSRecord associated to probe")
+		return nil, gatewayapiv1.Listener{}, false, nil
 	}
 
 	// Find the listener in the Gateway that matches the DNSRecord
@@ -244,17 +547,49 @@ func (r *DNSHealthCheckProbeReconciler) newProbeNotifierFor(ctx context.Context,
 		dnsRecordName := fmt.Sprintf("%s-%s", gateway.Name, listener.Name)
 		return dnsRecord.Name == dnsRecordName
 	})
-	if !ok {
-		return notifier, nil
+	return gateway, listener, ok, nil
+}
+
+// withWebhooks wraps the notifier with one that calls the global webhooks and
+// the webhooks of the probe. Webhooks whose signing key can't be loaded are
+// left out until their Secret changes
+func (r *DNSHealthCheckProbeReconciler) withWebhooks(ctx context.Context, logger logr.Logger, probe *v1alpha1.DNSHealthCheckProbe, payload health.WebhookPayload, notifier health.ProbeNotifier) health.ProbeNotifier {
+	if r.WebhookSender == nil {
+		return notifier
 	}
 
-	logger.V(3).Info("creating instrumented probe notifier for probe")
+	webhooks := append([]health.Webhook{}, r.Webhooks...)
+	for _, probeWebhook := range probe.Spec.Webhooks {
+		webhook, err := getWebhook(ctx, r.secretReader(), probe.Namespace, probeWebhook)
+		if err != nil {
+			logger.Error(err, "skipping probe webhook", "url", probeWebhook.URL)
+			continue
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if len(webhooks) == 0 {
+		return notifier
+	}
 
-	// Wrap the base notifier with the instrumented one that updates metrics
-	return health.NewInstrumentedProbeNotifier(
-		gateway.Name, gateway.Namespace, string(listener.Name),
-		notifier,
-	), nil
+	return health.NewWebhookProbeNotifier(r.WebhookSender, webhooks, payload, notifier)
+}
+
+// getWebhook loads the signing key of the webhook from its Secret
+func getWebhook(ctx context.Context, clt client.Reader, namespace string, probeWebhook v1alpha1.ProbeWebhook) (health.Webhook, error) {
+	webhook := health.Webhook{URL: probeWebhook.URL}
+	if probeWebhook.SigningSecretRef == nil {
+		return webhook, nil
+	}
+
+	secret := &v1.Secret{}
+	if err := clt.Get(ctx, client.ObjectKey{Name: probeWebhook.SigningSecretRef.Name, Namespace: namespace}, secret); err != nil {
+		return webhook, fmt.Errorf("error retrieving webhook signing secret %v/%v: %w", namespace, probeWebhook.SigningSecretRef.Name, err)
+	}
+	webhook.Key = secret.Data[WebhookSigningKey]
+	if len(webhook.Key) == 0 {
+		return webhook, fmt.Errorf("webhook signing secret '%s' has no %s key", secret.Name, WebhookSigningKey)
+	}
+	return webhook, nil
 }
 
 func getDNSRecord(ctx context.Context, apiClient client.Client, obj metav1.Object) (*v1alpha1.DNSRecord, bool, error) {
@@ -3,12 +3,16 @@ package dnshealthcheckprobe
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
@@ -25,6 +29,10 @@ func testScheme(t *testing.T) *runtime.Scheme {
 		t.Fatalf("failed to add work scheme %s ", err)
 	}
 
+	if err := rbacv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add rbac scheme %s ", err)
+	}
+
 	return scheme
 }
 
@@ -263,3 +271,174 @@ func TestGetAdditionalHeaders(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTLSConfig(t *testing.T) {
+	probe := func(caRef, clientRef string) *v1alpha1.DNSHealthCheckProbe {
+		p := &v1alpha1.DNSHealthCheckProbe{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "probe",
+				Namespace: "default",
+			},
+		}
+		if caRef != "" {
+			p.Spec.CACertificateRef = &v1alpha1.CertificateRef{Name: caRef}
+		}
+		if clientRef != "" {
+			p.Spec.ClientCertificateRef = &v1alpha1.CertificateRef{Name: clientRef}
+		}
+		return p
+	}
+	secret := func(name string, data map[string][]byte) *v1.Secret {
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Data: data,
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		probe       *v1alpha1.DNSHealthCheckProbe
+		expectError string
+	}{
+		{
+			name:  "no config without references",
+			probe: probe("", ""),
+		},
+		{
+			name:        "error when CA secret is missing",
+			probe:       probe("missing", ""),
+			expectError: "certificate secret 'missing' not found",
+		},
+		{
+			name:        "error when CA secret has no ca.crt",
+			probe:       probe("client-cert", ""),
+			expectError: "CA certificate secret 'client-cert' has no ca.crt key",
+		},
+		{
+			name:        "error when CA bundle is invalid",
+			probe:       probe("ca", ""),
+			expectError: "no valid PEM certificates found in CA bundle",
+		},
+		{
+			name:        "error when client certificate secret has no key",
+			probe:       probe("", "ca"),
+			expectError: "client certificate secret 'ca' must have tls.crt and tls.key keys",
+		},
+		{
+			name:        "error when client certificate is invalid",
+			probe:       probe("", "client-cert"),
+			expectError: "invalid client certificate",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			f := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(
+				testCase.probe,
+				secret("ca", map[string][]byte{"ca.crt": []byte("not a certificate")}),
+				secret("client-cert", map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")}),
+			).Build()
+
+			config, err := getTLSConfig(context.TODO(), f, testCase.probe)
+			if testCase.expectError == "" {
+				if err != nil || config != nil {
+					t.Fatalf("expected no config and no error, got %v, %v", config, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), testCase.expectError) {
+				t.Fatalf("expected error containing %q, got %v", testCase.expectError, err)
+			}
+		})
+	}
+}
+
+func TestLastCheckedAt(t *testing.T) {
+	hubCheckedAt := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	agentCheckedAt := metav1.NewTime(hubCheckedAt.Add(5 * time.Second))
+	probe := &v1alpha1.DNSHealthCheckProbe{
+		Status: v1alpha1.DNSHealthCheckProbeStatus{
+			LastCheckedAt: hubCheckedAt,
+			VantagePoints: []v1alpha1.VantagePointStatus{
+				{Name: HubVantagePoint, LastCheckedAt: hubCheckedAt},
+				{Name: "eu", LastCheckedAt: agentCheckedAt},
+			},
+		},
+	}
+
+	if checkedAt := lastCheckedAt(probe, ""); !checkedAt.Equal(hubCheckedAt.Time) {
+		t.Errorf("expected hub to restore %s, got %s", hubCheckedAt, checkedAt)
+	}
+	if checkedAt := lastCheckedAt(probe, "eu"); !checkedAt.Equal(agentCheckedAt.Time) {
+		t.Errorf("expected agent to restore %s, got %s", agentCheckedAt, checkedAt)
+	}
+	if checkedAt := lastCheckedAt(probe, "us"); !checkedAt.IsZero() {
+		t.Errorf("expected new agent to check straight away, got %s", checkedAt)
+	}
+}
+
+func TestReconcileAgentRBAC(t *testing.T) {
+	probe := func(name string, headers string, deleting bool) *v1alpha1.DNSHealthCheckProbe {
+		p := &v1alpha1.DNSHealthCheckProbe{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		}
+		if headers != "" {
+			p.Spec.AdditionalHeadersRef = &v1alpha1.AdditionalHeadersRef{Name: headers}
+		}
+		if deleting {
+			now := metav1.Now()
+			p.DeletionTimestamp = &now
+			p.Finalizers = []string{DNSHealthCheckProbeFinalizer}
+		}
+		return p
+	}
+
+	r := &DNSHealthCheckProbeReconciler{
+		Client: fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(
+			probe("a", "headers-a", false),
+			probe("b", "headers-b", true),
+			probe("c", "", false),
+			&v1alpha1.DNSHealthCheckProbe{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other"},
+				Spec: v1alpha1.DNSHealthCheckProbeSpec{
+					AdditionalHeadersRef: &v1alpha1.AdditionalHeadersRef{Name: "headers-other"},
+				},
+			},
+		).Build(),
+		ProbeAgentGroup: ProbeAgentGroup,
+	}
+
+	if err := r.reconcileAgentRBAC(context.TODO(), "test"); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	role := &rbacv1.Role{}
+	if err := r.Client.Get(context.TODO(), client.ObjectKey{Name: probeAgentRoleName, Namespace: "test"}, role); err != nil {
+		t.Fatalf("expected role, got %s", err)
+	}
+	if len(role.Rules) != 1 || !slice.ContainsString(role.Rules[0].ResourceNames, "headers-a") || len(role.Rules[0].ResourceNames) != 1 {
+		t.Fatalf("expected role to grant only headers-a, got %v", role.Rules)
+	}
+	binding := &rbacv1.RoleBinding{}
+	if err := r.Client.Get(context.TODO(), client.ObjectKey{Name: probeAgentRoleName, Namespace: "test"}, binding); err != nil {
+		t.Fatalf("expected role binding, got %s", err)
+	}
+	if len(binding.Subjects) != 1 || binding.Subjects[0].Name != ProbeAgentGroup {
+		t.Fatalf("expected binding to the probe agent group, got %v", binding.Subjects)
+	}
+
+	if err := r.Client.Delete(context.TODO(), probe("a", "headers-a", false)); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := r.reconcileAgentRBAC(context.TODO(), "test"); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := r.Client.Get(context.TODO(), client.ObjectKey{Name: probeAgentRoleName, Namespace: "test"}, role); !k8serrors.IsNotFound(err) {
+		t.Fatalf("expected role to be removed, got %v", err)
+	}
+	if err := r.Client.Get(context.TODO(), client.ObjectKey{Name: probeAgentRoleName, Namespace: "test"}, binding); !k8serrors.IsNotFound(err) {
+		t.Fatalf("expected role binding to be removed, got %v", err)
+	}
+}
@@ -2,67 +2,307 @@ package dnshealthcheckprobe
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-
-	"k8s.io/apimachinery/pkg/api/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/health"
 )
 
+const (
+	// HubVantagePoint is the vantage point name used for the checks performed
+	// by the hub
+	HubVantagePoint = "hub"
+
+	// staleVantagePointIntervals is the number of probe intervals after which
+	// the result of a vantage point is left out of the quorum
+	staleVantagePointIntervals = 3
+
+	// recentResultsLimit is the number of results kept in the probe status
+	recentResultsLimit = 10
+
+	// HealthyEventReason and UnhealthyEventReason are the reasons of the
+	// Events recorded when the probe changes state
+	HealthyEventReason   = "Healthy"
+	UnhealthyEventReason = "Unhealthy"
+)
+
 type StatusUpdateProbeNotifier struct {
 	apiClient   client.Client
+	recorder    record.EventRecorder
 	probeObjKey client.ObjectKey
 }
 
 var _ health.ProbeNotifier = StatusUpdateProbeNotifier{}
 
-func NewStatusUpdateProbeNotifier(apiClient client.Client, forObj *v1alpha1.DNSHealthCheckProbe) StatusUpdateProbeNotifier {
+// NewStatusUpdateProbeNotifier creates a notifier that records results in the
+// probe status. recorder is optional, when set an Event is recorded every time
+// the probe becomes healthy or unhealthy
+func NewStatusUpdateProbeNotifier(apiClient client.Client, recorder record.EventRecorder, forObj *v1alpha1.DNSHealthCheckProbe) StatusUpdateProbeNotifier {
 	return StatusUpdateProbeNotifier{
 		apiClient:   apiClient,
+		recorder:    recorder,
 		probeObjKey: client.ObjectKeyFromObject(forObj),
 	}
 }
 
+// Notify records the result in the latest version of the probe status. The
+// probe agents write their vantage points to the same status, so a conflicting
+// write records the result again in the probe read back, rather than checking
+// the probe again
 func (n StatusUpdateProbeNotifier) Notify(ctx context.Context, result health.ProbeResult) (health.NotificationResult, error) {
-	probeObj := &v1alpha1.DNSHealthCheckProbe{}
-	if err := n.apiClient.Get(ctx, n.probeObjKey, probeObj); err != nil {
+	var probeObj *v1alpha1.DNSHealthCheckProbe
+	var wasHealthy *bool
+	err := updateStatus(ctx, n.apiClient, n.probeObjKey, func(latest *v1alpha1.DNSHealthCheckProbe) {
+		probeObj = latest
+		wasHealthy = latest.Status.Healthy
+		updateProbeStatus(latest, result)
+	})
+	if err != nil {
 		return health.NotificationResult{}, err
 	}
 
-	// Increase the number of consecutive failures if it failed previously
-	if !result.Healthy {
-		probeHealthy := true
-		if probeObj.Status.Healthy != nil {
-			probeHealthy = *probeObj.Status.Healthy
-		}
-		if probeHealthy {
-			probeObj.Status.ConsecutiveFailures = 1
-		} else {
-			probeObj.Status.ConsecutiveFailures++
+	transition := healthTransition(wasHealthy, probeObj.Status)
+	n.recordTransition(probeObj, transition)
+
+	return health.NotificationResult{Transition: transition}, nil
+}
+
+// healthTransition returns the transition when the health of the probe
+// changed. The first result of a probe is only a transition when it is
+// unhealthy
+func healthTransition(wasHealthy *bool, status v1alpha1.DNSHealthCheckProbeStatus) *health.Transition {
+	healthy := *status.Healthy
+	if wasHealthy == nil && healthy || wasHealthy != nil && *wasHealthy == healthy {
+		return nil
+	}
+
+	return &health.Transition{
+		WasHealthy: wasHealthy,
+		Healthy:    healthy,
+		Reason:     status.Reason,
+	}
+}
+
+// recordTransition records an Event when the health of the probe changed
+func (n StatusUpdateProbeNotifier) recordTransition(probeObj *v1alpha1.DNSHealthCheckProbe, transition *health.Transition) {
+	if n.recorder == nil || transition == nil {
+		return
+	}
+
+	if transition.Healthy {
+		n.recorder.Eventf(probeObj, corev1.EventTypeNormal, HealthyEventReason, "%s is healthy", probeObj.Spec.Address)
+		return
+	}
+	n.recorder.Eventf(probeObj, corev1.EventTypeWarning, UnhealthyEventReason, "%s is unhealthy: %s", probeObj.Spec.Address, transition.Reason)
+}
+
+// VantagePointProbeNotifier records the results of a probe agent running on a
+// spoke cluster in the vantage points of the probe status. The hub takes them
+// into account the next time it checks the probe
+type VantagePointProbeNotifier struct {
+	apiClient    client.Client
+	probeObjKey  client.ObjectKey
+	vantagePoint string
+}
+
+var _ health.ProbeNotifier = VantagePointProbeNotifier{}
+
+func NewVantagePointProbeNotifier(apiClient client.Client, forObj *v1alpha1.DNSHealthCheckProbe, vantagePoint string) VantagePointProbeNotifier {
+	return VantagePointProbeNotifier{
+		apiClient:    apiClient,
+		probeObjKey:  client.ObjectKeyFromObject(forObj),
+		vantagePoint: vantagePoint,
+	}
+}
+
+// Notify records the result as the vantage point of the agent in the latest
+// version of the probe status, leaving the other vantage points as written by
+// the hub and the other agents
+func (n VantagePointProbeNotifier) Notify(ctx context.Context, result health.ProbeResult) (health.NotificationResult, error) {
+	err := updateStatus(ctx, n.apiClient, n.probeObjKey, func(latest *v1alpha1.DNSHealthCheckProbe) {
+		setVantagePointStatus(&latest.Status, n.vantagePoint, result)
+	})
+	return health.NotificationResult{}, err
+}
+
+// updateStatus applies update to the latest version of the probe and writes
+// its status. A write conflicting with another writer reads the probe again
+// and retries the update with backoff
+func updateStatus(ctx context.Context, apiClient client.Client, key client.ObjectKey, update func(*v1alpha1.DNSHealthCheckProbe)) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		probeObj := &v1alpha1.DNSHealthCheckProbe{}
+		if err := apiClient.Get(ctx, key, probeObj); err != nil {
+			return err
 		}
+		update(probeObj)
+		return apiClient.Status().Update(ctx, probeObj)
+	})
+}
+
+// updateProbeStatus records the result in the probe status. The result is
+// first recorded as the hub vantage point and combined with the results of the
+// probe agents, see quorumResult. The last recentResultsLimit results of the
+// hub are kept in RecentResults. A failed check
+// marks the probe unhealthy straight away, but an unhealthy probe only
+// recovers after SuccessThreshold consecutive successful checks. When
+// FlapDamping is set and the probe changes state too often within the window,
+// it is held unhealthy until the cool-down has passed
+func updateProbeStatus(probeObj *v1alpha1.DNSHealthCheckProbe, result health.ProbeResult) {
+	status := &probeObj.Status
+	now := metav1.NewTime(result.CheckedAt)
+
+	addRecentResult(status, result)
+	setVantagePointStatus(status, HubVantagePoint, result)
+	result = quorumResult(probeObj, result)
+
+	wasHealthy := true
+	if status.Healthy != nil {
+		wasHealthy = *status.Healthy
+	}
+
+	if result.Healthy {
+		status.ConsecutiveSuccesses++
+		status.ConsecutiveFailures = 0
 	} else {
-		probeObj.Status.ConsecutiveFailures = 0
+		status.ConsecutiveSuccesses = 0
+		status.ConsecutiveFailures++
 	}
 
-	probeObj.Status.LastCheckedAt = metav1.NewTime(result.CheckedAt)
-	if probeObj.Status.Healthy == nil {
-		probeObj.Status.Healthy = aws.Bool(true)
+	successThreshold := 1
+	if probeObj.Spec.SuccessThreshold != nil {
+		successThreshold = *probeObj.Spec.SuccessThreshold
 	}
-	probeObj.Status.Healthy = &result.Healthy
-	probeObj.Status.Reason = result.Reason
-	probeObj.Status.Status = result.Status
+	healthy := result.Healthy && (wasHealthy || status.ConsecutiveSuccesses >= successThreshold)
 
-	if err := n.apiClient.Status().Update(ctx, probeObj); err != nil {
-		if errors.IsConflict(err) {
-			return health.NotificationResult{Requeue: true}, nil
+	damping := probeObj.Spec.FlapDamping
+	if damping == nil || (status.DampedUntil != nil && !now.Before(status.DampedUntil)) {
+		status.DampedUntil = nil
+	}
+	if damping == nil {
+		status.RecentTransitions = nil
+	}
+
+	if status.DampedUntil != nil {
+		healthy = false
+	} else if damping != nil && status.Healthy != nil && healthy != wasHealthy {
+		status.RecentTransitions = append(recentTransitions(status.RecentTransitions, now, damping.Window.Duration), now)
+		if len(status.RecentTransitions) >= damping.Transitions {
+			dampedUntil := metav1.NewTime(now.Add(damping.CoolDown.Duration))
+			status.DampedUntil = &dampedUntil
+			status.RecentTransitions = nil
+			healthy = false
 		}
+	}
 
-		return health.NotificationResult{}, err
+	if status.Healthy == nil || healthy != wasHealthy {
+		status.LastTransitionTime = &now
+	}
+
+	status.Reason = result.Reason
+	if status.DampedUntil != nil {
+		status.Reason = fmt.Sprintf("flapping, held unhealthy until %s", status.DampedUntil.UTC().Format(time.RFC3339))
+		if result.Reason != "" {
+			status.Reason = fmt.Sprintf("%s: %s", status.Reason, result.Reason)
+		}
+	}
+
+	status.LastCheckedAt = now
+	status.Healthy = &healthy
+	status.Status = result.Status
+}
+
+// recentTransitions returns the transitions that happened within window of now
+func recentTransitions(transitions []metav1.Time, now metav1.Time, window time.Duration) []metav1.Time {
+	recent := []metav1.Time{}
+	for _, t := range transitions {
+		if now.Sub(t.Time) < window {
+			recent = append(recent, t)
+		}
+	}
+	return recent
+}
+
+// addRecentResult appends result to the recent results, dropping the oldest
+// ones beyond recentResultsLimit
+func addRecentResult(status *v1alpha1.DNSHealthCheckProbeStatus, result health.ProbeResult) {
+	status.RecentResults = append(status.RecentResults, v1alpha1.ProbeResult{
+		CheckedAt: metav1.NewTime(result.CheckedAt),
+		Healthy:   result.Healthy,
+		Status:    result.Status,
+		Latency:   metav1.Duration{Duration: result.Latency},
+		Reason:    result.Reason,
+	})
+	if len(status.RecentResults) > recentResultsLimit {
+		status.RecentResults = status.RecentResults[len(status.RecentResults)-recentResultsLimit:]
+	}
+}
+
+// setVantagePointStatus records result as the latest result of the named
+// vantage point
+func setVantagePointStatus(status *v1alpha1.DNSHealthCheckProbeStatus, name string, result health.ProbeResult) {
+	vantagePoint := v1alpha1.VantagePointStatus{
+		Name:          name,
+		LastCheckedAt: metav1.NewTime(result.CheckedAt),
+		Healthy:       result.Healthy,
+		Reason:        result.Reason,
+		Status:        result.Status,
+	}
+
+	for i := range status.VantagePoints {
+		if status.VantagePoints[i].Name == name {
+			status.VantagePoints[i] = vantagePoint
+			return
+		}
+	}
+	status.VantagePoints = append(status.VantagePoints, vantagePoint)
+}
+
+// quorumResult combines the latest result of every vantage point into the
+// result of the hub check. The address is unhealthy when at least
+// VantagePointQuorum vantage points report it unhealthy, by default a majority
+// of the vantage points reporting. The quorum is capped
+// to the number of vantage points reporting, so that a probe without agents
+// behaves as if checked by the hub only. Results older than
+// staleVantagePointIntervals intervals are ignored
+func quorumResult(probeObj *v1alpha1.DNSHealthCheckProbe, result health.ProbeResult) health.ProbeResult {
+	if len(probeObj.Status.VantagePoints) < 2 {
+		return result
+	}
+
+	staleAfter := staleVantagePointIntervals * probeObj.Spec.Interval.Duration
+
+	reporting := 0
+	unhealthy := []string{}
+	for _, vantagePoint := range probeObj.Status.VantagePoints {
+		if staleAfter > 0 && result.CheckedAt.Sub(vantagePoint.LastCheckedAt.Time) > staleAfter {
+			continue
+		}
+		reporting++
+		if !vantagePoint.Healthy {
+			unhealthy = append(unhealthy, vantagePoint.Name)
+		}
+	}
+	quorum := reporting/2 + 1
+	if probeObj.Spec.VantagePointQuorum != nil {
+		quorum = *probeObj.Spec.VantagePointQuorum
+	}
+	if quorum > reporting {
+		quorum = reporting
+	}
+
+	result.Healthy = len(unhealthy) < quorum
+	result.Reason = ""
+	if len(unhealthy) > 0 {
+		result.Reason = fmt.Sprintf("unhealthy from %d of %d vantage points (%s), quorum is %d", len(unhealthy), reporting, strings.Join(unhealthy, ", "), quorum)
 	}
 
-	return health.NotificationResult{}, nil
+	return result
 }
@@ -0,0 +1,358 @@
+package dnshealthcheckprobe
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/health"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func TestUpdateProbeStatus(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	interval := 10 * time.Second
+
+	testCases := []struct {
+		name     string
+		spec     v1alpha1.DNSHealthCheckProbeSpec
+		results  []bool
+		expected []bool
+		verify   func(t *testing.T, status v1alpha1.DNSHealthCheckProbeStatus)
+	}{
+		{
+			name:     "recovers on first success by default",
+			results:  []bool{true, false, true},
+			expected: []bool{true, false, true},
+			verify: func(t *testing.T, status v1alpha1.DNSHealthCheckProbeStatus) {
+				if status.ConsecutiveSuccesses != 1 || status.ConsecutiveFailures != 0 {
+					t.Errorf("expected 1 success and 0 failures, got %d and %d", status.ConsecutiveSuccesses, status.ConsecutiveFailures)
+				}
+				if !status.LastTransitionTime.Time.Equal(start.Add(2 * interval)) {
+					t.Errorf("expected last transition at %s, got %s", start.Add(2*interval), status.LastTransitionTime)
+				}
+			},
+		},
+		{
+			name:     "recovers after success threshold",
+			spec:     v1alpha1.DNSHealthCheckProbeSpec{SuccessThreshold: testutil.Pointer(3)},
+			results:  []bool{false, false, true, true, false, true, true, true},
+			expected: []bool{false, false, false, false, false, false, false, true},
+			verify: func(t *testing.T, status v1alpha1.DNSHealthCheckProbeStatus) {
+				if status.ConsecutiveSuccesses != 3 {
+					t.Errorf("expected 3 consecutive successes, got %d", status.ConsecutiveSuccesses)
+				}
+			},
+		},
+		{
+			name:     "healthy probe fails straight away",
+			spec:     v1alpha1.DNSHealthCheckProbeSpec{SuccessThreshold: testutil.Pointer(3)},
+			results:  []bool{true, false},
+			expected: []bool{true, false},
+		},
+		{
+			name: "flapping probe is held unhealthy for the cool-down",
+			spec: v1alpha1.DNSHealthCheckProbeSpec{FlapDamping: &v1alpha1.FlapDamping{
+				Transitions: 3,
+				Window:      metav1.Duration{Duration: time.Minute},
+				CoolDown:    metav1.Duration{Duration: 45 * time.Second},
+			}},
+			// damped by the third transition at 30s until 75s
+			results:  []bool{true, false, true, false, true, true, true, true, true},
+			expected: []bool{true, false, true, false, false, false, false, false, true},
+			verify: func(t *testing.T, status v1alpha1.DNSHealthCheckProbeStatus) {
+				if status.DampedUntil != nil {
+					t.Errorf("expected damping to be cleared, got %s", status.DampedUntil)
+				}
+				if len(status.RecentTransitions) != 1 {
+					t.Errorf("expected 1 recent transition, got %v", status.RecentTransitions)
+				}
+			},
+		},
+		{
+			name: "transitions outside of the window are forgotten",
+			spec: v1alpha1.DNSHealthCheckProbeSpec{FlapDamping: &v1alpha1.FlapDamping{
+				Transitions: 3,
+				Window:      metav1.Duration{Duration: 15 * time.Second},
+				CoolDown:    metav1.Duration{Duration: time.Minute},
+			}},
+			results:  []bool{true, false, true, false, true},
+			expected: []bool{true, false, true, false, true},
+			verify: func(t *testing.T, status v1alpha1.DNSHealthCheckProbeStatus) {
+				if len(status.RecentTransitions) != 2 {
+					t.Errorf("expected 2 recent transitions, got %v", status.RecentTransitions)
+				}
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			probe := &v1alpha1.DNSHealthCheckProbe{Spec: testCase.spec}
+
+			for i, result := range testCase.results {
+				updateProbeStatus(probe, health.ProbeResult{
+					CheckedAt: start.Add(time.Duration(i) * interval),
+					Healthy:   result,
+				})
+				if *probe.Status.Healthy != testCase.expected[i] {
+					t.Fatalf("check %d: expected healthy to be %v, got %v", i, testCase.expected[i], *probe.Status.Healthy)
+				}
+				if probe.Status.DampedUntil != nil && !strings.HasPrefix(probe.Status.Reason, "flapping") {
+					t.Errorf("check %d: expected flapping reason, got %q", i, probe.Status.Reason)
+				}
+			}
+
+			if testCase.verify != nil {
+				testCase.verify(t, probe.Status)
+			}
+		})
+	}
+}
+
+func TestUpdateProbeStatusQuorum(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	interval := metav1.Duration{Duration: 10 * time.Second}
+
+	agent := func(name string, healthy bool, age time.Duration) v1alpha1.VantagePointStatus {
+		return v1alpha1.VantagePointStatus{
+			Name:          name,
+			LastCheckedAt: metav1.NewTime(now.Add(-age)),
+			Healthy:       healthy,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		quorum        *int
+		vantagePoints []v1alpha1.VantagePointStatus
+		hubHealthy    bool
+		expected      bool
+	}{
+		{
+			name:       "hub only",
+			quorum:     testutil.Pointer(2),
+			hubHealthy: false,
+			expected:   false,
+		},
+		{
+			name:          "a single vantage point is below the default quorum",
+			vantagePoints: []v1alpha1.VantagePointStatus{agent("us", false, time.Second), agent("eu", true, time.Second)},
+			hubHealthy:    true,
+			expected:      true,
+		},
+		{
+			name:          "a majority of vantage points by default",
+			vantagePoints: []v1alpha1.VantagePointStatus{agent("us", false, time.Second), agent("eu", true, time.Second)},
+			hubHealthy:    false,
+			expected:      false,
+		},
+		{
+			name:          "below quorum",
+			quorum:        testutil.Pointer(2),
+			vantagePoints: []v1alpha1.VantagePointStatus{agent("us", true, time.Second), agent("eu", true, time.Second)},
+			hubHealthy:    false,
+			expected:      true,
+		},
+		{
+			name:          "quorum reached",
+			quorum:        testutil.Pointer(2),
+			vantagePoints: []v1alpha1.VantagePointStatus{agent("us", false, time.Second), agent("eu", true, time.Second)},
+			hubHealthy:    false,
+			expected:      false,
+		},
+		{
+			name:          "stale vantage points are ignored",
+			quorum:        testutil.Pointer(2),
+			vantagePoints: []v1alpha1.VantagePointStatus{agent("us", false, time.Minute), agent("eu", true, time.Second)},
+			hubHealthy:    false,
+			expected:      true,
+		},
+		{
+			name:          "quorum is capped to the reporting vantage points",
+			quorum:        testutil.Pointer(3),
+			vantagePoints: []v1alpha1.VantagePointStatus{agent("us", false, time.Second), agent("eu", false, time.Minute)},
+			hubHealthy:    false,
+			expected:      false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			probe := &v1alpha1.DNSHealthCheckProbe{
+				Spec: v1alpha1.DNSHealthCheckProbeSpec{
+					Interval:           interval,
+					VantagePointQuorum: testCase.quorum,
+				},
+				Status: v1alpha1.DNSHealthCheckProbeStatus{
+					VantagePoints: testCase.vantagePoints,
+				},
+			}
+
+			updateProbeStatus(probe, health.ProbeResult{
+				CheckedAt: now,
+				Healthy:   testCase.hubHealthy,
+				Reason:    "Status code: 503",
+			})
+
+			if *probe.Status.Healthy != testCase.expected {
+				t.Errorf("expected healthy to be %v, got %v (%s)", testCase.expected, *probe.Status.Healthy, probe.Status.Reason)
+			}
+			if len(probe.Status.VantagePoints) != len(testCase.vantagePoints)+1 {
+				t.Fatalf("expected hub vantage point to be recorded, got %v", probe.Status.VantagePoints)
+			}
+			hub := probe.Status.VantagePoints[len(probe.Status.VantagePoints)-1]
+			if hub.Name != HubVantagePoint || hub.Healthy != testCase.hubHealthy || hub.Reason != "Status code: 503" {
+				t.Errorf("unexpected hub vantage point %v", hub)
+			}
+		})
+	}
+}
+
+func TestUpdateProbeStatusRecentResults(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	probe := &v1alpha1.DNSHealthCheckProbe{}
+
+	for i := 0; i < recentResultsLimit+3; i++ {
+		updateProbeStatus(probe, health.ProbeResult{
+			CheckedAt: start.Add(time.Duration(i) * time.Second),
+			Healthy:   true,
+			Status:    200,
+			Latency:   time.Duration(i) * time.Millisecond,
+		})
+	}
+
+	if len(probe.Status.RecentResults) != recentResultsLimit {
+		t.Fatalf("expected %d recent results, got %d", recentResultsLimit, len(probe.Status.RecentResults))
+	}
+	oldest := probe.Status.RecentResults[0]
+	if !oldest.CheckedAt.Time.Equal(start.Add(3*time.Second)) || oldest.Latency.Duration != 3*time.Millisecond {
+		t.Errorf("expected oldest results to be dropped, got %v", oldest)
+	}
+	latest := probe.Status.RecentResults[recentResultsLimit-1]
+	if latest.Status != 200 || !latest.Healthy {
+		t.Errorf("unexpected latest result %v", latest)
+	}
+}
+
+func TestStatusUpdateProbeNotifierEvents(t *testing.T) {
+	probe := &v1alpha1.DNSHealthCheckProbe{
+		ObjectMeta: metav1.ObjectMeta{Name: "probe", Namespace: "default"},
+		Spec:       v1alpha1.DNSHealthCheckProbeSpec{Address: "172.0.0.1"},
+	}
+	apiClient := fake.NewClientBuilder().
+		WithScheme(testScheme(t)).
+		WithObjects(probe).
+		WithStatusSubresource(probe).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	notifier := NewStatusUpdateProbeNotifier(apiClient, recorder, probe)
+
+	transitions := 0
+	for _, healthy := range []bool{true, true, false, false, true} {
+		result, err := notifier.Notify(context.Background(), health.ProbeResult{
+			CheckedAt: time.Now(),
+			Healthy:   healthy,
+			Reason:    "Status code: 503",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if result.Transition != nil {
+			transitions++
+			if result.Transition.Healthy != healthy || result.Transition.WasHealthy == nil || *result.Transition.WasHealthy == healthy {
+				t.Errorf("unexpected transition %+v to healthy %v", result.Transition, healthy)
+			}
+		}
+	}
+	if transitions != 2 {
+		t.Errorf("expected 2 transitions, got %d", transitions)
+	}
+
+	expected := []string{
+		"Warning Unhealthy 172.0.0.1 is unhealthy: Status code: 503",
+		"Normal Healthy 172.0.0.1 is healthy",
+	}
+	for _, event := range expected {
+		select {
+		case recorded := <-recorder.Events:
+			if recorded != event {
+				t.Errorf("expected event %q, got %q", event, recorded)
+			}
+		default:
+			t.Fatalf("expected event %q, got none", event)
+		}
+	}
+	select {
+	case recorded := <-recorder.Events:
+		t.Errorf("unexpected event %q", recorded)
+	default:
+	}
+}
+
+func TestProbeNotifiersConflict(t *testing.T) {
+	probe := &v1alpha1.DNSHealthCheckProbe{
+		ObjectMeta: metav1.ObjectMeta{Name: "probe", Namespace: "default"},
+		Spec:       v1alpha1.DNSHealthCheckProbeSpec{Address: "172.0.0.1"},
+	}
+	base := fake.NewClientBuilder().
+		WithScheme(testScheme(t)).
+		WithObjects(probe).
+		WithStatusSubresource(probe).
+		Build()
+
+	// the first write of each notifier conflicts with an agent recording its
+	// vantage point in the meantime
+	agents := []string{"agent-a", "agent-b"}
+	writes := 0
+	apiClient := interceptor.NewClient(base, interceptor.Funcs{
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			writes++
+			if writes%2 == 1 {
+				agent := NewVantagePointProbeNotifier(base, probe, agents[writes/2])
+				if _, err := agent.Notify(ctx, health.ProbeResult{CheckedAt: time.Now(), Healthy: true}); err != nil {
+					t.Fatalf("unexpected error %s", err)
+				}
+			}
+			return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+		},
+	})
+
+	notifiers := []health.ProbeNotifier{
+		NewStatusUpdateProbeNotifier(apiClient, nil, probe),
+		NewVantagePointProbeNotifier(apiClient, probe, "agent-c"),
+	}
+	for _, notifier := range notifiers {
+		result, err := notifier.Notify(context.Background(), health.ProbeResult{CheckedAt: time.Now(), Healthy: true})
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if result.Requeue {
+			t.Errorf("expected a conflicting write not to requeue the probe")
+		}
+	}
+
+	updated := &v1alpha1.DNSHealthCheckProbe{}
+	if err := base.Get(context.Background(), client.ObjectKeyFromObject(probe), updated); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	names := []string{}
+	for _, vantagePoint := range updated.Status.VantagePoints {
+		names = append(names, vantagePoint.Name)
+	}
+	if !slices.Equal(names, []string{"agent-a", "hub", "agent-b", "agent-c"}) {
+		t.Errorf("expected the vantage point of every writer to be kept, got %v", names)
+	}
+	if len(updated.Status.RecentResults) != 1 {
+		t.Errorf("expected the hub result to be recorded once, got %v", updated.Status.RecentResults)
+	}
+}
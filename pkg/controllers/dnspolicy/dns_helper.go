@@ -13,6 +13,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -33,6 +34,7 @@ const (
 
 var (
 	ErrUnknownRoutingStrategy = fmt.Errorf("unknown routing strategy")
+	ErrSimpleStrategyTargets  = fmt.Errorf("the simple routing strategy needs IP addresses or a single hostname address, use the loadbalanced routing strategy")
 	ErrNoManagedZoneForHost   = fmt.Errorf("no managed zone for host")
 	ErrAlreadyAssigned        = fmt.Errorf("managed host already assigned")
 )
@@ -159,7 +161,11 @@ func (dh *dnsHelper) setEndpoints(ctx context.Context, mcgTarget *dns.MultiClust
 
 	switch strategy {
 	case v1alpha1.SimpleRoutingStrategy:
-		endpoints = dh.getSimpleEndpoints(mcgTarget, gwListenerHost, currentEndpoints)
+		var err error
+		endpoints, err = dh.getSimpleEndpoints(mcgTarget, gwListenerHost, currentEndpoints)
+		if err != nil {
+			return err
+		}
 	case v1alpha1.LoadBalancedRoutingStrategy:
 		endpoints = dh.getLoadBalancedEndpoints(mcgTarget, gwListenerHost, currentEndpoints)
 	default:
@@ -180,13 +186,16 @@ func (dh *dnsHelper) setEndpoints(ctx context.Context, mcgTarget *dns.MultiClust
 }
 
 // getSimpleEndpoints returns the endpoints for the given MultiClusterGatewayTarget using the simple routing strategy
-
-func (dh *dnsHelper) getSimpleEndpoints(mcgTarget *dns.MultiClusterGatewayTarget, hostname string, currentEndpoints map[string]*v1alpha1.Endpoint) []*v1alpha1.Endpoint {
+//
+// A single A record is created for the IP addresses of the gateways, or a single CNAME record for a hostname address.
+// A CNAME record can have only one target and can't share its name with other records, so gateways with several
+// hostname addresses, or with both IP and hostname addresses, need the loadbalanced routing strategy
+func (dh *dnsHelper) getSimpleEndpoints(mcgTarget *dns.MultiClusterGatewayTarget, hostname string, currentEndpoints map[string]*v1alpha1.Endpoint) ([]*v1alpha1.Endpoint, error) {
 
 	var (
 		endpoints  []*v1alpha1.Endpoint
 		ipValues   []string
-		hostValues []string
+		hostValues = sets.New[string]()
 	)
 
 	for _, cgwTarget := range mcgTarget.ClusterGatewayTargets {
@@ -194,23 +203,26 @@ func (dh *dnsHelper) getSimpleEndpoints(mcgTarget *dns.MultiClusterGatewayTarget
 			if *gwa.Type == gatewayapiv1.IPAddressType {
 				ipValues = append(ipValues, gwa.Value)
 			} else {
-				hostValues = append(hostValues, gwa.Value)
+				hostValues.Insert(gwa.Value)
 			}
 		}
 	}
 
+	if hostValues.Len() > 1 || (hostValues.Len() > 0 && len(ipValues) > 0) {
+		return nil, fmt.Errorf("%w : %v", ErrSimpleStrategyTargets, append(ipValues, sets.List(hostValues)...))
+	}
+
 	if len(ipValues) > 0 {
 		endpoint := createOrUpdateEndpoint(hostname, ipValues, v1alpha1.ARecordType, "", dns.DefaultTTL, currentEndpoints)
 		endpoints = append(endpoints, endpoint)
 	}
 
-	//ToDO This could possibly result in an invalid record since you can't have multiple CNAME target values https://github.com/Kuadrant/multicluster-gateway-controller/issues/663
-	if len(hostValues) > 0 {
-		endpoint := createOrUpdateEndpoint(hostname, hostValues, v1alpha1.CNAMERecordType, "", dns.DefaultTTL, currentEndpoints)
+	if hostValues.Len() > 0 {
+		endpoint := createOrUpdateEndpoint(hostname, sets.List(hostValues), v1alpha1.CNAMERecordType, "", dns.DefaultTTL, currentEndpoints)
 		endpoints = append(endpoints, endpoint)
 	}
 
-	return endpoints
+	return endpoints, nil
 }
 
 // getLoadBalancedEndpoints returns the endpoints for the given MultiClusterGatewayTarget using the loadbalanced routing strategy
@@ -386,6 +398,65 @@ func (dh *dnsHelper) getManagedZoneForListener(ctx context.Context, ns string, l
 	return mz, err
 }
 
+// publishedHostnames indexes the hostnames published into each ManagedZone, keyed by the ManagedZone and the hostname
+// and pointing at the Gateway whose DNSRecord publishes it.
+type publishedHostnames map[client.ObjectKey]map[string]client.ObjectKey
+
+// getPublishedHostnames builds the index of every hostname published into the ManagedZones of the given namespace. If
+// more than one gateway publishes the same hostname the gateway with the oldest DNSRecord is considered the owner.
+func (dh *dnsHelper) getPublishedHostnames(ctx context.Context, namespace string) (publishedHostnames, error) {
+	dnsList := &v1alpha1.DNSRecordList{}
+	if err := dh.List(ctx, dnsList, &client.ListOptions{Namespace: namespace}); err != nil {
+		return nil, err
+	}
+
+	records := slice.Filter(dnsList.Items, func(record v1alpha1.DNSRecord) bool {
+		return record.Spec.ManagedZoneRef != nil
+	})
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].CreationTimestamp.Equal(&records[j].CreationTimestamp) {
+			return records[i].Name < records[j].Name
+		}
+		return records[i].CreationTimestamp.Before(&records[j].CreationTimestamp)
+	})
+
+	published := publishedHostnames{}
+	for _, record := range records {
+		gwName, nameOk := record.Labels[LabelGatewayReference]
+		gwNamespace, nsOk := record.Labels[LabelGatewayNSRef]
+		if !nameOk || !nsOk {
+			continue
+		}
+		mz := client.ObjectKey{Name: record.Spec.ManagedZoneRef.Name, Namespace: record.Namespace}
+		for _, endpoint := range record.Spec.Endpoints {
+			published.publish(mz, endpoint.DNSName, client.ObjectKey{Name: gwName, Namespace: gwNamespace})
+		}
+	}
+	return published, nil
+}
+
+// publish records the hostname as published into the ManagedZone by the gateway, unless it's already published
+func (p publishedHostnames) publish(mz client.ObjectKey, host string, gateway client.ObjectKey) {
+	if p[mz] == nil {
+		p[mz] = map[string]client.ObjectKey{}
+	}
+	host = strings.ToLower(host)
+	if _, ok := p[mz][host]; !ok {
+		p[mz][host] = gateway
+	}
+}
+
+// checkHostnameConflict returns an ErrAlreadyAssigned error if the listener hostname is already published into the
+// ManagedZone by a DNSRecord belonging to a different gateway.
+func checkHostnameConflict(published publishedHostnames, gateway *gatewayapiv1.Gateway, listener gatewayapiv1.Listener, mz *v1alpha1.ManagedZone) error {
+	host := strings.ToLower(string(*listener.Hostname))
+	owner, ok := published[client.ObjectKeyFromObject(mz)][host]
+	if !ok || owner == client.ObjectKeyFromObject(gateway) {
+		return nil
+	}
+	return fmt.Errorf("%w : host %s in managed zone %s is published by gateway %s", ErrAlreadyAssigned, host, mz.Name, owner)
+}
+
 func dnsRecordName(gatewayName, listenerName string) string {
 	return fmt.Sprintf("%s-%s", gatewayName, listenerName)
 }
@@ -4,9 +4,11 @@ package dnspolicy
 
 import (
 	"context"
+	"errors"
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -1125,6 +1127,134 @@ func Test_dnsHelper_getDNSRecordForListener(t *testing.T) {
 
 }
 
+func Test_dnsHelper_checkHostnameConflict(t *testing.T) {
+	managedZone := &v1alpha1.ManagedZone{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "mz",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.ManagedZoneSpec{
+			DomainName: "domain.com",
+		},
+	}
+	recordFor := func(gatewayName, mzName, host string, created v1.Time) v1alpha1.DNSRecord {
+		return v1alpha1.DNSRecord{
+			ObjectMeta: v1.ObjectMeta{
+				Name:              dnsRecordName(gatewayName, "test"),
+				Namespace:         "test",
+				CreationTimestamp: created,
+				Labels: map[string]string{
+					LabelGatewayNSRef:      "test",
+					LabelGatewayReference:  gatewayName,
+					LabelListenerReference: "test",
+				},
+			},
+			Spec: v1alpha1.DNSRecordSpec{
+				ManagedZoneRef: &v1alpha1.ManagedZoneReference{
+					Name: mzName,
+				},
+				Endpoints: []*v1alpha1.Endpoint{
+					{
+						DNSName:    host,
+						Targets:    []string{"lb-a1b2." + host},
+						RecordType: "CNAME",
+					},
+				},
+			},
+		}
+	}
+	older := v1.NewTime(v1.Now().Add(-time.Hour))
+	newer := v1.Now()
+
+	testCases := []struct {
+		name       string
+		gateway    string
+		listener   gatewayapiv1.Listener
+		recordList *v1alpha1.DNSRecordList
+		wantErr    bool
+	}{
+		{
+			name:       "no conflict when host is not published",
+			gateway:    "gw1",
+			listener:   getTestListener("test.domain.com"),
+			recordList: &v1alpha1.DNSRecordList{},
+		},
+		{
+			name:     "no conflict when host is published by the same gateway",
+			gateway:  "gw1",
+			listener: getTestListener("test.domain.com"),
+			recordList: &v1alpha1.DNSRecordList{
+				Items: []v1alpha1.DNSRecord{recordFor("gw1", "mz", "test.domain.com", older)},
+			},
+		},
+		{
+			name:     "no conflict when host is published in a different managed zone",
+			gateway:  "gw1",
+			listener: getTestListener("test.domain.com"),
+			recordList: &v1alpha1.DNSRecordList{
+				Items: []v1alpha1.DNSRecord{recordFor("gw2", "other-mz", "test.domain.com", older)},
+			},
+		},
+		{
+			name:     "conflict when host is published by a different gateway",
+			gateway:  "gw1",
+			listener: getTestListener("test.domain.com"),
+			recordList: &v1alpha1.DNSRecordList{
+				Items: []v1alpha1.DNSRecord{recordFor("gw2", "mz", "test.domain.com", older)},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "oldest record wins when host is published by more than one gateway",
+			gateway:  "gw1",
+			listener: getTestListener("test.domain.com"),
+			recordList: &v1alpha1.DNSRecordList{
+				Items: []v1alpha1.DNSRecord{
+					recordFor("gw1", "mz", "test.domain.com", older),
+					recordFor("gw2", "mz", "test.domain.com", newer),
+				},
+			},
+		},
+		{
+			name:     "newest record loses when host is published by more than one gateway",
+			gateway:  "gw2",
+			listener: getTestListener("test.domain.com"),
+			recordList: &v1alpha1.DNSRecordList{
+				Items: []v1alpha1.DNSRecord{
+					recordFor("gw1", "mz", "test.domain.com", older),
+					recordFor("gw2", "mz", "test.domain.com", newer),
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			f := fake.NewClientBuilder().WithScheme(testScheme(t)).WithLists(testCase.recordList).Build()
+			s := dnsHelper{Client: f}
+			gateway := &gatewayapiv1.Gateway{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      testCase.gateway,
+					Namespace: "test",
+				},
+			}
+
+			published, err := s.getPublishedHostnames(context.TODO(), "test")
+			if err != nil {
+				t.Fatalf("getPublishedHostnames() error = %v", err)
+			}
+
+			err = checkHostnameConflict(published, gateway, testCase.listener, managedZone)
+			if (err != nil) != testCase.wantErr {
+				t.Fatalf("checkHostnameConflict() error = %v, wantErr %v", err, testCase.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrAlreadyAssigned) {
+				t.Errorf("checkHostnameConflict() error = %v, want %v", err, ErrAlreadyAssigned)
+			}
+		})
+	}
+}
+
 func assertSub(domain string, subdomain string, err string) func(t *testing.T, expectedzone *v1alpha1.ManagedZone, expectedsubdomain string, expectedErr error) {
 	return func(t *testing.T, expectedzone *v1alpha1.ManagedZone, expectedsubdomain string, expectedErr error) {
 		if (err == "") != (expectedErr == nil) {
@@ -1144,3 +1274,91 @@ func assertSub(domain string, subdomain string, err string) func(t *testing.T, e
 		}
 	}
 }
+
+func Test_dnsHelper_getSimpleEndpoints(t *testing.T) {
+	clusterTarget := func(cluster string, addresses ...gatewayapiv1.GatewayStatusAddress) dns.ClusterGatewayTarget {
+		return dns.ClusterGatewayTarget{
+			ClusterGateway: &utils.ClusterGateway{
+				Gateway: gatewayapiv1.Gateway{
+					ObjectMeta: v1.ObjectMeta{Name: "testgw"},
+					Status:     gatewayapiv1.GatewayStatus{Addresses: addresses},
+				},
+				ClusterName: cluster,
+			},
+		}
+	}
+	ip := func(value string) gatewayapiv1.GatewayStatusAddress {
+		return gatewayapiv1.GatewayStatusAddress{Type: testutil.Pointer(gatewayapiv1.IPAddressType), Value: value}
+	}
+	host := func(value string) gatewayapiv1.GatewayStatusAddress {
+		return gatewayapiv1.GatewayStatusAddress{Type: testutil.Pointer(gatewayapiv1.HostnameAddressType), Value: value}
+	}
+
+	testCases := []struct {
+		name    string
+		targets []dns.ClusterGatewayTarget
+		wantErr bool
+	}{
+		{
+			name: "IP addresses on several clusters",
+			targets: []dns.ClusterGatewayTarget{
+				clusterTarget("test-cluster-1", ip("1.1.1.1"), ip("2.2.2.2")),
+				clusterTarget("test-cluster-2", ip("3.3.3.3")),
+			},
+		},
+		{
+			name: "single hostname address",
+			targets: []dns.ClusterGatewayTarget{
+				clusterTarget("test-cluster-1", host("mylb.example.com")),
+			},
+		},
+		{
+			name: "same hostname address on several clusters",
+			targets: []dns.ClusterGatewayTarget{
+				clusterTarget("test-cluster-1", host("mylb.example.com")),
+				clusterTarget("test-cluster-2", host("mylb.example.com")),
+			},
+		},
+		{
+			name: "several hostname addresses",
+			targets: []dns.ClusterGatewayTarget{
+				clusterTarget("test-cluster-1", host("mylb.example.com")),
+				clusterTarget("test-cluster-2", host("otherlb.example.com")),
+			},
+			wantErr: true,
+		},
+		{
+			name: "IP and hostname addresses",
+			targets: []dns.ClusterGatewayTarget{
+				clusterTarget("test-cluster-1", ip("1.1.1.1")),
+				clusterTarget("test-cluster-2", host("mylb.example.com")),
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mcgTarget := &dns.MultiClusterGatewayTarget{ClusterGatewayTargets: testCase.targets}
+			endpoints, err := (&dnsHelper{}).getSimpleEndpoints(mcgTarget, "test.example.com", map[string]*v1alpha1.Endpoint{})
+			if testCase.wantErr {
+				if !errors.Is(err, ErrSimpleStrategyTargets) {
+					t.Fatalf("expected %s, got %v", ErrSimpleStrategyTargets, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			record := &v1alpha1.DNSRecord{
+				ObjectMeta: v1.ObjectMeta{Name: "test.example.com"},
+				Spec: v1alpha1.DNSRecordSpec{
+					ManagedZoneRef: &v1alpha1.ManagedZoneReference{Name: "example.com"},
+					Endpoints:      endpoints,
+				},
+			}
+			if err := record.Validate(); err != nil {
+				t.Errorf("expected a valid dns record, got %s", err)
+			}
+		})
+	}
+}
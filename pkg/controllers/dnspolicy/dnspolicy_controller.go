@@ -93,7 +93,7 @@ func (r *DNSPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 				if delResErr == nil {
 					delResErr = err
 				}
-				return r.reconcileStatus(ctx, dnsPolicy, fmt.Errorf("%w : %w", conditions.ErrTargetNotFound, delResErr))
+				return r.reconcileStatus(ctx, previous, dnsPolicy, fmt.Errorf("%w : %w", conditions.ErrTargetNotFound, delResErr))
 			}
 			return ctrl.Result{}, err
 		}
@@ -125,12 +125,18 @@ func (r *DNSPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 	specErr := r.reconcileResources(ctx, dnsPolicy, targetNetworkObject)
 
-	statusResult, statusErr := r.reconcileStatus(ctx, dnsPolicy, specErr)
+	statusResult, statusErr := r.reconcileStatus(ctx, previous, dnsPolicy, specErr)
 
 	if specErr != nil {
 		return ctrl.Result{}, specErr
 	}
 
+	// provider health checks are reconciled again on a period, to check the
+	// addresses the hostnames of the endpoints resolve to
+	if statusResult.IsZero() && dnsPolicy.Spec.HealthCheck != nil && dnsPolicy.Spec.HealthCheck.Mode.UsesProvider() {
+		statusResult.RequeueAfter = dns.HealthCheckResyncPeriod
+	}
+
 	return statusResult, statusErr
 }
 
@@ -152,7 +158,11 @@ func (r *DNSPolicyReconciler) reconcileResources(ctx context.Context, dnsPolicy
 	}
 
 	if err = r.reconcileDNSRecords(ctx, dnsPolicy, gatewayDiffObj); err != nil {
-		gatewayCondition = conditions.BuildPolicyAffectedCondition(DNSPolicyAffected, dnsPolicy, targetNetworkObject, conditions.PolicyReasonInvalid, err)
+		reason := conditions.PolicyReasonInvalid
+		if errors.Is(err, ErrAlreadyAssigned) {
+			reason = conditions.PolicyReasonConflicted
+		}
+		gatewayCondition = conditions.BuildPolicyAffectedCondition(DNSPolicyAffected, dnsPolicy, targetNetworkObject, reason, err)
 		updateErr := r.updateGatewayCondition(ctx, gatewayCondition, gatewayDiffObj)
 		return errors.Join(fmt.Errorf("reconcile DNSRecords error %w", err), updateErr)
 	}
@@ -218,10 +228,13 @@ func (r *DNSPolicyReconciler) deleteResources(ctx context.Context, dnsPolicy *v1
 	return r.updateGatewayCondition(ctx, metav1.Condition{Type: string(DNSPolicyAffected)}, gatewayDiffObj)
 }
 
-func (r *DNSPolicyReconciler) reconcileStatus(ctx context.Context, dnsPolicy *v1alpha1.DNSPolicy, specErr error) (ctrl.Result, error) {
+// reconcileStatus updates the status of dnsPolicy when it differs from the
+// status of previous. The reconciliation of the resources may have already
+// changed the status of dnsPolicy, such as the provider health checks
+func (r *DNSPolicyReconciler) reconcileStatus(ctx context.Context, previous, dnsPolicy *v1alpha1.DNSPolicy, specErr error) (ctrl.Result, error) {
 	newStatus := r.calculateStatus(dnsPolicy, specErr)
 
-	if !equality.Semantic.DeepEqual(newStatus, dnsPolicy.Status) {
+	if !equality.Semantic.DeepEqual(*newStatus, previous.Status) {
 		dnsPolicy.Status = *newStatus
 		updateErr := r.Client().Status().Update(ctx, dnsPolicy)
 		if updateErr != nil {
@@ -266,6 +279,10 @@ func (r *DNSPolicyReconciler) readyCondition(targetNetworkObjectectKind string,
 		if errors.Is(specErr, conditions.ErrTargetNotFound) {
 			cond.Reason = string(conditions.PolicyReasonTargetNotFound)
 		}
+
+		if errors.Is(specErr, ErrAlreadyAssigned) {
+			cond.Reason = string(conditions.PolicyReasonConflicted)
+		}
 	}
 
 	return cond
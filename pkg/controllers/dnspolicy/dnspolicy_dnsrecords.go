@@ -2,6 +2,7 @@ package dnspolicy
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -29,17 +30,27 @@ func (r *DNSPolicyReconciler) reconcileDNSRecords(ctx context.Context, dnsPolicy
 		}
 	}
 
+	published, err := r.dnsHelper.getPublishedHostnames(ctx, dnsPolicy.Namespace)
+	if err != nil {
+		return fmt.Errorf("error listing published hostnames: %w", err)
+	}
+
 	// Reconcile DNSRecords for each gateway directly referred by the policy (existing and new)
+	healthChecks := map[string]bool{}
 	for _, gw := range append(gwDiffObj.GatewaysWithValidPolicyRef, gwDiffObj.GatewaysMissingPolicyRef...) {
 		log.V(1).Info("reconcileDNSRecords: gateway with valid or missing policy ref", "key", gw.Key())
-		if err := r.reconcileGatewayDNSRecords(ctx, gw.Gateway, dnsPolicy); err != nil {
+		if err := r.reconcileGatewayDNSRecords(ctx, gw.Gateway, dnsPolicy, published, healthChecks); err != nil {
 			return fmt.Errorf("error reconciling dns records for gateway %v: %w", gw.Gateway.Name, err)
 		}
 	}
+	pruneHealthCheckConditions(dnsPolicy, healthChecks)
 	return nil
 }
 
-func (r *DNSPolicyReconciler) reconcileGatewayDNSRecords(ctx context.Context, gw *gatewayapiv1.Gateway, dnsPolicy *v1alpha1.DNSPolicy) error {
+// reconcileGatewayDNSRecords reconciles the DNSRecords of every listener of
+// the gateway whose hostname isn't published by another gateway, adding the
+// provider health checks reconciled to healthChecks
+func (r *DNSPolicyReconciler) reconcileGatewayDNSRecords(ctx context.Context, gw *gatewayapiv1.Gateway, dnsPolicy *v1alpha1.DNSPolicy, published publishedHostnames, healthChecks map[string]bool) error {
 	log := crlog.FromContext(ctx)
 
 	gatewayWrapper := utils.NewGatewayWrapper(gw)
@@ -56,6 +67,7 @@ func (r *DNSPolicyReconciler) reconcileGatewayDNSRecords(ctx context.Context, gw
 
 	log.V(3).Info("checking gateway for attached routes ", "gateway", gatewayWrapper.Name, "clusterGateways", clusterGateways)
 
+	var conflicts []error
 	for _, listener := range gatewayWrapper.Spec.Listeners {
 		var mz, err = r.dnsHelper.getManagedZoneForListener(ctx, gatewayWrapper.Namespace, listener)
 		if err != nil {
@@ -67,6 +79,15 @@ func (r *DNSPolicyReconciler) reconcileGatewayDNSRecords(ctx context.Context, gw
 			continue
 		}
 
+		if err := checkHostnameConflict(published, gatewayWrapper.Gateway, listener, mz); err != nil {
+			log.Info("skipping listener with conflicting hostname", "listener", listener.Name, "error", err)
+			if err := r.dnsHelper.deleteDNSRecordForListener(ctx, gatewayWrapper, listener); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to delete dns record for conflicting listener %s : %s", listener.Name, err)
+			}
+			conflicts = append(conflicts, err)
+			continue
+		}
+
 		listenerGateways := slice.Filter(clusterGateways, func(cgw utils.ClusterGateway) bool {
 			hasAttachedRoute := false
 			for _, statusListener := range cgw.Status.Listeners {
@@ -84,7 +105,7 @@ func (r *DNSPolicyReconciler) reconcileGatewayDNSRecords(ctx context.Context, gw
 			if err := r.dnsHelper.deleteDNSRecordForListener(ctx, gatewayWrapper, listener); client.IgnoreNotFound(err) != nil {
 				return fmt.Errorf("failed to delete dns record for listener %s : %s", listener.Name, err)
 			}
-			return nil
+			return errors.Join(conflicts...)
 		}
 		dnsRecord, err := r.dnsHelper.createDNSRecordForListener(ctx, gatewayWrapper.Gateway, dnsPolicy, mz, listener)
 		if err := client.IgnoreAlreadyExists(err); err != nil {
@@ -96,6 +117,7 @@ func (r *DNSPolicyReconciler) reconcileGatewayDNSRecords(ctx context.Context, gw
 				return fmt.Errorf("failed to get dns record for host %s : %s ", listener.Name, err)
 			}
 		}
+		published.publish(client.ObjectKeyFromObject(mz), string(listenerHost), client.ObjectKeyFromObject(gatewayWrapper.Gateway))
 
 		mcgTarget, err := dns.NewMultiClusterGatewayTarget(gatewayWrapper.Gateway, listenerGateways, dnsPolicy.Spec.LoadBalancing)
 		if err != nil {
@@ -111,8 +133,11 @@ func (r *DNSPolicyReconciler) reconcileGatewayDNSRecords(ctx context.Context, gw
 		if err := r.dnsHelper.setEndpoints(ctx, mcgTarget, dnsRecord, listener, dnsPolicy.Spec.RoutingStrategy); err != nil {
 			return fmt.Errorf("failed to add dns record dnsTargets %s %v", err, mcgTarget)
 		}
+		if err := r.reconcileProviderHealthChecks(ctx, dnsPolicy, dnsRecord, mz, listener, healthChecks); err != nil {
+			return fmt.Errorf("failed to reconcile provider health checks for listener %s : %w", listener.Name, err)
+		}
 	}
-	return nil
+	return errors.Join(conflicts...)
 }
 
 func (r *DNSPolicyReconciler) deleteGatewayDNSRecords(ctx context.Context, gateway *gatewayapiv1.Gateway, dnsPolicy *v1alpha1.DNSPolicy) error {
@@ -118,6 +118,11 @@ func (r *DNSPolicyReconciler) expectedHealthCheckProbesForGateway(ctx context.Co
 		return healthChecks
 	}
 
+	if !dnsPolicy.Spec.HealthCheck.Mode.UsesProbes() {
+		log.V(3).Info("DNS Policy health check only uses provider health checks")
+		return healthChecks
+	}
+
 	interval := metav1.Duration{Duration: 60 * time.Second}
 	if dnsPolicy.Spec.HealthCheck.Interval != nil {
 		interval = *dnsPolicy.Spec.HealthCheck.Interval
@@ -143,12 +148,15 @@ func (r *DNSPolicyReconciler) expectedHealthCheckProbesForGateway(ctx context.Co
 			port = &listenerPort
 		}
 
-		var protocol string
-		// handle protocol being nil
-		if dnsPolicy.Spec.HealthCheck.Protocol == nil {
-			protocol = string(listener.Protocol)
-		} else {
-			protocol = string(*dnsPolicy.Spec.HealthCheck.Protocol)
+		// use the protocol from the policy, falling back to the one matching the listener.
+		// UDP listeners can't be probed whatever the protocol
+		protocol, ok := healthProtocolForListener(listener)
+		if dnsPolicy.Spec.HealthCheck.Protocol != nil && listener.Protocol != gatewayapiv1.UDPProtocolType {
+			protocol, ok = *dnsPolicy.Spec.HealthCheck.Protocol, true
+		}
+		if !ok {
+			log.V(3).Info("skipping health check for listener with unsupported protocol", "listener", listener.Name, "protocol", listener.Protocol)
+			continue
 		}
 
 		for _, addresses := range clusterGatewayAddresses {
@@ -161,16 +169,28 @@ func (r *DNSPolicyReconciler) expectedHealthCheckProbesForGateway(ctx context.Co
 						Labels:    commonDNSRecordLabels(client.ObjectKeyFromObject(gw), client.ObjectKeyFromObject(dnsPolicy)),
 					},
 					Spec: v1alpha1.DNSHealthCheckProbeSpec{
-						Port:                     *port,
-						Host:                     string(*listener.Hostname),
-						Address:                  address.Value,
-						Path:                     dnsPolicy.Spec.HealthCheck.Endpoint,
-						Protocol:                 v1alpha1.HealthProtocol(protocol),
-						Interval:                 interval,
-						AdditionalHeadersRef:     dnsPolicy.Spec.HealthCheck.AdditionalHeadersRef,
-						FailureThreshold:         dnsPolicy.Spec.HealthCheck.FailureThreshold,
-						ExpectedResponses:        dnsPolicy.Spec.HealthCheck.ExpectedResponses,
-						AllowInsecureCertificate: dnsPolicy.Spec.HealthCheck.AllowInsecureCertificates,
+						Port:                       *port,
+						Host:                       string(*listener.Hostname),
+						Address:                    address.Value,
+						Path:                       dnsPolicy.Spec.HealthCheck.Endpoint,
+						Protocol:                   protocol,
+						Interval:                   interval,
+						Timeout:                    dnsPolicy.Spec.HealthCheck.Timeout,
+						AdditionalHeadersRef:       dnsPolicy.Spec.HealthCheck.AdditionalHeadersRef,
+						CACertificateRef:           dnsPolicy.Spec.HealthCheck.CACertificateRef,
+						ClientCertificateRef:       dnsPolicy.Spec.HealthCheck.ClientCertificateRef,
+						FailureThreshold:           dnsPolicy.Spec.HealthCheck.FailureThreshold,
+						SuccessThreshold:           dnsPolicy.Spec.HealthCheck.SuccessThreshold,
+						FlapDamping:                dnsPolicy.Spec.HealthCheck.FlapDamping,
+						VantagePointQuorum:         dnsPolicy.Spec.HealthCheck.VantagePointQuorum,
+						ExpectedResponses:          dnsPolicy.Spec.HealthCheck.ExpectedResponses,
+						AllowInsecureCertificate:   dnsPolicy.Spec.HealthCheck.AllowInsecureCertificates,
+						ServerName:                 dnsPolicy.Spec.HealthCheck.ServerName,
+						CertificateExpiryThreshold: dnsPolicy.Spec.HealthCheck.CertificateExpiryThreshold,
+						GRPCService:                dnsPolicy.Spec.HealthCheck.GRPCService,
+						GRPCPlaintext:              protocol.IsGrpc() && listener.Protocol == gatewayapiv1.HTTPProtocolType,
+						ResponseAssertions:         dnsPolicy.Spec.HealthCheck.ResponseAssertions,
+						Webhooks:                   dnsPolicy.Spec.HealthCheck.Webhooks,
 					},
 				}
 				healthChecks = append(healthChecks, withGatewayListener(gw, listener, healthCheck))
@@ -181,6 +201,22 @@ func (r *DNSPolicyReconciler) expectedHealthCheckProbesForGateway(ctx context.Co
 	return healthChecks
 }
 
+// healthProtocolForListener returns the health check protocol matching the
+// protocol of the listener. Listeners that can't be probed, such as UDP, return false
+func healthProtocolForListener(listener gatewayapiv1.Listener) (v1alpha1.HealthProtocol, bool) {
+	switch listener.Protocol {
+	case gatewayapiv1.HTTPProtocolType:
+		return v1alpha1.HttpProtocol, true
+	case gatewayapiv1.HTTPSProtocolType:
+		return v1alpha1.HttpsProtocol, true
+	case gatewayapiv1.TLSProtocolType:
+		return v1alpha1.TlsProtocol, true
+	case gatewayapiv1.TCPProtocolType:
+		return v1alpha1.TcpProtocol, true
+	}
+	return "", false
+}
+
 func dnsHealthCheckProbeName(address, gatewayName, listenerName string) string {
 	return fmt.Sprintf("%s-%s", address, dnsRecordName(gatewayName, listenerName))
 }
@@ -207,6 +207,172 @@ func TestDNSPolicyReconciler_expectedProbesForGateway(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "expected TLS probe when listener protocol is TLS",
+			fields: fields{
+				TargetRefReconciler: reconcilers.TargetRefReconciler{},
+				DNSProvider:         nil,
+				dnsHelper:           dnsHelper{},
+				Placer:              nil,
+			},
+			args: args{
+				ctx: nil,
+				gw: common.GatewayWrapper{
+					Gateway: &gatewayapiv1.Gateway{
+						ObjectMeta: controllerruntime.ObjectMeta{
+							Name:      "testgateway",
+							Namespace: "testnamespace",
+						},
+						Spec: gatewayapiv1.GatewaySpec{
+							Listeners: []gatewayapiv1.Listener{
+								{
+									Name:     "testlistener",
+									Hostname: (*gatewayapiv1.Hostname)(testutil.Pointer(ValidTestHostname)),
+									Port:     8443,
+									Protocol: gatewayapiv1.TLSProtocolType,
+								},
+								{
+									Name:     "udplistener",
+									Hostname: (*gatewayapiv1.Hostname)(testutil.Pointer(ValidTestHostname)),
+									Port:     53,
+									Protocol: gatewayapiv1.UDPProtocolType,
+								},
+							},
+						},
+						Status: gatewayapiv1.GatewayStatus{
+							Addresses: []gatewayapiv1.GatewayStatusAddress{
+								{
+									Type:  testutil.Pointer(utils.MultiClusterIPAddressType),
+									Value: "clusterName/172.31.200.0",
+								},
+							},
+						},
+					},
+				},
+				dnsPolicy: &v1alpha1.DNSPolicy{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "testdnspolicy",
+						Namespace: "testnamespace",
+					},
+					Spec: v1alpha1.DNSPolicySpec{
+						HealthCheck: &v1alpha1.HealthCheckSpec{
+							ServerName:                 "sni." + Domain,
+							CertificateExpiryThreshold: &metav1.Duration{Duration: 24 * time.Hour},
+						},
+					},
+				},
+			},
+			want: []*v1alpha1.DNSHealthCheckProbe{
+				{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "172.31.200.0-testgateway-testlistener",
+						Namespace: "testnamespace",
+						Labels: map[string]string{
+							DNSPolicyBackRefAnnotation:                              "testdnspolicy",
+							fmt.Sprintf("%s-namespace", DNSPolicyBackRefAnnotation): "testnamespace",
+							LabelGatewayNSRef:                                       "testnamespace",
+							LabelGatewayReference:                                   "testgateway",
+						},
+						Annotations: map[string]string{
+							"dnsrecord-name":      "testgateway-testlistener",
+							"dnsrecord-namespace": "testnamespace",
+						},
+					},
+					Spec: v1alpha1.DNSHealthCheckProbeSpec{
+						Port:                       8443,
+						Host:                       ValidTestHostname,
+						Address:                    "172.31.200.0",
+						Protocol:                   v1alpha1.TlsProtocol,
+						Interval:                   metav1.Duration{Duration: 60 * time.Second},
+						ServerName:                 "sni." + Domain,
+						CertificateExpiryThreshold: &metav1.Duration{Duration: 24 * time.Hour},
+					},
+				},
+			},
+		},
+		{
+			name: "expected plaintext gRPC probe when listener protocol is HTTP",
+			fields: fields{
+				TargetRefReconciler: reconcilers.TargetRefReconciler{},
+				DNSProvider:         nil,
+				dnsHelper:           dnsHelper{},
+				Placer:              nil,
+			},
+			args: args{
+				ctx: nil,
+				gw: common.GatewayWrapper{
+					Gateway: &gatewayapiv1.Gateway{
+						ObjectMeta: controllerruntime.ObjectMeta{
+							Name:      "testgateway",
+							Namespace: "testnamespace",
+						},
+						Spec: gatewayapiv1.GatewaySpec{
+							Listeners: []gatewayapiv1.Listener{
+								{
+									Name:     "testlistener",
+									Hostname: (*gatewayapiv1.Hostname)(testutil.Pointer(ValidTestHostname)),
+									Port:     80,
+									Protocol: gatewayapiv1.HTTPProtocolType,
+								},
+								{
+									Name:     "udplistener",
+									Hostname: (*gatewayapiv1.Hostname)(testutil.Pointer(ValidTestHostname)),
+									Port:     53,
+									Protocol: gatewayapiv1.UDPProtocolType,
+								},
+							},
+						},
+						Status: gatewayapiv1.GatewayStatus{
+							Addresses: []gatewayapiv1.GatewayStatusAddress{
+								{
+									Type:  testutil.Pointer(utils.MultiClusterIPAddressType),
+									Value: "clusterName/172.31.200.0",
+								},
+							},
+						},
+					},
+				},
+				dnsPolicy: &v1alpha1.DNSPolicy{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "testdnspolicy",
+						Namespace: "testnamespace",
+					},
+					Spec: v1alpha1.DNSPolicySpec{
+						HealthCheck: &v1alpha1.HealthCheckSpec{
+							Protocol:    testutil.Pointer(v1alpha1.GrpcProtocol),
+							GRPCService: "echo",
+						},
+					},
+				},
+			},
+			want: []*v1alpha1.DNSHealthCheckProbe{
+				{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "172.31.200.0-testgateway-testlistener",
+						Namespace: "testnamespace",
+						Labels: map[string]string{
+							DNSPolicyBackRefAnnotation:                              "testdnspolicy",
+							fmt.Sprintf("%s-namespace", DNSPolicyBackRefAnnotation): "testnamespace",
+							LabelGatewayNSRef:                                       "testnamespace",
+							LabelGatewayReference:                                   "testgateway",
+						},
+						Annotations: map[string]string{
+							"dnsrecord-name":      "testgateway-testlistener",
+							"dnsrecord-namespace": "testnamespace",
+						},
+					},
+					Spec: v1alpha1.DNSHealthCheckProbeSpec{
+						Port:          80,
+						Host:          ValidTestHostname,
+						Address:       "172.31.200.0",
+						Protocol:      v1alpha1.GrpcProtocol,
+						Interval:      metav1.Duration{Duration: 60 * time.Second},
+						GRPCService:   "echo",
+						GRPCPlaintext: true,
+					},
+				},
+			},
+		},
 		{
 			name: "no probes when listener has a wildcard domain",
 			fields: fields{
@@ -0,0 +1,185 @@
+package dnspolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/dns"
+)
+
+const defaultProviderHealthCheckPath = "/"
+
+// reconcileProviderHealthChecks creates or updates a health check in the DNS
+// provider for every weighted endpoint of the record, and attaches it to the
+// endpoint through the provider specific health check ID. Health checks of
+// every endpoint are deleted when the policy no longer uses provider health
+// checks. Health checks of endpoints removed from the record are deleted by
+// the DNSRecord controller once the record is published without them. The
+// result of each health
+// check is recorded as a condition in the policy status, and its type added to
+// reconciled
+func (r *DNSPolicyReconciler) reconcileProviderHealthChecks(ctx context.Context, dnsPolicy *v1alpha1.DNSPolicy, dnsRecord *v1alpha1.DNSRecord, mz *v1alpha1.ManagedZone, listener gatewayapiv1.Listener, reconciled map[string]bool) error {
+	log := crlog.FromContext(ctx)
+
+	usesProvider := dnsPolicy.Spec.HealthCheck != nil && dnsPolicy.Spec.HealthCheck.Mode.UsesProvider()
+	hadHealthChecks := dnsPolicy.Status.HealthCheck != nil && len(dnsPolicy.Status.HealthCheck.Conditions) > 0
+	if !usesProvider && !hadHealthChecks {
+		return nil
+	}
+
+	provider, err := r.DNSProvider(ctx, mz)
+	if err != nil {
+		return err
+	}
+	healthCheckReconciler := provider.HealthCheckReconciler()
+	healthCheckID := provider.ProviderSpecific().HealthCheckID
+
+	previous := dnsRecord.DeepCopy()
+	var errs []error
+
+	for _, endpoint := range dnsRecord.Spec.Endpoints {
+		if _, ok := endpoint.GetProviderSpecific(dns.ProviderSpecificWeight); !ok {
+			continue
+		}
+
+		spec, ok := providerHealthCheckSpec(dnsPolicy, listener, endpoint)
+		if !usesProvider || !ok {
+			if _, ok := endpoint.GetProviderSpecific(healthCheckID); ok {
+				log.V(1).Info("deleting provider health check", "endpoint", endpoint.SetID())
+				if _, err := healthCheckReconciler.Delete(ctx, endpoint); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			continue
+		}
+
+		log.V(3).Info("reconciling provider health check", "endpoint", endpoint.SetID(), "spec", spec)
+		result, err := healthCheckReconciler.Reconcile(ctx, spec, endpoint)
+		setHealthCheckCondition(dnsPolicy, spec.Name, result, err)
+		reconciled[spec.Name] = true
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to reconcile health check for endpoint %s: %w", endpoint.SetID(), err))
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(previous.Spec, dnsRecord.Spec) {
+		if err := r.Client().Update(ctx, dnsRecord); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// providerHealthCheckSpec returns the provider health check for the endpoint.
+// Provider health checks are limited to HTTP and HTTPS, when the policy
+// doesn't set the protocol listeners with other protocols return false
+func providerHealthCheckSpec(dnsPolicy *v1alpha1.DNSPolicy, listener gatewayapiv1.Listener, endpoint *v1alpha1.Endpoint) (dns.HealthCheckSpec, bool) {
+	healthCheck := dnsPolicy.Spec.HealthCheck
+
+	protocol, ok := healthProtocolForListener(listener)
+	if healthCheck.Protocol != nil {
+		protocol, ok = *healthCheck.Protocol, true
+	}
+	if !ok || !protocol.IsHttp() && !protocol.IsHttps() {
+		return dns.HealthCheckSpec{}, false
+	}
+	providerProtocol := dns.HealthCheckProtocol(protocol)
+
+	port := int64(listener.Port)
+	if healthCheck.Port != nil {
+		port = int64(*healthCheck.Port)
+	}
+
+	var failureThreshold *int64
+	if healthCheck.FailureThreshold != nil {
+		threshold := int64(*healthCheck.FailureThreshold)
+		failureThreshold = &threshold
+	}
+
+	path := healthCheck.Endpoint
+	if path == "" {
+		path = defaultProviderHealthCheckPath
+	}
+
+	// the endpoint is checked as the listener hostname, wildcard listeners
+	// have no hostname to check
+	var host string
+	if listener.Hostname != nil && !strings.Contains(string(*listener.Hostname), "*") {
+		host = string(*listener.Hostname)
+	}
+
+	name := healthCheckConditionType(endpoint)
+	return dns.HealthCheckSpec{
+		Id:               dns.ToBase36hash(name),
+		Name:             name,
+		Port:             &port,
+		FailureThreshold: failureThreshold,
+		Protocol:         &providerProtocol,
+		Path:             path,
+		Host:             host,
+	}, true
+}
+
+// healthCheckConditionType identifies the health check of a weighted endpoint
+// by the record name and its target, e.g.
+// default.lb-1ab1.www.example.com/1bc1.lb-1ab1.www.example.com
+func healthCheckConditionType(endpoint *v1alpha1.Endpoint) string {
+	address, _ := endpoint.GetAddress()
+	return fmt.Sprintf("%s/%s", endpoint.DNSName, address)
+}
+
+// setHealthCheckCondition records the result of reconciling a provider health
+// check in the policy status
+func setHealthCheckCondition(dnsPolicy *v1alpha1.DNSPolicy, conditionType string, result dns.HealthCheckResult, err error) {
+	if dnsPolicy.Status.HealthCheck == nil {
+		dnsPolicy.Status.HealthCheck = &v1alpha1.HealthCheckStatus{}
+	}
+
+	cond := metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             string(result.Result),
+		Message:            result.Message,
+		ObservedGeneration: dnsPolicy.Generation,
+	}
+	if err != nil || result.Result == dns.HealthCheckFailed {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = string(dns.HealthCheckFailed)
+		if err != nil {
+			cond.Message = dns.SanitizeError(err).Error()
+		}
+	}
+
+	meta.SetStatusCondition(&dnsPolicy.Status.HealthCheck.Conditions, cond)
+}
+
+// pruneHealthCheckConditions removes the conditions of the provider health
+// checks that were not reconciled, as their endpoints no longer exist
+func pruneHealthCheckConditions(dnsPolicy *v1alpha1.DNSPolicy, reconciled map[string]bool) {
+	if dnsPolicy.Status.HealthCheck == nil {
+		return
+	}
+
+	conditions := []metav1.Condition{}
+	for _, cond := range dnsPolicy.Status.HealthCheck.Conditions {
+		if reconciled[cond.Type] {
+			conditions = append(conditions, cond)
+		}
+	}
+
+	if len(conditions) == 0 {
+		dnsPolicy.Status.HealthCheck = nil
+		return
+	}
+	dnsPolicy.Status.HealthCheck.Conditions = conditions
+}
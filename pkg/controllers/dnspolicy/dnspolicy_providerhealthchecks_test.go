@@ -0,0 +1,221 @@
+//go:build unit
+
+package dnspolicy
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/dns"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+const testHealthCheckID = "fake/health-check-id"
+
+type recordingHealthCheckReconciler struct {
+	reconciled []string
+	deleted    []string
+}
+
+func (r *recordingHealthCheckReconciler) Reconcile(_ context.Context, spec dns.HealthCheckSpec, endpoint *v1alpha1.Endpoint) (dns.HealthCheckResult, error) {
+	r.reconciled = append(r.reconciled, spec.Name)
+	if _, ok := endpoint.GetProviderSpecific(testHealthCheckID); ok {
+		return dns.NewHealthCheckResult(dns.HealthCheckNoop, ""), nil
+	}
+	endpoint.SetProviderSpecific(testHealthCheckID, spec.Id)
+	return dns.NewHealthCheckResult(dns.HealthCheckCreated, "Created health check with ID "+spec.Id), nil
+}
+
+func (r *recordingHealthCheckReconciler) Delete(_ context.Context, endpoint *v1alpha1.Endpoint) (dns.HealthCheckResult, error) {
+	r.deleted = append(r.deleted, endpoint.SetID())
+	endpoint.DeleteProviderSpecific(testHealthCheckID)
+	return dns.NewHealthCheckResult(dns.HealthCheckDeleted, ""), nil
+}
+
+type healthCheckProvider struct {
+	dns.FakeProvider
+	reconciler *recordingHealthCheckReconciler
+}
+
+func (p *healthCheckProvider) HealthCheckReconciler() dns.HealthCheckReconciler {
+	return p.reconciler
+}
+
+func TestReconcileProviderHealthChecks(t *testing.T) {
+	weighted := func(target, healthCheckID string) *v1alpha1.Endpoint {
+		endpoint := &v1alpha1.Endpoint{
+			DNSName:       "default.lb-1ab1.boop.thecat.com",
+			SetIdentifier: target,
+			Targets:       v1alpha1.Targets{target},
+			RecordType:    "CNAME",
+		}
+		endpoint.SetProviderSpecific(dns.ProviderSpecificWeight, "120")
+		if healthCheckID != "" {
+			endpoint.SetProviderSpecific(testHealthCheckID, healthCheckID)
+		}
+		return endpoint
+	}
+	cname := &v1alpha1.Endpoint{
+		DNSName:    "boop.thecat.com",
+		Targets:    v1alpha1.Targets{"lb-1ab1.boop.thecat.com"},
+		RecordType: "CNAME",
+	}
+	listener := gatewayapiv1.Listener{Name: "api", Port: 443, Protocol: gatewayapiv1.HTTPSProtocolType}
+
+	testCases := []struct {
+		name      string
+		mode      v1alpha1.HealthCheckMode
+		status    *v1alpha1.HealthCheckStatus
+		endpoints []*v1alpha1.Endpoint
+		verify    func(t *testing.T, reconciler *recordingHealthCheckReconciler, dnsPolicy *v1alpha1.DNSPolicy, dnsRecord *v1alpha1.DNSRecord, reconciled map[string]bool)
+	}{
+		{
+			name:      "creates health checks for weighted endpoints",
+			mode:      v1alpha1.ProviderHealthCheckMode,
+			endpoints: []*v1alpha1.Endpoint{weighted("1bc1.lb-1ab1.boop.thecat.com", ""), cname},
+			verify: func(t *testing.T, reconciler *recordingHealthCheckReconciler, dnsPolicy *v1alpha1.DNSPolicy, dnsRecord *v1alpha1.DNSRecord, reconciled map[string]bool) {
+				conditionType := "default.lb-1ab1.boop.thecat.com/1bc1.lb-1ab1.boop.thecat.com"
+				if len(reconciler.reconciled) != 1 || reconciler.reconciled[0] != conditionType {
+					t.Errorf("expected health check %s to be reconciled, got %v", conditionType, reconciler.reconciled)
+				}
+				if _, ok := dnsRecord.Spec.Endpoints[0].GetProviderSpecific(testHealthCheckID); !ok {
+					t.Errorf("expected health check ID to be saved in the record, got %v", dnsRecord.Spec.Endpoints[0])
+				}
+				cond := meta.FindStatusCondition(dnsPolicy.Status.HealthCheck.Conditions, conditionType)
+				if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != string(dns.HealthCheckCreated) {
+					t.Errorf("expected Created condition, got %v", cond)
+				}
+				if !reconciled[conditionType] {
+					t.Errorf("expected %s to be marked as reconciled", conditionType)
+				}
+			},
+		},
+		{
+			name: "skips provider when never used",
+			mode: v1alpha1.ProbesHealthCheckMode,
+			endpoints: []*v1alpha1.Endpoint{
+				weighted("1bc1.lb-1ab1.boop.thecat.com", ""),
+			},
+			verify: func(t *testing.T, reconciler *recordingHealthCheckReconciler, dnsPolicy *v1alpha1.DNSPolicy, _ *v1alpha1.DNSRecord, _ map[string]bool) {
+				if len(reconciler.reconciled) != 0 || len(reconciler.deleted) != 0 {
+					t.Errorf("expected no health checks, got %v and %v", reconciler.reconciled, reconciler.deleted)
+				}
+				if dnsPolicy.Status.HealthCheck != nil {
+					t.Errorf("expected no health check status, got %v", dnsPolicy.Status.HealthCheck)
+				}
+			},
+		},
+		{
+			name: "deletes health checks when switching to probes",
+			mode: v1alpha1.ProbesHealthCheckMode,
+			status: &v1alpha1.HealthCheckStatus{Conditions: []metav1.Condition{{
+				Type:   "default.lb-1ab1.boop.thecat.com/1bc1.lb-1ab1.boop.thecat.com",
+				Status: metav1.ConditionTrue,
+				Reason: string(dns.HealthCheckNoop),
+			}}},
+			endpoints: []*v1alpha1.Endpoint{weighted("1bc1.lb-1ab1.boop.thecat.com", "abc123")},
+			verify: func(t *testing.T, reconciler *recordingHealthCheckReconciler, _ *v1alpha1.DNSPolicy, dnsRecord *v1alpha1.DNSRecord, reconciled map[string]bool) {
+				if len(reconciler.deleted) != 1 {
+					t.Errorf("expected health check to be deleted, got %v", reconciler.deleted)
+				}
+				if _, ok := dnsRecord.Spec.Endpoints[0].GetProviderSpecific(testHealthCheckID); ok {
+					t.Errorf("expected health check ID to be removed from the record, got %v", dnsRecord.Spec.Endpoints[0])
+				}
+				if len(reconciled) != 0 {
+					t.Errorf("expected no health checks to be reconciled, got %v", reconciled)
+				}
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dnsRecord := &v1alpha1.DNSRecord{
+				ObjectMeta: metav1.ObjectMeta{Name: "gw-api", Namespace: "test"},
+				Spec:       v1alpha1.DNSRecordSpec{Endpoints: testCase.endpoints},
+			}
+			dnsPolicy := &v1alpha1.DNSPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "test"},
+				Spec: v1alpha1.DNSPolicySpec{
+					HealthCheck: &v1alpha1.HealthCheckSpec{Mode: testCase.mode},
+				},
+				Status: v1alpha1.DNSPolicyStatus{HealthCheck: testCase.status},
+			}
+
+			f := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(dnsRecord).Build()
+			healthCheckReconciler := &recordingHealthCheckReconciler{}
+			r := &DNSPolicyReconciler{
+				TargetRefReconciler: reconcilers.TargetRefReconciler{
+					BaseReconciler: reconcilers.NewBaseReconciler(f, f.Scheme(), f, log.Log, record.NewFakeRecorder(10)),
+				},
+				DNSProvider: func(_ context.Context, _ *v1alpha1.ManagedZone) (dns.Provider, error) {
+					return &healthCheckProvider{reconciler: healthCheckReconciler}, nil
+				},
+			}
+
+			reconciled := map[string]bool{}
+			if err := r.reconcileProviderHealthChecks(context.Background(), dnsPolicy, dnsRecord, &v1alpha1.ManagedZone{}, listener, reconciled); err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+
+			stored := &v1alpha1.DNSRecord{}
+			if err := f.Get(context.Background(), client.ObjectKeyFromObject(dnsRecord), stored); err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			testCase.verify(t, healthCheckReconciler, dnsPolicy, stored, reconciled)
+		})
+	}
+}
+
+func TestProviderHealthCheckSpec(t *testing.T) {
+	endpoint := &v1alpha1.Endpoint{
+		DNSName:       "default.lb-1ab1.boop.thecat.com",
+		SetIdentifier: "aws.lb.com",
+		Targets:       v1alpha1.Targets{"aws.lb.com"},
+	}
+	dnsPolicy := &v1alpha1.DNSPolicy{
+		Spec: v1alpha1.DNSPolicySpec{
+			HealthCheck: &v1alpha1.HealthCheckSpec{
+				Mode:             v1alpha1.ProviderHealthCheckMode,
+				FailureThreshold: testutil.Pointer(5),
+			},
+		},
+	}
+
+	spec, ok := providerHealthCheckSpec(dnsPolicy, gatewayapiv1.Listener{Port: 8443, Protocol: gatewayapiv1.HTTPSProtocolType}, endpoint)
+	if !ok {
+		t.Fatalf("expected HTTPS listener to be checked")
+	}
+	if *spec.Port != 8443 || *spec.Protocol != dns.HealthCheckProtocolHTTPS || *spec.FailureThreshold != 5 || spec.Path != "/" {
+		t.Errorf("unexpected spec %+v", spec)
+	}
+	if spec.Name != "default.lb-1ab1.boop.thecat.com/aws.lb.com" || len(spec.Id) != 6 {
+		t.Errorf("unexpected name %s and id %s", spec.Name, spec.Id)
+	}
+
+	hostname := gatewayapiv1.Hostname("boop.thecat.com")
+	spec, _ = providerHealthCheckSpec(dnsPolicy, gatewayapiv1.Listener{Port: 8443, Protocol: gatewayapiv1.HTTPSProtocolType, Hostname: &hostname}, endpoint)
+	if spec.Host != "boop.thecat.com" {
+		t.Errorf("expected the listener hostname to be checked, got %s", spec.Host)
+	}
+	wildcard := gatewayapiv1.Hostname("*.thecat.com")
+	spec, _ = providerHealthCheckSpec(dnsPolicy, gatewayapiv1.Listener{Port: 8443, Protocol: gatewayapiv1.HTTPSProtocolType, Hostname: &wildcard}, endpoint)
+	if spec.Host != "" {
+		t.Errorf("expected no hostname for a wildcard listener, got %s", spec.Host)
+	}
+
+	if _, ok := providerHealthCheckSpec(dnsPolicy, gatewayapiv1.Listener{Port: 5432, Protocol: gatewayapiv1.TCPProtocolType}, endpoint); ok {
+		t.Errorf("expected TCP listener not to be checked")
+	}
+}
@@ -160,15 +160,31 @@ func (r *DNSRecordReconciler) deleteRecord(ctx context.Context, dnsRecord *v1alp
 	if err != nil {
 		if strings.Contains(err.Error(), "was not found") || strings.Contains(err.Error(), "notFound") {
 			log.Log.Info("Record not found in managed zone, continuing", "dnsRecord", dnsRecord.Name, "managedZone", managedZone.Name)
-			return nil
 		} else if strings.Contains(err.Error(), "no endpoints") {
 			log.Log.Info("DNS record had no endpoint, continuing", "dnsRecord", dnsRecord.Name, "managedZone", managedZone.Name)
-			return nil
+		} else {
+			return err
 		}
-		return err
+	} else {
+		log.Log.Info("Deleted DNSRecord in manage zone", "dnsRecord", dnsRecord.Name, "managedZone", managedZone.Name)
 	}
-	log.Log.Info("Deleted DNSRecord in manage zone", "dnsRecord", dnsRecord.Name, "managedZone", managedZone.Name)
 
+	return deleteHealthChecks(ctx, dnsProvider, dnsRecord)
+}
+
+// deleteHealthChecks deletes the provider health checks attached to the
+// endpoints of the record. They are deleted after the record so that they are
+// no longer in use
+func deleteHealthChecks(ctx context.Context, dnsProvider dns.Provider, dnsRecord *v1alpha1.DNSRecord) error {
+	healthCheckID := dnsProvider.ProviderSpecific().HealthCheckID
+	for _, endpoint := range dnsRecord.Spec.Endpoints {
+		if _, ok := endpoint.GetProviderSpecific(healthCheckID); !ok {
+			continue
+		}
+		if _, err := dnsProvider.HealthCheckReconciler().Delete(ctx, endpoint); err != nil {
+			return fmt.Errorf("failed to delete health check for endpoint %s: %w", endpoint.SetID(), err)
+		}
+	}
 	return nil
 }
 
@@ -207,6 +223,32 @@ func (r *DNSRecordReconciler) publishRecord(ctx context.Context, dnsRecord *v1al
 	}
 	log.Log.Info("Published DNSRecord to manage zone", "dnsRecord", dnsRecord.Name, "managedZone", managedZone.Name)
 
+	return deleteRemovedHealthChecks(ctx, dnsProvider, dnsRecord)
+}
+
+// deleteRemovedHealthChecks deletes the provider health checks attached to the
+// endpoints that were published before, and have been removed from the record.
+// They are deleted once the record is published so that they are no longer in
+// use
+func deleteRemovedHealthChecks(ctx context.Context, dnsProvider dns.Provider, dnsRecord *v1alpha1.DNSRecord) error {
+	current := map[string]bool{}
+	for _, endpoint := range dnsRecord.Spec.Endpoints {
+		current[endpoint.SetID()] = true
+	}
+
+	healthCheckID := dnsProvider.ProviderSpecific().HealthCheckID
+	for _, endpoint := range dnsRecord.Status.Endpoints {
+		if current[endpoint.SetID()] {
+			continue
+		}
+		if _, ok := endpoint.GetProviderSpecific(healthCheckID); !ok {
+			continue
+		}
+		log.Log.V(1).Info("Deleting health check of removed endpoint", "dnsRecord", dnsRecord.Name, "endpoint", endpoint.SetID())
+		if _, err := dnsProvider.HealthCheckReconciler().Delete(ctx, endpoint.DeepCopy()); err != nil {
+			return fmt.Errorf("failed to delete health check for endpoint %s: %w", endpoint.SetID(), err)
+		}
+	}
 	return nil
 }
 
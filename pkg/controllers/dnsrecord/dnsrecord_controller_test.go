@@ -0,0 +1,69 @@
+//go:build unit
+
+package dnsrecord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/dns"
+)
+
+type recordingHealthCheckReconciler struct {
+	dns.FakeHealthCheckReconciler
+	deleted []string
+}
+
+func (r *recordingHealthCheckReconciler) Delete(_ context.Context, endpoint *v1alpha1.Endpoint) (dns.HealthCheckResult, error) {
+	r.deleted = append(r.deleted, endpoint.SetID())
+	return dns.NewHealthCheckResult(dns.HealthCheckDeleted, ""), nil
+}
+
+type healthCheckProvider struct {
+	dns.FakeProvider
+	reconciler *recordingHealthCheckReconciler
+}
+
+func (p *healthCheckProvider) HealthCheckReconciler() dns.HealthCheckReconciler {
+	return p.reconciler
+}
+
+func TestDeleteRemovedHealthChecks(t *testing.T) {
+	provider := &healthCheckProvider{reconciler: &recordingHealthCheckReconciler{}}
+	healthCheckID := provider.ProviderSpecific().HealthCheckID
+
+	weighted := func(target, id string) *v1alpha1.Endpoint {
+		endpoint := &v1alpha1.Endpoint{
+			DNSName:       "default.lb-1ab1.boop.thecat.com",
+			SetIdentifier: target,
+			Targets:       v1alpha1.Targets{target},
+			RecordType:    "CNAME",
+		}
+		if id != "" {
+			endpoint.SetProviderSpecific(healthCheckID, id)
+		}
+		return endpoint
+	}
+
+	dnsRecord := &v1alpha1.DNSRecord{
+		Spec: v1alpha1.DNSRecordSpec{Endpoints: []*v1alpha1.Endpoint{
+			weighted("1bc1.lb-1ab1.boop.thecat.com", "abc123"),
+		}},
+		Status: v1alpha1.DNSRecordStatus{Endpoints: []*v1alpha1.Endpoint{
+			weighted("1bc1.lb-1ab1.boop.thecat.com", "abc123"),
+			weighted("aws.lb.com", "def456"),
+			weighted("gcp.lb.com", ""),
+		}},
+	}
+
+	if err := deleteRemovedHealthChecks(context.Background(), provider, dnsRecord); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(provider.reconciler.deleted) != 1 || provider.reconciler.deleted[0] != "default.lb-1ab1.boop.thecat.comaws.lb.com" {
+		t.Errorf("expected only the health check of the removed endpoint to be deleted, got %v", provider.reconciler.deleted)
+	}
+	if _, ok := dnsRecord.Status.Endpoints[1].GetProviderSpecific(healthCheckID); !ok {
+		t.Errorf("expected the published endpoints to be left unchanged")
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/clusterSecret"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
 )
 
 type ClusterEventHandler struct {
@@ -42,6 +44,8 @@ func (eh *ClusterEventHandler) Generic(ctx context.Context, e event.GenericEvent
 // Update implements handler.EventHandler
 func (eh *ClusterEventHandler) Update(ctx context.Context, e event.UpdateEvent, q workqueue.RateLimitingInterface) {
 	eh.enqueueForObject(ctx, e.ObjectNew, q)
+	// the cluster may no longer match the selector of the gateways it was placed on
+	eh.enqueueForClusterLabels(ctx, e.ObjectOld, q)
 }
 
 func (eh *ClusterEventHandler) enqueueForObject(ctx context.Context, obj v1.Object, q workqueue.RateLimitingInterface) {
@@ -61,6 +65,33 @@ func (eh *ClusterEventHandler) enqueueForObject(ctx context.Context, obj v1.Obje
 			NamespacedName: client.ObjectKeyFromObject(&gateway),
 		})
 	}
+
+	eh.enqueueForClusterLabels(ctx, obj, q)
+}
+
+// enqueueForClusterLabels enqueues the gateways whose cluster label selector
+// matches the labels of the cluster secret
+func (eh *ClusterEventHandler) enqueueForClusterLabels(ctx context.Context, obj v1.Object, q workqueue.RateLimitingInterface) {
+	if !clusterSecret.IsClusterSecret(obj) {
+		return
+	}
+
+	gateways := &gatewayapiv1.GatewayList{}
+	if err := eh.client.List(ctx, gateways); err != nil {
+		log.Log.Error(err, "failed to get gateways when enqueueing from cluster secret labels")
+		return
+	}
+
+	for _, gateway := range gateways.Items {
+		selector, err := placement.ClusterSelector(&gateway)
+		if err != nil || selector == nil || !selector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+		log.Log.V(3).Info(fmt.Sprintf("Enqueing reconciliation from cluster secret labels to gateway/%s", gateway.Name))
+		q.Add(ctrl.Request{
+			NamespacedName: client.ObjectKeyFromObject(&gateway),
+		})
+	}
 }
 
 func (eh *ClusterEventHandler) getGatewaysFor(ctx context.Context, secret *corev1.Secret) ([]gatewayapiv1.Gateway, error) {
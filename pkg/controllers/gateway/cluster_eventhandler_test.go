@@ -100,6 +100,26 @@ func TestClusterEventHandler(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "Queued by cluster labels",
+			scheme:   testutil.GetValidTestScheme(),
+			gateways: testGateway(),
+			secret: corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						clusterSecret.CLUSTER_SECRET_LABEL: clusterSecret.CLUSTER_SECRET_LABEL_VALUE,
+						"type":                             "test",
+					},
+					Name:      "cluster",
+					Namespace: testutil.Namespace,
+				},
+				Data: map[string][]byte{
+					"name":   []byte(testutil.Cluster),
+					"config": []byte(tlsConfig),
+				},
+			},
+			enqueuedGateways: testGateway(),
+		},
 		{
 			name:     "Not enqueued. Not a cluster secret",
 			scheme:   testutil.GetValidTestScheme(),
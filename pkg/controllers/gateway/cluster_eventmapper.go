@@ -6,12 +6,14 @@ import (
 
 	"github.com/go-logr/logr"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/metadata"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
 )
 
 // ClusterEventMapper is an EventHandler that maps Cluster object events to gateway events.
@@ -56,6 +58,13 @@ func (m *ClusterEventMapper) mapToGatewayRequest(ctx context.Context, obj client
 
 	requests := make([]reconcile.Request, 0)
 	for _, gw := range allGwList.Items {
+		// the cluster may now match the selector of a gateway that isn't
+		// placed on it. Gateways placed on it are requeued below, in case it
+		// no longer matches
+		if selector, err := placement.ClusterSelector(&gw); err == nil && selector != nil && selector.Matches(labels.Set(obj.GetLabels())) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gw)})
+			continue
+		}
 		val := metadata.GetAnnotation(&gw, GatewayClustersAnnotation)
 		if val == "" {
 			continue
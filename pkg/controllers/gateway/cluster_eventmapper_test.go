@@ -0,0 +1,79 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"github.com/go-logr/logr"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func TestClusterEventMapper(t *testing.T) {
+	gateway := func(name string, annotations map[string]string) *gatewayapiv1.Gateway {
+		return &gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testutil.Namespace, Annotations: annotations}}
+	}
+	client := fake.NewClientBuilder().WithScheme(testutil.GetBasicScheme()).WithObjects(
+		gateway("placed", map[string]string{GatewayClustersAnnotation: `["cluster-a"]`}),
+		gateway("selected", map[string]string{GatewayClusterLabelSelectorAnnotation: "region=eu"}),
+		gateway("not-selected", map[string]string{GatewayClusterLabelSelectorAnnotation: "region=us"}),
+		gateway("invalid-selector", map[string]string{GatewayClusterLabelSelectorAnnotation: "region in (eu"}),
+	).Build()
+	mapper := NewClusterEventMapper(logr.Discard(), client)
+
+	cases := []struct {
+		name     string
+		cluster  *clusterv1.ManagedCluster
+		expected sets.Set[string]
+	}{
+		{
+			name:     "placed and selected gateways",
+			cluster:  &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-a", Labels: map[string]string{"region": "eu"}}},
+			expected: sets.New("placed", "selected"),
+		},
+		{
+			name:     "newly selected gateways",
+			cluster:  &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-b", Labels: map[string]string{"region": "eu"}}},
+			expected: sets.New("selected"),
+		},
+		{
+			name:     "gateways no longer selected",
+			cluster:  &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-a", Labels: map[string]string{"region": "ap"}}},
+			expected: sets.New("placed"),
+		},
+		{
+			name: "cluster being deleted",
+			cluster: &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{
+				Name:              "cluster-a",
+				Labels:            map[string]string{"region": "eu"},
+				DeletionTimestamp: &metav1.Time{Time: time.Now()},
+			}},
+			expected: sets.New[string](),
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := sets.New[string]()
+			for _, request := range mapper.MapToGateway(context.Background(), testCase.cluster) {
+				if request.Namespace != testutil.Namespace {
+					t.Errorf("unexpected request %v", request.NamespacedName)
+				}
+				got.Insert(request.Name)
+			}
+			if !got.Equal(testCase.expected) {
+				t.Errorf("expected gateways %v, got %v", sets.List(testCase.expected), sets.List(got))
+			}
+		})
+	}
+}
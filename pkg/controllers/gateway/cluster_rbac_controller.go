@@ -0,0 +1,42 @@
+package gateway
+
+import (
+	"context"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+)
+
+// ClusterRBACReconciler reconciles the RBAC granted to the work agent of a
+// cluster as the ManifestWorks placed on it change, so that access is revoked
+// once the work agent has pruned the resources removed from a ManifestWork,
+// or a ManifestWork being deleted is gone
+type ClusterRBACReconciler struct {
+	client.Client
+}
+
+// Reconcile reconciles the RBAC of the cluster of the namespace of the request
+func (r *ClusterRBACReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	crlog.FromContext(ctx).V(3).Info("reconciling cluster rbac", "cluster", req.Namespace)
+	return ctrl.Result{}, placement.ReconcileClusterRBAC(ctx, r.Client, req.Namespace)
+}
+
+func (r *ClusterRBACReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("cluster-rbac").
+		Watches(&workv1.ManifestWork{}, handler.EnqueueRequestsFromMapFunc(func(_ context.Context, o client.Object) []reconcile.Request {
+			if o.GetLabels()[placement.ManagedWorkLabel] != "managed" {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: o.GetNamespace()}}}
+		})).
+		Complete(r)
+}
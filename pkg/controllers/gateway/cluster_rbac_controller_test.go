@@ -0,0 +1,65 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func TestClusterRBACReconciler(t *testing.T) {
+	scheme := testutil.GetBasicScheme()
+	if err := workv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	gatewayWork := &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "gateway-test-test",
+			Namespace:  "c1",
+			Labels:     map[string]string{placement.ManagedWorkLabel: "managed", placement.WorkManifestLabel: "gateway-test-test"},
+			Finalizers: []string{"cluster.open-cluster-management.io/manifest-work-cleanup"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gatewayWork).Build()
+	r := &ClusterRBACReconciler{Client: c}
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "c1"}}
+	rbacKey := client.ObjectKey{Name: "gateway-rbac", Namespace: "c1"}
+
+	// the rbac is kept while the gateway is being removed from the cluster
+	if err := c.Delete(context.TODO(), gatewayWork); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if _, err := r.Reconcile(context.TODO(), request); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := c.Get(context.TODO(), rbacKey, &workv1.ManifestWork{}); err != nil {
+		t.Fatalf("expected the rbac manifest work while the gateway is removed, got %s", err)
+	}
+
+	// and removed once the work agent has removed it
+	if err := c.Get(context.TODO(), client.ObjectKeyFromObject(gatewayWork), gatewayWork); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	gatewayWork.Finalizers = nil
+	if err := c.Update(context.TODO(), gatewayWork); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if _, err := r.Reconcile(context.TODO(), request); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := c.Get(context.TODO(), rbacKey, &workv1.ManifestWork{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected the rbac manifest work to be removed, got %v", err)
+	}
+}
@@ -27,11 +27,13 @@ import (
 
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta1"
+	clustersetv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 	workv1 "open-cluster-management.io/api/work/v1"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -51,6 +53,7 @@ import (
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/gracePeriod"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/metadata"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/policysync"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/utils"
 )
@@ -58,8 +61,8 @@ import (
 const (
 	LabelPrefix                           = "kuadrant.io/"
 	ClustersLabelPrefix                   = "clusters." + LabelPrefix
-	GatewayClusterLabelSelectorAnnotation = LabelPrefix + "gateway-cluster-label-selector"
-	GatewayClustersAnnotation             = LabelPrefix + "gateway-clusters"
+	GatewayClusterLabelSelectorAnnotation = placement.ClusterLabelSelectorAnnotation
+	GatewayClustersAnnotation             = placement.GatewayClustersAnnotation
 	GatewayFinalizer                      = LabelPrefix + "gateway"
 	ManagedLabel                          = LabelPrefix + "managed"
 )
@@ -67,7 +70,7 @@ const (
 type GatewayPlacer interface {
 	//Place will use the placement logic to create the needed resources and ensure the objects are synced to the targeted clusters
 	// it will return the set of clusters it has targeted
-	Place(ctx context.Context, upstream *gatewayapiv1.Gateway, downstream *gatewayapiv1.Gateway, children ...metav1.Object) (sets.Set[string], error)
+	Place(ctx context.Context, upstream *gatewayapiv1.Gateway, downstream *gatewayapiv1.Gateway, overrides placement.Overrides, clusterChildren placement.ClusterChildren, children ...metav1.Object) (sets.Set[string], error)
 	// gets the clusters the gateway has actually been placed on
 	GetPlacedClusters(ctx context.Context, gateway *gatewayapiv1.Gateway) (sets.Set[string], error)
 	//GetClusters returns the clusters decided on by the placement logic
@@ -76,6 +79,21 @@ type GatewayPlacer interface {
 	ListenerTotalAttachedRoutes(ctx context.Context, gateway *gatewayapiv1.Gateway, listenerName string, downstream string) (int, error)
 	// GetAddresses will look at the downstream view of the gateway and return the LB addresses used for these gateways
 	GetAddresses(ctx context.Context, gateway *gatewayapiv1.Gateway, downstream string) ([]gatewayapiv1.GatewayAddress, error)
+	// GetDownstreamStatus returns the conditions of the downstream gateway and of its listeners
+	GetDownstreamStatus(ctx context.Context, gateway *gatewayapiv1.Gateway, downstream string) (*gatewayapiv1.GatewayStatus, error)
+}
+
+// StatusPoller is implemented by placers that can't watch the status of the
+// downstream gateways, which is read back by reconciling the gateways placed
+// on clusters every StatusSyncPeriod
+type StatusPoller interface {
+	StatusSyncPeriod() time.Duration
+}
+
+// ClusterLabeler is implemented by placers that hold the labels of their
+// clusters, rather than relying on OCM ManagedClusters
+type ClusterLabeler interface {
+	GetClusterLabels(ctx context.Context, gateway *gatewayapiv1.Gateway, cluster string) (map[string]string, error)
 }
 
 // +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch;create;update;patch;delete
@@ -85,9 +103,11 @@ type GatewayPlacer interface {
 // +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=placementdecisions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;delete
 // +kubebuilder:rbac:groups="cert-manager.io",resources=certificates,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclusters;managedclustersets;managedclustersetbindings,verbs=get;list;watch
 
-// +kubebuilder:rbac:groups="kuadrant.io",resources=authpolicies;ratelimitpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups="kuadrant.io",resources=authpolicies;ratelimitpolicies,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="kuadrant.io",resources=authpolicies/status;ratelimitpolicies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="kuadrant.io",resources=dnshealthcheckprobes,verbs=get;list;watch
 
 // GatewayReconciler reconciles a Gateway object
 type GatewayReconciler struct {
@@ -97,6 +117,12 @@ type GatewayReconciler struct {
 	PolicyInformersManager *policysync.PolicyInformersManager
 	DynamicClient          dynamic.Interface
 	WatchedPolicies        map[schema.GroupVersionResource]cache.ResourceEventHandlerRegistration
+	// DisableOCM stops watching Open Cluster Management resources, for
+	// placers that don't use them
+	DisableOCM bool
+	// PolicySyncer syncs the policies targeting the gateways to their
+	// clusters. Policies are only logged when nil
+	PolicySyncer policysync.Syncer
 }
 
 func isDeleting(g *gatewayapiv1.Gateway) bool {
@@ -120,6 +146,7 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		if _, _, _, err := r.reconcileDownstreamFromUpstreamGateway(ctx, upstreamGateway, nil); client.IgnoreNotFound(err) != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to reconcile downstream gateway after upstream gateway deleted: %s ", err)
 		}
+		r.syncPolicies(ctx, upstreamGateway)
 		controllerutil.RemoveFinalizer(upstreamGateway, GatewayFinalizer)
 		if err := r.Update(ctx, upstreamGateway); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to remove finalizer from gateway : %s", err)
@@ -161,8 +188,8 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, fmt.Errorf("gateway class err %s ", err)
 	}
 	//if we get to the point where we are going to reconcile the gateway in to the downstream then the upstream gateway is considered accepted
-	// checking not true covers "unknown" and false
-	if !meta.IsStatusConditionTrue(upstreamGateway.Status.Conditions, string(gatewayapiv1.GatewayConditionAccepted)) {
+	// Accepted is only False when a downstream gateway isn't accepted, which is aggregated below
+	if acceptedCondition := meta.FindStatusCondition(upstreamGateway.Status.Conditions, string(gatewayapiv1.GatewayConditionAccepted)); acceptedCondition == nil || acceptedCondition.Status == metav1.ConditionUnknown {
 		log.V(3).Info("gateway is accepted setting initial programmed and accepted status")
 		acceptedCondition := buildAcceptedCondition(upstreamGateway.Generation, metav1.ConditionTrue)
 		programmedCondition := buildProgrammedCondition(upstreamGateway.Generation, []string{}, metav1.ConditionUnknown, nil)
@@ -180,7 +207,12 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		//TODO (cbrookes) refactor how status is handled in this controller
 		if errors.Is(reconcileErr, gracePeriod.ErrGracePeriodNotExpired) || requeue {
 			log.V(3).Info("requeueing gateway ", "error", reconcileErr, "requeue", requeue)
-			programmedCondition := buildProgrammedCondition(upstreamGateway.Generation, clusters, metav1.ConditionUnknown, reconcileErr)
+			status := metav1.ConditionUnknown
+			// a gateway too large to place won't be placed by retrying
+			if errors.Is(reconcileErr, placement.ErrWorkTooLarge) {
+				status = metav1.ConditionFalse
+			}
+			programmedCondition := buildProgrammedCondition(upstreamGateway.Generation, clusters, status, reconcileErr)
 			meta.SetStatusCondition(&upstreamGateway.Status.Conditions, programmedCondition)
 			if !isDeleting(upstreamGateway) && !reflect.DeepEqual(upstreamGateway.Status, previous.Status) {
 				return reconcile.Result{}, r.Status().Update(ctx, upstreamGateway)
@@ -210,6 +242,8 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return reconcile.Result{}, r.Update(ctx, upstreamGateway)
 	}
 
+	r.syncPolicies(ctx, upstreamGateway)
+
 	var addressErr error
 	allAddresses := []gatewayapiv1.GatewayStatusAddress{}
 	for _, cluster := range clusters {
@@ -257,8 +291,18 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 	upstreamGateway.Status.Listeners = allListenerStatuses
 
-	acceptedCondition := buildAcceptedCondition(upstreamGateway.Generation, metav1.ConditionTrue)
-	programmedCondition := buildProgrammedCondition(upstreamGateway.Generation, clusters, programmedStatus, err)
+	downstreamStatuses := map[string]*gatewayapiv1.GatewayStatus{}
+	for _, cluster := range clusters {
+		status, err := r.Placement.GetDownstreamStatus(ctx, upstreamGateway, cluster)
+		if err != nil {
+			log.Info("Status unknown for downstream gateway", "cluster", cluster, "message", err)
+			continue
+		}
+		downstreamStatuses[cluster] = status
+	}
+
+	acceptedCondition := aggregateDownstreamCondition(buildAcceptedCondition(upstreamGateway.Generation, metav1.ConditionTrue), clusters, downstreamStatuses)
+	programmedCondition := aggregateDownstreamCondition(buildProgrammedCondition(upstreamGateway.Generation, clusters, programmedStatus, err), clusters, downstreamStatuses)
 
 	meta.SetStatusCondition(&upstreamGateway.Status.Conditions, acceptedCondition)
 	meta.SetStatusCondition(&upstreamGateway.Status.Conditions, programmedCondition)
@@ -271,6 +315,9 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		log.V(3).Info("requeuing gateway in ", "namespace", upstreamGateway.Namespace, "with name", upstreamGateway.Name)
 		return ctrl.Result{Requeue: true, RequeueAfter: time.Second * 10}, reconcileErr
 	}
+	if poller, ok := r.Placement.(StatusPoller); ok && len(clusters) > 0 {
+		return ctrl.Result{RequeueAfter: poller.StatusSyncPeriod()}, reconcileErr
+	}
 	return ctrl.Result{}, reconcileErr
 }
 
@@ -285,12 +332,12 @@ func (r *GatewayReconciler) reconcileClusterLabels(ctx context.Context, gateway
 
 	//Add clusters.kuadrant.io labels for current clusters
 	for _, cluster := range clusters {
-		managedCluster := &clusterv1.ManagedCluster{}
-		if err := r.Client.Get(ctx, client.ObjectKey{Name: cluster}, managedCluster); client.IgnoreNotFound(err) != nil {
+		clusterLabels, err := r.getClusterLabels(ctx, gateway, cluster)
+		if err != nil {
 			return err
 		}
 
-		for key, value := range managedCluster.Labels {
+		for key, value := range clusterLabels {
 			attribute, found := strings.CutPrefix(key, LabelPrefix)
 			if !found {
 				continue
@@ -301,12 +348,25 @@ func (r *GatewayReconciler) reconcileClusterLabels(ctx context.Context, gateway
 	return nil
 }
 
+// getClusterLabels returns the labels of the cluster from the placer when it
+// holds them, or from the ManagedCluster otherwise
+func (r *GatewayReconciler) getClusterLabels(ctx context.Context, gateway *gatewayapiv1.Gateway, cluster string) (map[string]string, error) {
+	if labeler, ok := r.Placement.(ClusterLabeler); ok {
+		return labeler.GetClusterLabels(ctx, gateway, cluster)
+	}
+	managedCluster := &clusterv1.ManagedCluster{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: cluster}, managedCluster); client.IgnoreNotFound(err) != nil {
+		return nil, err
+	}
+	return managedCluster.Labels, nil
+}
+
 // reconcileDownstreamGateway takes the upstream definition and transforms it as needed to apply it to the downstream spokes
 func (r *GatewayReconciler) reconcileDownstreamFromUpstreamGateway(ctx context.Context, upstreamGateway *gatewayapiv1.Gateway, params *Params) (bool, metav1.ConditionStatus, []string, error) {
 	log := crlog.FromContext(ctx)
 	clusters := []string{}
 	downstream := upstreamGateway.DeepCopy()
-	downstreamNS := fmt.Sprintf("%s-%s", "kuadrant", downstream.Namespace)
+	downstreamNS := params.GetDownstreamNamespace(upstreamGateway.Namespace)
 	downstream.Status = gatewayapiv1.GatewayStatus{}
 
 	// reset this for the sync as we don't want control plane level UID, creation etc etc
@@ -322,7 +382,7 @@ func (r *GatewayReconciler) reconcileDownstreamFromUpstreamGateway(ctx context.C
 	downstream.Labels[ManagedLabel] = "true"
 	if isDeleting(upstreamGateway) {
 		log.Info("deleting downstream gateways owned by upstream gateway ", "name", downstream.Name, "namespace", downstream.Namespace)
-		targets, err := r.Placement.Place(ctx, upstreamGateway, downstream)
+		targets, err := r.Placement.Place(ctx, upstreamGateway, downstream, nil, nil)
 		if err != nil {
 			return false, metav1.ConditionFalse, clusters, err
 		}
@@ -333,12 +393,6 @@ func (r *GatewayReconciler) reconcileDownstreamFromUpstreamGateway(ctx context.C
 		return false, metav1.ConditionFalse, clusters, fmt.Errorf("no managed listeners found")
 	}
 
-	// get tls secrets for all TLS listeners.
-	tlsSecrets, err := r.getTLSSecrets(ctx, upstreamGateway, downstream)
-	if err != nil {
-		return true, metav1.ConditionFalse, clusters, fmt.Errorf("failed to get tls secrets : %s", err)
-	}
-
 	// some of this should be pulled from gateway class params
 	if params != nil {
 		if err := r.reconcileParams(ctx, downstream, params); err != nil {
@@ -346,8 +400,32 @@ func (r *GatewayReconciler) reconcileDownstreamFromUpstreamGateway(ctx context.C
 		}
 	}
 
+	children := []metav1.Object{}
+	if params.OwnsDownstreamNamespace() {
+		children = append(children, &corev1.Namespace{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: downstreamNS},
+		})
+	}
+
+	// get tls secrets for the TLS listeners of the gateway placed on each
+	// cluster, including the listeners added by its overrides
+	tlsSecrets := func(ctx context.Context, _ string, gateway *gatewayapiv1.Gateway) ([]metav1.Object, error) {
+		secrets, err := r.getTLSSecrets(ctx, upstreamGateway, gateway)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tls secrets : %s", err)
+		}
+		return secrets, nil
+	}
+
+	// hold the clusters of the later waves of a rollout on their current revision
+	overrides, clusterChildren, rollingOut, err := r.rollout(ctx, upstreamGateway, downstream, params, r.clusterOverrides(upstreamGateway, params), tlsSecrets)
+	if err != nil {
+		return true, metav1.ConditionFalse, clusters, fmt.Errorf("failed to roll out gateway : %w", err)
+	}
+
 	// ensure the gateways are placed into the right target clusters and removed from any that are no longer targeted
-	targets, err := r.Placement.Place(ctx, upstreamGateway, downstream, tlsSecrets...)
+	targets, err := r.Placement.Place(ctx, upstreamGateway, downstream, overrides, clusterChildren, children...)
 	if err != nil {
 		return true, metav1.ConditionFalse, clusters, fmt.Errorf("failed to place gateway : %w", err)
 	}
@@ -361,17 +439,44 @@ func (r *GatewayReconciler) reconcileDownstreamFromUpstreamGateway(ctx context.C
 	//update the cluster set, needs to be ordered or the status update can continually change and cause spurious updates
 	clusters = sets.List(placed)
 	if placed.Equal(targets) && placed.Len() > 0 {
-		return false, metav1.ConditionTrue, clusters, nil
+		return rollingOut, metav1.ConditionTrue, clusters, nil
 	}
 	log.Info("Gateway Reconciled Successfully ", "gateway", upstreamGateway.Name, "namespace", upstreamGateway.Namespace)
-	return false, metav1.ConditionUnknown, clusters, nil
+	return rollingOut, metav1.ConditionUnknown, clusters, nil
 }
 
+// DownstreamGateway returns the clusters the upstream gateway is placed on, and
+// the name of its downstream gateway on them. Gateways that aren't managed by
+// this controller, or are being deleted, aren't placed on any cluster
+func DownstreamGateway(ctx context.Context, c client.Client, upstreamGateway *gatewayapiv1.Gateway) ([]string, types.NamespacedName, error) {
+	downstream := types.NamespacedName{Name: upstreamGateway.Name}
+	if isDeleting(upstreamGateway) || !slice.ContainsString(getSupportedClasses(), string(upstreamGateway.Spec.GatewayClassName)) {
+		return nil, downstream, nil
+	}
+
+	params, err := getParams(ctx, c, string(upstreamGateway.Spec.GatewayClassName))
+	if err != nil {
+		return nil, downstream, err
+	}
+	downstream.Namespace = params.GetDownstreamNamespace(upstreamGateway.Namespace)
+
+	clusters := []string{}
+	if value := metadata.GetAnnotation(upstreamGateway, GatewayClustersAnnotation); value != "" {
+		if err := json.Unmarshal([]byte(value), &clusters); err != nil {
+			return nil, downstream, fmt.Errorf("invalid %s annotation: %w", GatewayClustersAnnotation, err)
+		}
+	}
+	return clusters, downstream, nil
+}
+
+// getTLSSecrets returns the TLS secrets referenced by the listeners of the
+// downstream gateway, copied to its namespace. References without a namespace
+// are relative to the namespace of the upstream gateway
 func (r *GatewayReconciler) getTLSSecrets(ctx context.Context, upstreamGateway *gatewayapiv1.Gateway, downstreamGateway *gatewayapiv1.Gateway) ([]metav1.Object, error) {
 	log := crlog.FromContext(ctx)
 	tlsSecrets := []metav1.Object{}
 	var listenerTLSErr error
-	for _, listener := range upstreamGateway.Spec.Listeners {
+	for _, listener := range downstreamGateway.Spec.Listeners {
 		if listener.TLS != nil {
 			for _, secretRef := range listener.TLS.CertificateRefs {
 				ns := upstreamGateway.GetNamespace()
@@ -429,7 +534,7 @@ func (r *GatewayReconciler) reconcileParams(ctx context.Context, gateway *gatewa
 			Client:        r.Client,
 			DynamicClient: r.DynamicClient,
 			Gateway:       gateway,
-			Syncer:        &policysync.FakeSyncer{},
+			Syncer:        r.policySyncer(),
 		}
 		informer := r.PolicyInformersManager.InformerFactory.ForResource(gvr).Informer()
 		reg, err := informer.AddEventHandler(eventHandler)
@@ -469,6 +574,51 @@ func (r *GatewayReconciler) reconcileParams(ctx context.Context, gateway *gatewa
 	return nil
 }
 
+func (r *GatewayReconciler) policySyncer() policysync.Syncer {
+	if r.PolicySyncer == nil {
+		return &policysync.FakeSyncer{}
+	}
+	return r.PolicySyncer
+}
+
+// syncPolicies syncs the watched policies targeting the gateway, so that they
+// follow the gateway when it's placed on or removed from clusters
+func (r *GatewayReconciler) syncPolicies(ctx context.Context, upstreamGateway *gatewayapiv1.Gateway) {
+	log := crlog.FromContext(ctx)
+	if r.PolicyInformersManager == nil {
+		return
+	}
+
+	for gvr := range r.WatchedPolicies {
+		objs, err := r.PolicyInformersManager.InformerFactory.ForResource(gvr).Lister().List(labels.Everything())
+		if err != nil {
+			log.Error(err, "failed to list policies to sync", "gvr", gvr)
+			continue
+		}
+		for _, obj := range objs {
+			policy, err := policysync.NewPolicyFor(obj.DeepCopyObject())
+			if err != nil || !targetsGateway(policy, upstreamGateway) {
+				continue
+			}
+			if err := r.policySyncer().SyncPolicy(ctx, r.Client, policy); err != nil {
+				log.Error(err, "failed to sync policy", "gvr", gvr, "policy", policy.GetName(), "namespace", policy.GetNamespace())
+			}
+		}
+	}
+}
+
+func targetsGateway(policy policysync.Policy, gateway *gatewayapiv1.Gateway) bool {
+	targetRef := policy.GetTargetRef()
+	if targetRef == nil || string(targetRef.Group) != gatewayapiv1.GroupName || string(targetRef.Kind) != "Gateway" {
+		return false
+	}
+	namespace := policy.GetNamespace()
+	if targetRef.Namespace != nil {
+		namespace = string(*targetRef.Namespace)
+	}
+	return string(targetRef.Name) == gateway.Name && namespace == gateway.Namespace
+}
+
 func buildProgrammedCondition(generation int64, placed []string, programmedStatus metav1.ConditionStatus, err error) metav1.Condition {
 	var reason = gatewayapiv1.GatewayReasonProgrammed
 	message := "waiting for gateway to placed on clusters %v"
@@ -514,32 +664,13 @@ func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager, ctx context.Conte
 	log := crlog.FromContext(ctx)
 	clusterEventMapper := NewClusterEventMapper(log, mgr.GetClient())
 	//TODO need to trigger gateway reconcile when gatewayclass params changes
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&gatewayapiv1.Gateway{}).
-		Watches(&workv1.ManifestWork{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
-			log.V(3).Info("enqueuing gateways based on manifest work change ", "work namespace", o.GetNamespace())
-			requests := []reconcile.Request{}
-			annotations := o.GetAnnotations()
-			if annotations == nil {
-				log.V(3).Info("no parent or annotations on manifest work ", "work ns", o.GetNamespace(), "name", o.GetName())
-				return requests
-			}
-			key := annotations["kuadrant.io/parent"]
-			ns, name, err := cache.SplitMetaNamespaceKey(key)
-			if err != nil {
-				log.Error(err, "failed to parse namespace and name from manifest work")
-				return requests
-			}
-			log.Info("requeuing gateway ", "namespace", ns, "name", name)
-			requests = append(requests, reconcile.Request{
-				NamespacedName: types.NamespacedName{Namespace: ns, Name: name},
-			})
-			return requests
-		}), builder.OnlyMetadata).
-		Watches(&clusterv1beta2.PlacementDecision{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
-			// kinda want to get the old and new object here and only queue if the clusters have changed
-			// queue up gateways in this namespace
-			log.V(3).Info("enqueuing gateways based on placementdecision change ", " namespace", o.GetNamespace())
+		Watches(&corev1.Secret{}, &ClusterEventHandler{client: r.Client})
+	if !r.DisableOCM {
+		// queue up gateways in the namespace of the object
+		namespaceGateways := func(ctx context.Context, o client.Object) []reconcile.Request {
+			log.V(3).Info("enqueuing gateways based on change ", "kind", fmt.Sprintf("%T", o), " namespace", o.GetNamespace())
 			req := []reconcile.Request{}
 			l := &gatewayapiv1.GatewayList{}
 			if err := mgr.GetClient().List(ctx, l, &client.ListOptions{Namespace: o.GetNamespace()}); err != nil {
@@ -552,12 +683,39 @@ func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager, ctx context.Conte
 				})
 			}
 			return req
-		})).
-		Watches(&corev1.Secret{}, &ClusterEventHandler{client: r.Client}).
-		Watches(
-			&clusterv1.ManagedCluster{},
-			handler.EnqueueRequestsFromMapFunc(clusterEventMapper.MapToGateway),
-		).
+		}
+		b = b.
+			Watches(&workv1.ManifestWork{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+				log.V(3).Info("enqueuing gateways based on manifest work change ", "work namespace", o.GetNamespace())
+				requests := []reconcile.Request{}
+				parents := placement.WorkParents(o)
+				if len(parents) == 0 {
+					log.V(3).Info("no parent or annotations on manifest work ", "work ns", o.GetNamespace(), "name", o.GetName())
+					return requests
+				}
+				for _, key := range parents {
+					ns, name, err := cache.SplitMetaNamespaceKey(key)
+					if err != nil {
+						log.Error(err, "failed to parse namespace and name from manifest work")
+						continue
+					}
+					log.Info("requeuing gateway ", "namespace", ns, "name", name)
+					requests = append(requests, reconcile.Request{
+						NamespacedName: types.NamespacedName{Namespace: ns, Name: name},
+					})
+				}
+				return requests
+			}), builder.OnlyMetadata).
+			// kinda want to get the old and new object here and only queue if the clusters have changed
+			Watches(&clusterv1beta2.PlacementDecision{}, handler.EnqueueRequestsFromMapFunc(namespaceGateways)).
+			// the clusters selected by the cluster label selector of the gateways in the namespace of the binding may change
+			Watches(&clustersetv1beta2.ManagedClusterSetBinding{}, handler.EnqueueRequestsFromMapFunc(namespaceGateways)).
+			Watches(
+				&clusterv1.ManagedCluster{},
+				handler.EnqueueRequestsFromMapFunc(clusterEventMapper.MapToGateway),
+			)
+	}
+	return b.
 		WithEventFilter(predicate.NewPredicateFuncs(func(object client.Object) bool {
 			gateway, ok := object.(*gatewayapiv1.Gateway)
 			if ok {
@@ -386,6 +386,17 @@ func TestGatewayReconciler_getTLSSecrets(t *testing.T) {
 								Name:     testutil.ValidTestHostname,
 								Hostname: testutil.Pointer(gatewayapiv1.Hostname(testutil.ValidTestHostname)),
 								Protocol: gatewayapiv1.HTTPSProtocolType,
+								TLS: &gatewayapiv1.GatewayTLSConfig{
+									Mode: testutil.Pointer(gatewayapiv1.TLSModeTerminate),
+									CertificateRefs: []gatewayapiv1.SecretObjectReference{
+										{
+											Group:     testutil.Pointer(gatewayapiv1.Group("")),
+											Kind:      testutil.Pointer(gatewayapiv1.Kind("secret")),
+											Name:      testutil.TLSSecretName,
+											Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+										},
+									},
+								},
 							},
 						},
 					},
@@ -439,6 +450,17 @@ func TestGatewayReconciler_getTLSSecrets(t *testing.T) {
 								Name:     testutil.ValidTestHostname,
 								Hostname: testutil.Pointer(gatewayapiv1.Hostname(testutil.ValidTestHostname)),
 								Protocol: gatewayapiv1.HTTPSProtocolType,
+								TLS: &gatewayapiv1.GatewayTLSConfig{
+									Mode: testutil.Pointer(gatewayapiv1.TLSModeTerminate),
+									CertificateRefs: []gatewayapiv1.SecretObjectReference{
+										{
+											Group:     testutil.Pointer(gatewayapiv1.Group("")),
+											Kind:      testutil.Pointer(gatewayapiv1.Kind("secret")),
+											Name:      testutil.TLSSecretName,
+											Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+										},
+									},
+								},
 							},
 						},
 					},
@@ -447,6 +469,46 @@ func TestGatewayReconciler_getTLSSecrets(t *testing.T) {
 			want:    []v1.Object{},
 			wantErr: true,
 		},
+		{
+			name: "returns secret for HTTPS listener only on the downstream gateway",
+			fields: fields{
+				Client: testutil.GetValidTestClient(getValidTLSCertificateSecretList(testutil.TLSSecretName, testutil.Namespace)),
+				Scheme: testutil.GetValidTestScheme(),
+			},
+			args: args{
+				upstreamGateway: &gatewayapiv1.Gateway{
+					ObjectMeta: v1.ObjectMeta{
+						Namespace: testutil.Namespace,
+						Name:      testutil.DummyCRName,
+					},
+				},
+				downstreamGateway: &gatewayapiv1.Gateway{
+					ObjectMeta: v1.ObjectMeta{
+						Namespace: testutil.Namespace + "-downstream",
+						Name:      testutil.DummyCRName,
+					},
+					Spec: gatewayapiv1.GatewaySpec{
+						Listeners: []gatewayapiv1.Listener{
+							{
+								Name:     testutil.ValidTestHostname,
+								Hostname: testutil.Pointer(gatewayapiv1.Hostname(testutil.ValidTestHostname)),
+								Protocol: gatewayapiv1.HTTPSProtocolType,
+								TLS: &gatewayapiv1.GatewayTLSConfig{
+									Mode: testutil.Pointer(gatewayapiv1.TLSModeTerminate),
+									CertificateRefs: []gatewayapiv1.SecretObjectReference{
+										{
+											Name: testutil.TLSSecretName,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want:    []v1.Object{&getValidTLSCertificateSecretList(testutil.TLSSecretName, testutil.Namespace+"-downstream").Items[0]},
+			wantErr: false,
+		},
 		{
 			name: "returns empty list for HTTP listener",
 			fields: fields{
@@ -0,0 +1,134 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+)
+
+type ClusterOverridePatchType string
+
+const (
+	StrategicMergePatchType ClusterOverridePatchType = "StrategicMerge"
+	JSONPatchType           ClusterOverridePatchType = "JSON"
+)
+
+// ClusterOverride patches the downstream gateway placed on the clusters
+// matching its selector. For example, to use a different gateway class on
+// the clusters of one cloud:
+//
+//	{
+//	  "clusterSelector": {"matchLabels": {"cloud": "aws"}},
+//	  "patch": {"spec": {"gatewayClassName": "aws-lb"}}
+//	}
+type ClusterOverride struct {
+	// ClusterSelector selects the clusters the patch is applied to by their
+	// labels. An empty selector selects every cluster
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector"`
+
+	// Type of the patch, StrategicMerge by default. Lists, such as the
+	// listeners, are replaced by a strategic merge patch; use a JSON patch
+	// to add to them
+	Type ClusterOverridePatchType `json:"type,omitempty"`
+
+	// Patch applied to the downstream gateway
+	Patch json.RawMessage `json:"patch"`
+}
+
+func (o ClusterOverride) validate() error {
+	if _, err := metav1.LabelSelectorAsSelector(&o.ClusterSelector); err != nil {
+		return fmt.Errorf("invalid cluster selector: %w", err)
+	}
+	switch o.Type {
+	case "", StrategicMergePatchType:
+	case JSONPatchType:
+		if _, err := jsonpatch.DecodePatch(o.Patch); err != nil {
+			return fmt.Errorf("invalid JSON patch: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported patch type %s, must be one of [%s %s]", o.Type, StrategicMergePatchType, JSONPatchType)
+	}
+	if len(o.Patch) == 0 {
+		return fmt.Errorf("patch must be defined")
+	}
+	return nil
+}
+
+// apply patches the downstream gateway. The name, namespace and labels
+// identifying the downstream gateway can't be overridden
+func (o ClusterOverride) apply(downstream *gatewayapiv1.Gateway) (*gatewayapiv1.Gateway, error) {
+	original, err := json.Marshal(downstream)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch o.Type {
+	case JSONPatchType:
+		patch, err := jsonpatch.DecodePatch(o.Patch)
+		if err != nil {
+			return nil, err
+		}
+		patched, err = patch.Apply(original)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		patched, err = strategicpatch.StrategicMergePatch(original, o.Patch, gatewayapiv1.Gateway{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &gatewayapiv1.Gateway{}
+	if err := json.Unmarshal(patched, result); err != nil {
+		return nil, err
+	}
+	result.Name = downstream.Name
+	result.Namespace = downstream.Namespace
+	if result.Labels == nil {
+		result.Labels = map[string]string{}
+	}
+	result.Labels[ManagedLabel] = downstream.Labels[ManagedLabel]
+	return result, nil
+}
+
+// clusterOverrides returns the overrides placing the downstream gateway
+// patched by the params overrides selecting each cluster, in order. It's nil
+// when there are no overrides, placing the same gateway on every cluster
+func (r *GatewayReconciler) clusterOverrides(upstreamGateway *gatewayapiv1.Gateway, params *Params) placement.Overrides {
+	if params == nil || len(params.Overrides) == 0 {
+		return nil
+	}
+
+	return func(ctx context.Context, cluster string, downstream *gatewayapiv1.Gateway) (*gatewayapiv1.Gateway, error) {
+		clusterLabels, err := r.getClusterLabels(ctx, upstreamGateway, cluster)
+		if err != nil {
+			return nil, err
+		}
+
+		result := downstream
+		for i, override := range params.Overrides {
+			selector, err := metav1.LabelSelectorAsSelector(&override.ClusterSelector)
+			if err != nil {
+				return nil, fmt.Errorf("override %d: invalid cluster selector: %w", i, err)
+			}
+			if !selector.Matches(labels.Set(clusterLabels)) {
+				continue
+			}
+			if result, err = override.apply(result); err != nil {
+				return nil, fmt.Errorf("override %d: failed to patch gateway for cluster %s: %w", i, cluster, err)
+			}
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,137 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func TestClusterOverrides(t *testing.T) {
+	scheme := testutil.GetBasicScheme()
+	if err := clusterv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	managedCluster := func(name, cloud string) *clusterv1.ManagedCluster {
+		return &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"cloud": cloud}}}
+	}
+	r := &GatewayReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			managedCluster("aws-cluster", "aws"),
+			managedCluster("gcp-cluster", "gcp"),
+		).Build(),
+	}
+
+	upstream := &gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "test"}}
+	downstream := &gatewayapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: "kuadrant-test",
+			Labels:    map[string]string{ManagedLabel: "true"},
+		},
+		Spec: gatewayapiv1.GatewaySpec{
+			GatewayClassName: "istio",
+			Listeners:        []gatewayapiv1.Listener{{Name: "api", Port: 443, Protocol: gatewayapiv1.HTTPSProtocolType}},
+		},
+	}
+	params := &Params{
+		DownstreamClass: "istio",
+		Overrides: []ClusterOverride{
+			{
+				ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"cloud": "aws"}},
+				Patch:           json.RawMessage(`{"metadata":{"name":"renamed","annotations":{"lb":"nlb"}},"spec":{"gatewayClassName":"aws-lb"}}`),
+			},
+			{
+				ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"cloud": "aws"}},
+				Type:            JSONPatchType,
+				Patch:           json.RawMessage(`[{"op":"add","path":"/spec/listeners/-","value":{"name":"internal","port":8080,"protocol":"HTTP"}}]`),
+			},
+		},
+	}
+
+	if overrides := r.clusterOverrides(upstream, &Params{DownstreamClass: "istio"}); overrides != nil {
+		t.Errorf("expected no overrides without params overrides")
+	}
+
+	overrides := r.clusterOverrides(upstream, params)
+	aws, err := overrides(context.Background(), "aws-cluster", downstream)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if aws.Spec.GatewayClassName != "aws-lb" || aws.Annotations["lb"] != "nlb" || len(aws.Spec.Listeners) != 2 {
+		t.Errorf("expected the aws gateway to be patched, got %v %v", aws.Annotations, aws.Spec)
+	}
+	if aws.Name != "gateway" || aws.Namespace != "kuadrant-test" || aws.Labels[ManagedLabel] != "true" {
+		t.Errorf("expected the aws gateway to keep its identity, got %s/%s %v", aws.Namespace, aws.Name, aws.Labels)
+	}
+	if downstream.Spec.GatewayClassName != "istio" || len(downstream.Spec.Listeners) != 1 {
+		t.Errorf("expected the common downstream gateway not to be modified, got %v", downstream.Spec)
+	}
+
+	gcp, err := overrides(context.Background(), "gcp-cluster", downstream)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if gcp.Spec.GatewayClassName != "istio" || len(gcp.Spec.Listeners) != 1 {
+		t.Errorf("expected the gcp gateway not to be patched, got %v", gcp.Spec)
+	}
+
+	params.Overrides = []ClusterOverride{{Type: JSONPatchType, Patch: json.RawMessage(`[{"op":"remove","path":"/spec/missing"}]`)}}
+	if _, err := r.clusterOverrides(upstream, params)(context.Background(), "gcp-cluster", downstream); err == nil {
+		t.Errorf("expected an error for a patch that can't be applied")
+	}
+}
+
+func TestClusterOverrideValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		override ClusterOverride
+		valid    bool
+	}{
+		{
+			name:     "strategic merge patch",
+			override: ClusterOverride{Patch: json.RawMessage(`{"spec":{"gatewayClassName":"aws-lb"}}`)},
+			valid:    true,
+		},
+		{
+			name:     "JSON patch",
+			override: ClusterOverride{Type: JSONPatchType, Patch: json.RawMessage(`[{"op":"replace","path":"/spec/gatewayClassName","value":"aws-lb"}]`)},
+			valid:    true,
+		},
+		{
+			name:     "invalid JSON patch",
+			override: ClusterOverride{Type: JSONPatchType, Patch: json.RawMessage(`{"spec":{}}`)},
+		},
+		{
+			name:     "unsupported type",
+			override: ClusterOverride{Type: "Merge", Patch: json.RawMessage(`{}`)},
+		},
+		{
+			name:     "missing patch",
+			override: ClusterOverride{},
+		},
+		{
+			name: "invalid selector",
+			override: ClusterOverride{
+				ClusterSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "cloud", Operator: "Near"}}},
+				Patch:           json.RawMessage(`{}`),
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if err := testCase.override.validate(); (err == nil) != testCase.valid {
+				t.Errorf("expected valid %t, got %v", testCase.valid, err)
+			}
+		})
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
@@ -21,8 +23,37 @@ type Params struct {
 	// PoliciesToSync specifies a listof Policy GVRs that will be watched
 	// in the hub and synced to the spokes
 	PoliciesToSync []ParamsGroupVersionResource `json:"experimentalPolicySync,omitempty"`
+
+	// Overrides patch the downstream gateway placed on the clusters they
+	// select, in order
+	Overrides []ClusterOverride `json:"overrides,omitempty"`
+
+	// DownstreamNamespace specifies the namespace of the downstream gateway
+	// in the downstream clusters, where "{namespace}" is replaced by the
+	// namespace of the upstream gateway. Defaults to "kuadrant-{namespace}"
+	DownstreamNamespace string `json:"downstreamNamespace,omitempty"`
+
+	// DownstreamNamespaceMode specifies whether the downstream namespace is
+	// created and owned by the placement (Owned, the default), or expected
+	// to exist already and left untouched (Existing)
+	DownstreamNamespaceMode DownstreamNamespaceMode `json:"downstreamNamespaceMode,omitempty"`
+
+	// Rollout rolls changes to the downstream gateway out to the clusters
+	// in waves. Without it, changes are placed on every cluster at once
+	Rollout *RolloutStrategy `json:"rollout,omitempty"`
 }
 
+type DownstreamNamespaceMode string
+
+const (
+	OwnedNamespaceMode    DownstreamNamespaceMode = "Owned"
+	ExistingNamespaceMode DownstreamNamespaceMode = "Existing"
+
+	DefaultDownstreamNamespace = "kuadrant-{namespace}"
+
+	namespacePlaceholder = "{namespace}"
+)
+
 type ParamsGroupVersionResource struct {
 	Group    string `json:"group"`
 	Version  string `json:"version"`
@@ -41,6 +72,36 @@ func (p *Params) GetDownstreamClass() string {
 	return p.DownstreamClass
 }
 
+// GetDownstreamNamespace returns the namespace of the downstream gateway of
+// an upstream gateway in the given namespace
+func (p *Params) GetDownstreamNamespace(namespace string) string {
+	template := DefaultDownstreamNamespace
+	if p != nil && p.DownstreamNamespace != "" {
+		template = p.DownstreamNamespace
+	}
+	return strings.ReplaceAll(template, namespacePlaceholder, namespace)
+}
+
+// OwnsDownstreamNamespace returns whether the downstream namespace is placed
+// along with the downstream gateway
+func (p *Params) OwnsDownstreamNamespace() bool {
+	return p == nil || p.DownstreamNamespaceMode != ExistingNamespaceMode
+}
+
+func (p *Params) validateDownstreamNamespace() error {
+	switch p.DownstreamNamespaceMode {
+	case "", OwnedNamespaceMode, ExistingNamespaceMode:
+	default:
+		return fmt.Errorf("unsupported downstreamNamespaceMode %s, must be one of [%s %s]", p.DownstreamNamespaceMode, OwnedNamespaceMode, ExistingNamespaceMode)
+	}
+	// the namespace of the upstream gateway is itself a valid label, so
+	// validating with one validates the template
+	if errs := validation.IsDNS1123Label(p.GetDownstreamNamespace("namespace")); len(errs) > 0 {
+		return fmt.Errorf("invalid downstreamNamespace %s: %s", p.DownstreamNamespace, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
 var defaultParams Params = Params{
 	DownstreamClass: "istio",
 }
@@ -100,6 +161,19 @@ func fromConfigMap(configMap *corev1.ConfigMap) (*Params, error) {
 	if err := json.Unmarshal([]byte(paramsRaw), result); err != nil {
 		return nil, &InvalidParamsError{fmt.Sprintf("Failed to unmarshal params: %v", err)}
 	}
+	if err := result.validateDownstreamNamespace(); err != nil {
+		return nil, &InvalidParamsError{err.Error()}
+	}
+	for i, override := range result.Overrides {
+		if err := override.validate(); err != nil {
+			return nil, &InvalidParamsError{fmt.Sprintf("Invalid override %d: %v", i, err)}
+		}
+	}
+	if result.Rollout != nil {
+		if err := result.Rollout.validate(); err != nil {
+			return nil, &InvalidParamsError{fmt.Sprintf("Invalid rollout: %v", err)}
+		}
+	}
 
 	return result, nil
 }
@@ -125,6 +125,29 @@ func TestGetParams(t *testing.T) {
 			},
 			assertParams: assertError(IsInvalidParamsError),
 		},
+		{
+			name: "Invalid downstream namespace",
+			gatewayClass: &gatewayapiv1.GatewayClass{
+				Spec: gatewayapiv1.GatewayClassSpec{
+					ParametersRef: &gatewayapiv1.ParametersReference{
+						Group:     "",
+						Kind:      "ConfigMap",
+						Name:      testutil.DummyCRName,
+						Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+					},
+				},
+			},
+			paramsObj: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testutil.DummyCRName,
+					Namespace: testutil.Namespace,
+				},
+				Data: map[string]string{
+					"params": `{"downstreamClass": "istio", "downstreamNamespace": "Kuadrant_{namespace}"}`,
+				},
+			},
+			assertParams: assertError(IsInvalidParamsError),
+		},
 		{
 			name: "Missing namespace",
 			gatewayClass: &gatewayapiv1.GatewayClass{
@@ -221,6 +244,66 @@ func TestGetParams(t *testing.T) {
 	}
 }
 
+func TestParamsDownstreamNamespace(t *testing.T) {
+	cases := []struct {
+		name              string
+		params            *Params
+		expectedNamespace string
+		expectedOwned     bool
+		valid             bool
+	}{
+		{
+			name:              "default",
+			params:            &Params{},
+			expectedNamespace: "kuadrant-test",
+			expectedOwned:     true,
+			valid:             true,
+		},
+		{
+			name:              "no params",
+			expectedNamespace: "kuadrant-test",
+			expectedOwned:     true,
+			valid:             true,
+		},
+		{
+			name:              "same namespace",
+			params:            &Params{DownstreamNamespace: "{namespace}", DownstreamNamespaceMode: ExistingNamespaceMode},
+			expectedNamespace: "test",
+			valid:             true,
+		},
+		{
+			name:              "fixed namespace",
+			params:            &Params{DownstreamNamespace: "gateways", DownstreamNamespaceMode: OwnedNamespaceMode},
+			expectedNamespace: "gateways",
+			expectedOwned:     true,
+			valid:             true,
+		},
+		{
+			name:              "unsupported mode",
+			params:            &Params{DownstreamNamespaceMode: "Shared"},
+			expectedNamespace: "kuadrant-test",
+			expectedOwned:     true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := testCase.params.GetDownstreamNamespace("test"); got != testCase.expectedNamespace {
+				t.Errorf("expected namespace %s, got %s", testCase.expectedNamespace, got)
+			}
+			if got := testCase.params.OwnsDownstreamNamespace(); got != testCase.expectedOwned {
+				t.Errorf("expected owned %t, got %t", testCase.expectedOwned, got)
+			}
+			if testCase.params == nil {
+				return
+			}
+			if err := testCase.params.validateDownstreamNamespace(); (err == nil) != testCase.valid {
+				t.Errorf("expected valid %t, got %v", testCase.valid, err)
+			}
+		})
+	}
+}
+
 // Assertion utils
 
 func and(assertions ...func(*Params, error) error) func(*Params, error) error {
@@ -0,0 +1,375 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/metadata"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+)
+
+const (
+	// RolloutRevisionAnnotation records the revision of the downstream
+	// gateway placed on a cluster
+	RolloutRevisionAnnotation = "kuadrant.io/rollout-revision"
+
+	// RolloutProgrammedAnnotation records on the upstream gateway when the
+	// clusters were first seen programmed with the latest revision, so that
+	// only the health checks made since then advance the rollout
+	RolloutProgrammedAnnotation = "kuadrant.io/rollout-programmed"
+
+	// GatewayConditionRolledOut reports the progress of rolling the latest
+	// revision of the downstream gateway out to the clusters
+	GatewayConditionRolledOut = "RolledOut"
+
+	GatewayReasonRolloutComplete    = "RolloutComplete"
+	GatewayReasonRolloutProgressing = "RolloutProgressing"
+	GatewayReasonRolloutHalted      = "RolloutHalted"
+
+	// the labels of the health check probes created for a gateway by its
+	// DNSPolicy
+	probeGatewayLabel          = "kuadrant.io/gateway"
+	probeGatewayNamespaceLabel = "kuadrant.io/gateway-namespace"
+)
+
+// RolloutStrategy rolls changes to the downstream gateway out to the clusters
+// in waves. A wave only starts once the downstream gateways of the previous
+// waves are programmed and their health checks are healthy. For example, to
+// roll out to one canary cluster, then to the rest two at a time:
+//
+//	{
+//	  "waves": [{"clusterSelector": {"matchLabels": {"canary": "true"}}}],
+//	  "maxUnavailable": 2
+//	}
+type RolloutStrategy struct {
+	// Waves select the clusters of each wave by their labels, in order. A
+	// cluster belongs to the first wave selecting it, and clusters not
+	// selected by any wave are rolled out to last
+	Waves []RolloutWave `json:"waves,omitempty"`
+
+	// MaxUnavailable splits each wave into waves of at most this many
+	// clusters, by name. Zero doesn't split the waves
+	MaxUnavailable int `json:"maxUnavailable,omitempty"`
+}
+
+type RolloutWave struct {
+	// ClusterSelector selects the clusters of the wave by their labels
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector"`
+}
+
+// PlacedGatewayGetter is implemented by placers that can return the
+// downstream gateway, and its children, currently placed on a cluster, which
+// is required to hold the clusters of later waves on their revision during a
+// rollout
+type PlacedGatewayGetter interface {
+	GetPlacedGateway(ctx context.Context, gateway *gatewayapiv1.Gateway, cluster string) (*gatewayapiv1.Gateway, error)
+	GetPlacedChildren(ctx context.Context, gateway *gatewayapiv1.Gateway, cluster string) ([]metav1.Object, error)
+}
+
+// rolloutProgress is the content of the RolloutProgrammedAnnotation
+type rolloutProgress struct {
+	Revision   string                 `json:"revision"`
+	Programmed map[string]metav1.Time `json:"programmed,omitempty"`
+}
+
+func (s *RolloutStrategy) validate() error {
+	for i, wave := range s.Waves {
+		if _, err := metav1.LabelSelectorAsSelector(&wave.ClusterSelector); err != nil {
+			return fmt.Errorf("wave %d: invalid cluster selector: %w", i, err)
+		}
+	}
+	if s.MaxUnavailable < 0 {
+		return fmt.Errorf("maxUnavailable must not be negative")
+	}
+	return nil
+}
+
+// waves groups the clusters into the waves of the strategy
+func (s *RolloutStrategy) waves(clusters []string, clusterLabels map[string]map[string]string) ([][]string, error) {
+	grouped := make([][]string, len(s.Waves)+1)
+	for _, cluster := range clusters {
+		wave := len(s.Waves)
+		for i := range s.Waves {
+			selector, err := metav1.LabelSelectorAsSelector(&s.Waves[i].ClusterSelector)
+			if err != nil {
+				return nil, err
+			}
+			if selector.Matches(labels.Set(clusterLabels[cluster])) {
+				wave = i
+				break
+			}
+		}
+		grouped[wave] = append(grouped[wave], cluster)
+	}
+
+	waves := [][]string{}
+	for _, wave := range grouped {
+		if len(wave) == 0 {
+			continue
+		}
+		sort.Strings(wave)
+		size := len(wave)
+		if s.MaxUnavailable > 0 {
+			size = s.MaxUnavailable
+		}
+		for len(wave) > 0 {
+			n := min(size, len(wave))
+			waves = append(waves, wave[:n])
+			wave = wave[n:]
+		}
+	}
+	return waves, nil
+}
+
+// rolloutRevision identifies the spec of the downstream gateway common to
+// every cluster, along with the overrides customising it for each cluster and
+// the content of its children, such as TLS secrets. The metadata of the
+// gateway is left out as the controller updates it on every placement
+func rolloutRevision(downstream *gatewayapiv1.Gateway, params *Params, children []metav1.Object) (string, error) {
+	sorted := append([]metav1.Object{}, children...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GetNamespace()+"/"+sorted[i].GetName() < sorted[j].GetNamespace()+"/"+sorted[j].GetName()
+	})
+	content, err := json.Marshal(struct {
+		Spec      gatewayapiv1.GatewaySpec `json:"spec"`
+		Overrides []ClusterOverride        `json:"overrides,omitempty"`
+		Children  []metav1.Object          `json:"children,omitempty"`
+	}{downstream.Spec, params.Overrides, sorted})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(content))[:16], nil
+}
+
+// rollout returns the overrides and children placing the latest revision of
+// the downstream gateway on the clusters of the waves that have started, and
+// keeping the revision placed on the clusters of the later waves. The
+// progress is reported by the RolledOut condition of the upstream gateway,
+// and the returned bool is true until the rollout completes, as the health
+// checks of the clusters don't trigger a reconcile when they change. Without a
+// rollout strategy, or when the placer can't return the placed gateways, the
+// latest revision is placed on every cluster
+func (r *GatewayReconciler) rollout(ctx context.Context, upstreamGateway, downstream *gatewayapiv1.Gateway, params *Params, overrides placement.Overrides, children placement.ClusterChildren) (placement.Overrides, placement.ClusterChildren, bool, error) {
+	log := crlog.FromContext(ctx)
+	if params == nil || params.Rollout == nil {
+		meta.RemoveStatusCondition(&upstreamGateway.Status.Conditions, GatewayConditionRolledOut)
+		metadata.RemoveAnnotation(upstreamGateway, RolloutProgrammedAnnotation)
+		return overrides, children, false, nil
+	}
+	getter, ok := r.Placement.(PlacedGatewayGetter)
+	if !ok {
+		log.Info("placement doesn't support rollouts, placing gateway on every cluster", "gateway", upstreamGateway.Name)
+		return overrides, children, false, nil
+	}
+
+	targets, err := r.Placement.GetClusters(ctx, upstreamGateway)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	clusters := sets.List(targets)
+
+	// the latest children of every cluster are part of the revision
+	latest := map[string]metav1.Object{}
+	for _, cluster := range clusters {
+		gateway := downstream
+		if overrides != nil {
+			if gateway, err = overrides(ctx, cluster, downstream); err != nil {
+				return nil, nil, false, err
+			}
+		}
+		if children == nil {
+			continue
+		}
+		clusterChildren, err := children(ctx, cluster, gateway)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		for _, child := range clusterChildren {
+			latest[child.GetNamespace()+"/"+child.GetName()] = child
+		}
+	}
+	latestChildren := make([]metav1.Object, 0, len(latest))
+	for _, child := range latest {
+		latestChildren = append(latestChildren, child)
+	}
+	revision, err := rolloutRevision(downstream, params, latestChildren)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	metadata.AddAnnotation(downstream, RolloutRevisionAnnotation, revision)
+
+	progress := rolloutProgress{}
+	if value := metadata.GetAnnotation(upstreamGateway, RolloutProgrammedAnnotation); value != "" {
+		if err := json.Unmarshal([]byte(value), &progress); err != nil {
+			log.Info("ignoring invalid rollout progress", "gateway", upstreamGateway.Name, "error", err.Error())
+		}
+	}
+	if progress.Revision != revision || progress.Programmed == nil {
+		progress = rolloutProgress{Revision: revision, Programmed: map[string]metav1.Time{}}
+	}
+
+	clusterLabels := map[string]map[string]string{}
+	placed := map[string]*gatewayapiv1.Gateway{}
+	for _, cluster := range clusters {
+		if clusterLabels[cluster], err = r.getClusterLabels(ctx, upstreamGateway, cluster); err != nil {
+			return nil, nil, false, err
+		}
+		if placed[cluster], err = getter.GetPlacedGateway(ctx, upstreamGateway, cluster); err != nil {
+			return nil, nil, false, err
+		}
+	}
+
+	waves, err := params.Rollout.waves(clusters, clusterLabels)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	started := sets.New[string]()
+	condition := metav1.Condition{
+		Type:               GatewayConditionRolledOut,
+		Status:             metav1.ConditionTrue,
+		Reason:             GatewayReasonRolloutComplete,
+		Message:            fmt.Sprintf("revision %s rolled out to clusters %v", revision, clusters),
+		ObservedGeneration: upstreamGateway.Generation,
+	}
+	for i, wave := range waves {
+		started.Insert(wave...)
+		failed, pending, err := r.waveProblems(ctx, upstreamGateway, wave, placed, progress)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if len(failed) > 0 {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = GatewayReasonRolloutHalted
+			condition.Message = fmt.Sprintf("revision %s halted at wave %d/%d: %s", revision, i+1, len(waves), strings.Join(append(failed, pending...), "; "))
+			break
+		}
+		if len(pending) > 0 {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = GatewayReasonRolloutProgressing
+			condition.Message = fmt.Sprintf("revision %s rolling out wave %d/%d: %s", revision, i+1, len(waves), strings.Join(pending, "; "))
+			break
+		}
+	}
+	meta.SetStatusCondition(&upstreamGateway.Status.Conditions, condition)
+	serialized, err := json.Marshal(progress)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	metadata.AddAnnotation(upstreamGateway, RolloutProgrammedAnnotation, string(serialized))
+	log.V(3).Info("rollout", "gateway", upstreamGateway.Name, "revision", revision, "started", sets.List(started), "reason", condition.Reason)
+
+	// the clusters held on their revision keep the children placed with it
+	held := map[string][]metav1.Object{}
+	for _, cluster := range clusters {
+		if placed[cluster] == nil || started.Has(cluster) {
+			continue
+		}
+		if held[cluster], err = getter.GetPlacedChildren(ctx, upstreamGateway, cluster); err != nil {
+			return nil, nil, false, err
+		}
+	}
+
+	return func(ctx context.Context, cluster string, downstream *gatewayapiv1.Gateway) (*gatewayapiv1.Gateway, error) {
+			if previous := placed[cluster]; previous != nil && !started.Has(cluster) {
+				return previous, nil
+			}
+			if overrides == nil {
+				return downstream, nil
+			}
+			return overrides(ctx, cluster, downstream)
+		}, func(ctx context.Context, cluster string, gateway *gatewayapiv1.Gateway) ([]metav1.Object, error) {
+			if heldChildren, ok := held[cluster]; ok {
+				return heldChildren, nil
+			}
+			if children == nil {
+				return nil, nil
+			}
+			return children(ctx, cluster, gateway)
+		}, condition.Reason != GatewayReasonRolloutComplete, nil
+}
+
+// waveProblems returns the clusters of the wave that failed the latest
+// revision, because their downstream gateway isn't programmed or one of its
+// health checks is unhealthy, and the clusters that are yet to be
+// programmed with it. Health checks only count once they have been made since
+// the cluster was programmed with the revision, which is recorded in the
+// progress the first time it's seen
+func (r *GatewayReconciler) waveProblems(ctx context.Context, upstreamGateway *gatewayapiv1.Gateway, wave []string, placed map[string]*gatewayapiv1.Gateway, progress rolloutProgress) ([]string, []string, error) {
+	probes := &v1alpha1.DNSHealthCheckProbeList{}
+	if err := r.Client.List(ctx, probes, client.InNamespace(upstreamGateway.Namespace), client.MatchingLabels{
+		probeGatewayLabel:          upstreamGateway.Name,
+		probeGatewayNamespaceLabel: upstreamGateway.Namespace,
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	failed := []string{}
+	pending := []string{}
+	for _, cluster := range wave {
+		if placed[cluster] == nil || metadata.GetAnnotation(placed[cluster], RolloutRevisionAnnotation) != progress.Revision {
+			pending = append(pending, fmt.Sprintf("%s: updating", cluster))
+			continue
+		}
+
+		status, err := r.Placement.GetDownstreamStatus(ctx, upstreamGateway, cluster)
+		if err != nil {
+			pending = append(pending, fmt.Sprintf("%s: status unknown", cluster))
+			continue
+		}
+		programmed := meta.FindStatusCondition(status.Conditions, string(gatewayapiv1.GatewayConditionProgrammed))
+		switch {
+		case programmed == nil || programmed.Status == metav1.ConditionUnknown:
+			pending = append(pending, fmt.Sprintf("%s: not programmed yet", cluster))
+			continue
+		case programmed.Status == metav1.ConditionFalse:
+			failed = append(failed, fmt.Sprintf("%s: %s", cluster, conditionProblem("not Programmed", programmed)))
+			continue
+		}
+		programmedAt, ok := progress.Programmed[cluster]
+		if !ok {
+			programmedAt = metav1.Now()
+			progress.Programmed[cluster] = programmedAt
+		}
+		if programmedAt.Before(&programmed.LastTransitionTime) {
+			programmedAt = programmed.LastTransitionTime
+		}
+
+		addresses, err := r.Placement.GetAddresses(ctx, upstreamGateway, cluster)
+		if err != nil {
+			pending = append(pending, fmt.Sprintf("%s: addresses unknown", cluster))
+			continue
+		}
+		clusterAddresses := sets.New[string]()
+		for _, address := range addresses {
+			clusterAddresses.Insert(address.Value)
+		}
+		for _, probe := range probes.Items {
+			if !clusterAddresses.Has(probe.Spec.Address) {
+				continue
+			}
+			switch {
+			case probe.Status.Healthy == nil:
+				pending = append(pending, fmt.Sprintf("%s: health check %s pending", cluster, probe.Name))
+			case !programmedAt.Before(&probe.Status.LastCheckedAt):
+				pending = append(pending, fmt.Sprintf("%s: health check %s not made since programmed", cluster, probe.Name))
+			case !*probe.Status.Healthy:
+				failed = append(failed, fmt.Sprintf("%s: health check %s unhealthy (%s)", cluster, probe.Name, probe.Status.Reason))
+			}
+		}
+	}
+	return failed, pending, nil
+}
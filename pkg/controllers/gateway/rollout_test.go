@@ -0,0 +1,353 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	fakeplacement "github.com/Kuadrant/multicluster-gateway-controller/pkg/placement/fake"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+// rolloutPlacer places the gateway on clusters labelled with their tier, and
+// reports the gateways placed on them as programmed
+type rolloutPlacer struct {
+	fakeplacement.FakeGatewayPlacer
+	tiers    map[string]string
+	placed   map[string]*gatewayapiv1.Gateway
+	children map[string][]metav1.Object
+}
+
+func (p *rolloutPlacer) GetClusters(_ context.Context, _ *gatewayapiv1.Gateway) (sets.Set[string], error) {
+	clusters := sets.New[string]()
+	for cluster := range p.tiers {
+		clusters.Insert(cluster)
+	}
+	return clusters, nil
+}
+
+func (p *rolloutPlacer) GetClusterLabels(_ context.Context, _ *gatewayapiv1.Gateway, cluster string) (map[string]string, error) {
+	return map[string]string{"tier": p.tiers[cluster]}, nil
+}
+
+func (p *rolloutPlacer) GetPlacedGateway(_ context.Context, _ *gatewayapiv1.Gateway, cluster string) (*gatewayapiv1.Gateway, error) {
+	return p.placed[cluster], nil
+}
+
+func (p *rolloutPlacer) GetPlacedChildren(_ context.Context, _ *gatewayapiv1.Gateway, cluster string) ([]metav1.Object, error) {
+	return p.children[cluster], nil
+}
+
+func (p *rolloutPlacer) GetAddresses(_ context.Context, _ *gatewayapiv1.Gateway, cluster string) ([]gatewayapiv1.GatewayAddress, error) {
+	return []gatewayapiv1.GatewayAddress{{Value: cluster + ".example.com"}}, nil
+}
+
+func TestRolloutStrategyWaves(t *testing.T) {
+	strategy := &RolloutStrategy{
+		Waves: []RolloutWave{
+			{ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "canary"}}},
+			{ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "prod"}}},
+		},
+		MaxUnavailable: 2,
+	}
+	clusterLabels := map[string]map[string]string{
+		"canary":  {"tier": "canary"},
+		"prod-c":  {"tier": "prod"},
+		"prod-a":  {"tier": "prod"},
+		"prod-b":  {"tier": "prod"},
+		"staging": {"tier": "staging"},
+	}
+
+	waves, err := strategy.waves([]string{"prod-c", "staging", "prod-a", "canary", "prod-b"}, clusterLabels)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	expected := [][]string{{"canary"}, {"prod-a", "prod-b"}, {"prod-c"}, {"staging"}}
+	if !reflect.DeepEqual(waves, expected) {
+		t.Errorf("expected waves %v, got %v", expected, waves)
+	}
+
+	if err := (&RolloutStrategy{MaxUnavailable: -1}).validate(); err == nil {
+		t.Errorf("expected an error for a negative maxUnavailable")
+	}
+}
+
+func TestRollout(t *testing.T) {
+	scheme := testutil.GetBasicScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	upstream := &gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: testutil.Namespace}}
+	programmedAt := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	params := &Params{Rollout: &RolloutStrategy{Waves: []RolloutWave{
+		{ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "canary"}}},
+	}}}
+	downstream := func(class string) *gatewayapiv1.Gateway {
+		return &gatewayapiv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "kuadrant-" + testutil.Namespace},
+			Spec:       gatewayapiv1.GatewaySpec{GatewayClassName: gatewayapiv1.ObjectName(class)},
+		}
+	}
+	probe := func(cluster string, healthy *bool, checkedAt metav1.Time) *v1alpha1.DNSHealthCheckProbe {
+		return &v1alpha1.DNSHealthCheckProbe{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster + "-probe",
+				Namespace: testutil.Namespace,
+				Labels: map[string]string{
+					probeGatewayLabel:          upstream.Name,
+					probeGatewayNamespaceLabel: upstream.Namespace,
+				},
+			},
+			Spec:   v1alpha1.DNSHealthCheckProbeSpec{Address: cluster + ".example.com"},
+			Status: v1alpha1.DNSHealthCheckProbeStatus{Healthy: healthy, LastCheckedAt: checkedAt},
+		}
+	}
+	healthy, unhealthy := true, false
+	checkedAt := metav1.NewTime(programmedAt.Add(time.Minute))
+	checkedBefore := metav1.NewTime(programmedAt.Add(-time.Minute))
+
+	revision, err := rolloutRevision(downstream("istio"), params, nil)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	placedAt := func(class, revision string) *gatewayapiv1.Gateway {
+		gateway := downstream(class)
+		gateway.Annotations = map[string]string{RolloutRevisionAnnotation: revision}
+		return gateway
+	}
+
+	// the clusters were first seen programmed with the revision an hour ago
+	progress := fmt.Sprintf(`{"revision":%q,"programmed":{"canary":%q,"prod":%q}}`, revision, programmedAt.Format(time.RFC3339), programmedAt.Format(time.RFC3339))
+
+	cases := []struct {
+		name           string
+		placed         map[string]*gatewayapiv1.Gateway
+		probes         []*v1alpha1.DNSHealthCheckProbe
+		progress       string
+		expectedReason string
+		expectedClass  map[string]string
+	}{
+		{
+			name:           "first placement",
+			placed:         map[string]*gatewayapiv1.Gateway{},
+			expectedReason: GatewayReasonRolloutProgressing,
+			expectedClass:  map[string]string{"canary": "istio", "prod": "istio"},
+		},
+		{
+			name: "canary updating",
+			placed: map[string]*gatewayapiv1.Gateway{
+				"canary": placedAt("previous", "old"),
+				"prod":   placedAt("previous", "old"),
+			},
+			expectedReason: GatewayReasonRolloutProgressing,
+			expectedClass:  map[string]string{"canary": "istio", "prod": "previous"},
+		},
+		{
+			name: "canary health check pending",
+			placed: map[string]*gatewayapiv1.Gateway{
+				"canary": placedAt("istio", revision),
+				"prod":   placedAt("previous", "old"),
+			},
+			progress:       progress,
+			probes:         []*v1alpha1.DNSHealthCheckProbe{probe("canary", nil, checkedAt)},
+			expectedReason: GatewayReasonRolloutProgressing,
+			expectedClass:  map[string]string{"canary": "istio", "prod": "previous"},
+		},
+		{
+			name: "canary unhealthy",
+			placed: map[string]*gatewayapiv1.Gateway{
+				"canary": placedAt("istio", revision),
+				"prod":   placedAt("previous", "old"),
+			},
+			progress:       progress,
+			probes:         []*v1alpha1.DNSHealthCheckProbe{probe("canary", &unhealthy, checkedAt)},
+			expectedReason: GatewayReasonRolloutHalted,
+			expectedClass:  map[string]string{"canary": "istio", "prod": "previous"},
+		},
+		{
+			name: "canary healthy",
+			placed: map[string]*gatewayapiv1.Gateway{
+				"canary": placedAt("istio", revision),
+				"prod":   placedAt("previous", "old"),
+			},
+			progress:       progress,
+			probes:         []*v1alpha1.DNSHealthCheckProbe{probe("canary", &healthy, checkedAt), probe("prod", &unhealthy, checkedAt)},
+			expectedReason: GatewayReasonRolloutProgressing,
+			expectedClass:  map[string]string{"canary": "istio", "prod": "istio"},
+		},
+		{
+			name: "canary health check made before programmed",
+			placed: map[string]*gatewayapiv1.Gateway{
+				"canary": placedAt("istio", revision),
+				"prod":   placedAt("previous", "old"),
+			},
+			progress:       progress,
+			probes:         []*v1alpha1.DNSHealthCheckProbe{probe("canary", &healthy, checkedBefore)},
+			expectedReason: GatewayReasonRolloutProgressing,
+			expectedClass:  map[string]string{"canary": "istio", "prod": "previous"},
+		},
+		{
+			name: "canary first seen programmed",
+			placed: map[string]*gatewayapiv1.Gateway{
+				"canary": placedAt("istio", revision),
+				"prod":   placedAt("previous", "old"),
+			},
+			probes:         []*v1alpha1.DNSHealthCheckProbe{probe("canary", &healthy, checkedAt)},
+			expectedReason: GatewayReasonRolloutProgressing,
+			expectedClass:  map[string]string{"canary": "istio", "prod": "previous"},
+		},
+		{
+			name: "rolled out",
+			placed: map[string]*gatewayapiv1.Gateway{
+				"canary": placedAt("istio", revision),
+				"prod":   placedAt("istio", revision),
+			},
+			progress:       progress,
+			probes:         []*v1alpha1.DNSHealthCheckProbe{probe("canary", &healthy, checkedAt), probe("prod", &healthy, checkedAt)},
+			expectedReason: GatewayReasonRolloutComplete,
+			expectedClass:  map[string]string{"canary": "istio", "prod": "istio"},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			for _, probe := range testCase.probes {
+				builder = builder.WithObjects(probe)
+			}
+			r := &GatewayReconciler{
+				Client: builder.Build(),
+				Placement: &rolloutPlacer{
+					tiers:  map[string]string{"canary": "canary", "prod": "prod"},
+					placed: testCase.placed,
+				},
+			}
+			gateway := upstream.DeepCopy()
+			if testCase.progress != "" {
+				gateway.Annotations = map[string]string{RolloutProgrammedAnnotation: testCase.progress}
+			}
+			latest := downstream("istio")
+
+			overrides, _, requeue, err := r.rollout(context.Background(), gateway, latest, params, nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			recorded := rolloutProgress{}
+			if err := json.Unmarshal([]byte(gateway.Annotations[RolloutProgrammedAnnotation]), &recorded); err != nil || recorded.Revision != revision {
+				t.Errorf("expected the rollout progress of revision %s, got %v (%v)", revision, recorded, err)
+			}
+			if testCase.placed["canary"] != nil && testCase.placed["canary"].Annotations[RolloutRevisionAnnotation] == revision {
+				if _, ok := recorded.Programmed["canary"]; !ok {
+					t.Errorf("expected the time canary was programmed to be recorded, got %v", recorded)
+				}
+			}
+			if latest.Annotations[RolloutRevisionAnnotation] != revision {
+				t.Errorf("expected the downstream gateway at revision %s, got %v", revision, latest.Annotations)
+			}
+			condition := meta.FindStatusCondition(gateway.Status.Conditions, GatewayConditionRolledOut)
+			if condition == nil || condition.Reason != testCase.expectedReason {
+				t.Fatalf("expected reason %s, got %v", testCase.expectedReason, condition)
+			}
+			if requeue != (testCase.expectedReason != GatewayReasonRolloutComplete) {
+				t.Errorf("unexpected requeue %t", requeue)
+			}
+			for cluster, class := range testCase.expectedClass {
+				placed, err := overrides(context.Background(), cluster, latest)
+				if err != nil {
+					t.Fatalf("unexpected error %s", err)
+				}
+				if string(placed.Spec.GatewayClassName) != class {
+					t.Errorf("expected class %s on cluster %s, got %s", class, cluster, placed.Spec.GatewayClassName)
+				}
+			}
+		})
+	}
+
+	t.Run("without rollout strategy", func(t *testing.T) {
+		r := &GatewayReconciler{Placement: &rolloutPlacer{}}
+		gateway := upstream.DeepCopy()
+		meta.SetStatusCondition(&gateway.Status.Conditions, metav1.Condition{Type: GatewayConditionRolledOut, Status: metav1.ConditionTrue, Reason: GatewayReasonRolloutComplete})
+		overrides, _, requeue, err := r.rollout(context.Background(), gateway, downstream("istio"), &Params{}, nil, nil)
+		if err != nil || overrides != nil || requeue {
+			t.Errorf("expected no rollout, got %v %t %v", overrides != nil, requeue, err)
+		}
+		if meta.FindStatusCondition(gateway.Status.Conditions, GatewayConditionRolledOut) != nil {
+			t.Errorf("expected the RolledOut condition to be removed")
+		}
+	})
+}
+
+func TestRolloutChildren(t *testing.T) {
+	upstream := &gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: testutil.Namespace}}
+	params := &Params{Rollout: &RolloutStrategy{Waves: []RolloutWave{
+		{ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "canary"}}},
+	}}}
+	downstream := &gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "kuadrant-" + testutil.Namespace}}
+	secret := func(cert string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: downstream.Namespace},
+			Data:       map[string][]byte{"tls.crt": []byte(cert)},
+		}
+	}
+	children := func(_ context.Context, _ string, _ *gatewayapiv1.Gateway) ([]metav1.Object, error) {
+		return []metav1.Object{secret("new")}, nil
+	}
+
+	// the content of the children is part of the revision
+	latestRevision, err := rolloutRevision(downstream, params, []metav1.Object{secret("new")})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	previousRevision, err := rolloutRevision(downstream, params, []metav1.Object{secret("old")})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if latestRevision == previousRevision {
+		t.Fatalf("expected the revision to change with the content of the children")
+	}
+
+	placed := downstream.DeepCopy()
+	placed.Annotations = map[string]string{RolloutRevisionAnnotation: previousRevision}
+	scheme := testutil.GetBasicScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	r := &GatewayReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Placement: &rolloutPlacer{
+			tiers:    map[string]string{"canary": "canary", "prod": "prod"},
+			placed:   map[string]*gatewayapiv1.Gateway{"canary": placed, "prod": placed},
+			children: map[string][]metav1.Object{"canary": {secret("old")}, "prod": {secret("old")}},
+		},
+	}
+	latest := downstream.DeepCopy()
+	_, clusterChildren, _, err := r.rollout(context.Background(), upstream.DeepCopy(), latest, params, nil, children)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if latest.Annotations[RolloutRevisionAnnotation] != latestRevision {
+		t.Errorf("expected the downstream gateway at revision %s, got %v", latestRevision, latest.Annotations)
+	}
+	for cluster, cert := range map[string]string{"canary": "new", "prod": "old"} {
+		placedChildren, err := clusterChildren(context.Background(), cluster, latest)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if len(placedChildren) != 1 || string(placedChildren[0].(*corev1.Secret).Data["tls.crt"]) != cert {
+			t.Errorf("expected the %s children on cluster %s, got %v", cert, cluster, placedChildren)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// GatewayReasonDownstreamNotAccepted is the reason of the Accepted
+	// condition when a downstream gateway is not accepted
+	GatewayReasonDownstreamNotAccepted = "DownstreamNotAccepted"
+	// GatewayReasonDownstreamNotProgrammed is the reason of the Programmed
+	// condition when a downstream gateway is not programmed
+	GatewayReasonDownstreamNotProgrammed = "DownstreamNotProgrammed"
+)
+
+// aggregateDownstreamCondition combines the condition of the upstream gateway
+// with the same condition of the downstream gateways. A downstream gateway,
+// or one of its listeners, with the condition False makes it False, and the
+// message names each cluster and its reasons. A downstream gateway that
+// hasn't reported the condition yet makes Programmed Unknown, but doesn't
+// affect Accepted, which is decided by the upstream gateway
+func aggregateDownstreamCondition(condition metav1.Condition, clusters []string, statuses map[string]*gatewayapiv1.GatewayStatus) metav1.Condition {
+	if condition.Status != metav1.ConditionTrue {
+		return condition
+	}
+
+	notTrue := []string{}
+	unknown := []string{}
+	for _, cluster := range clusters {
+		status, ok := statuses[cluster]
+		if !ok {
+			unknown = append(unknown, fmt.Sprintf("%s: status unknown", cluster))
+			continue
+		}
+
+		problems := []string{}
+		downstream := meta.FindStatusCondition(status.Conditions, condition.Type)
+		switch {
+		case downstream == nil || downstream.Status == metav1.ConditionUnknown:
+			unknown = append(unknown, fmt.Sprintf("%s: %s not reported", cluster, condition.Type))
+		case downstream.Status == metav1.ConditionFalse:
+			problems = append(problems, conditionProblem("not "+condition.Type, downstream))
+		}
+		for _, listener := range status.Listeners {
+			for _, problem := range listenerProblems(condition.Type, listener) {
+				problems = append(problems, fmt.Sprintf("listener %s %s", listener.Name, problem))
+			}
+		}
+		if len(problems) > 0 {
+			notTrue = append(notTrue, fmt.Sprintf("%s: %s", cluster, strings.Join(problems, ", ")))
+		}
+	}
+
+	reason := GatewayReasonDownstreamNotProgrammed
+	if condition.Type == string(gatewayapiv1.GatewayConditionAccepted) {
+		reason = GatewayReasonDownstreamNotAccepted
+	}
+
+	switch {
+	case len(notTrue) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reason
+		condition.Message = strings.Join(append(notTrue, unknown...), "; ")
+	case len(unknown) > 0 && condition.Type == string(gatewayapiv1.GatewayConditionProgrammed):
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = string(gatewayapiv1.GatewayReasonPending)
+		condition.Message = strings.Join(unknown, "; ")
+	}
+	return condition
+}
+
+// listenerProblems returns the problems of a downstream listener affecting
+// the condition of the gateway. A listener in conflict or not accepted
+// affects Accepted, and a listener with unresolved references or not
+// programmed affects Programmed
+func listenerProblems(conditionType string, listener gatewayapiv1.ListenerStatus) []string {
+	problems := []string{}
+	for _, condition := range listener.Conditions {
+		condition := condition
+		switch {
+		case conditionType == string(gatewayapiv1.GatewayConditionAccepted) &&
+			condition.Type == string(gatewayapiv1.ListenerConditionConflicted) && condition.Status == metav1.ConditionTrue:
+			problems = append(problems, conditionProblem(condition.Type, &condition))
+		case conditionType == string(gatewayapiv1.GatewayConditionAccepted) &&
+			condition.Type == string(gatewayapiv1.ListenerConditionAccepted) && condition.Status == metav1.ConditionFalse:
+			problems = append(problems, conditionProblem("not "+condition.Type, &condition))
+		case conditionType == string(gatewayapiv1.GatewayConditionProgrammed) &&
+			condition.Type == string(gatewayapiv1.ListenerConditionResolvedRefs) && condition.Status == metav1.ConditionFalse:
+			problems = append(problems, conditionProblem("unresolved refs", &condition))
+		case conditionType == string(gatewayapiv1.GatewayConditionProgrammed) &&
+			condition.Type == string(gatewayapiv1.ListenerConditionProgrammed) && condition.Status == metav1.ConditionFalse:
+			problems = append(problems, conditionProblem("not "+condition.Type, &condition))
+		}
+	}
+	return problems
+}
+
+func conditionProblem(prefix string, condition *metav1.Condition) string {
+	problem := fmt.Sprintf("%s %s", prefix, condition.Reason)
+	if condition.Message != "" {
+		problem += fmt.Sprintf(" (%s)", condition.Message)
+	}
+	return problem
+}
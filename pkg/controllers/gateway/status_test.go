@@ -0,0 +1,130 @@
+//go:build unit
+
+package gateway
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_aggregateDownstreamCondition(t *testing.T) {
+	accepted := string(gatewayapiv1.GatewayConditionAccepted)
+	programmed := string(gatewayapiv1.GatewayConditionProgrammed)
+	healthy := &gatewayapiv1.GatewayStatus{
+		Conditions: []metav1.Condition{
+			{Type: accepted, Status: metav1.ConditionTrue, Reason: "Accepted"},
+			{Type: programmed, Status: metav1.ConditionTrue, Reason: "Programmed"},
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		condition       metav1.Condition
+		statuses        map[string]*gatewayapiv1.GatewayStatus
+		expectedStatus  metav1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:            "all downstream gateways programmed",
+			condition:       buildProgrammedCondition(1, []string{"cluster-a", "cluster-b"}, metav1.ConditionTrue, nil),
+			statuses:        map[string]*gatewayapiv1.GatewayStatus{"cluster-a": healthy, "cluster-b": healthy},
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  string(gatewayapiv1.GatewayReasonProgrammed),
+			expectedMessage: "gateway placed on clusters [cluster-a cluster-b]",
+		},
+		{
+			name:      "downstream gateway not programmed",
+			condition: buildProgrammedCondition(1, []string{"cluster-a", "cluster-b"}, metav1.ConditionTrue, nil),
+			statuses: map[string]*gatewayapiv1.GatewayStatus{
+				"cluster-a": healthy,
+				"cluster-b": {
+					Conditions: []metav1.Condition{
+						{Type: programmed, Status: metav1.ConditionFalse, Reason: "AddressNotAssigned", Message: "no load balancer"},
+					},
+				},
+			},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  GatewayReasonDownstreamNotProgrammed,
+			expectedMessage: "cluster-b: not Programmed AddressNotAssigned (no load balancer)",
+		},
+		{
+			name:      "downstream listener with unresolved refs",
+			condition: buildProgrammedCondition(1, []string{"cluster-a", "cluster-b"}, metav1.ConditionTrue, nil),
+			statuses: map[string]*gatewayapiv1.GatewayStatus{
+				"cluster-a": {
+					Conditions: healthy.Conditions,
+					Listeners: []gatewayapiv1.ListenerStatus{
+						{
+							Name: "api",
+							Conditions: []metav1.Condition{
+								{Type: string(gatewayapiv1.ListenerConditionResolvedRefs), Status: metav1.ConditionFalse, Reason: "InvalidCertificateRef"},
+							},
+						},
+					},
+				},
+			},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  GatewayReasonDownstreamNotProgrammed,
+			expectedMessage: "cluster-a: listener api unresolved refs InvalidCertificateRef; cluster-b: status unknown",
+		},
+		{
+			name:            "downstream gateway not reported",
+			condition:       buildProgrammedCondition(1, []string{"cluster-a", "cluster-b"}, metav1.ConditionTrue, nil),
+			statuses:        map[string]*gatewayapiv1.GatewayStatus{"cluster-a": healthy, "cluster-b": {}},
+			expectedStatus:  metav1.ConditionUnknown,
+			expectedReason:  string(gatewayapiv1.GatewayReasonPending),
+			expectedMessage: "cluster-b: Programmed not reported",
+		},
+		{
+			name:            "not placed takes precedence",
+			condition:       buildProgrammedCondition(1, []string{"cluster-a"}, metav1.ConditionFalse, nil),
+			statuses:        map[string]*gatewayapiv1.GatewayStatus{"cluster-a": healthy},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  string(gatewayapiv1.GatewayReasonInvalid),
+			expectedMessage: "gateway failed to be placed on all clusters [cluster-a]",
+		},
+		{
+			name:      "downstream listener in conflict",
+			condition: buildAcceptedCondition(1, metav1.ConditionTrue),
+			statuses: map[string]*gatewayapiv1.GatewayStatus{
+				"cluster-a": healthy,
+				"cluster-b": {
+					Conditions: []metav1.Condition{
+						{Type: accepted, Status: metav1.ConditionFalse, Reason: "ListenersNotValid"},
+					},
+					Listeners: []gatewayapiv1.ListenerStatus{
+						{
+							Name: "api",
+							Conditions: []metav1.Condition{
+								{Type: string(gatewayapiv1.ListenerConditionConflicted), Status: metav1.ConditionTrue, Reason: "HostnameConflict"},
+							},
+						},
+					},
+				},
+			},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  GatewayReasonDownstreamNotAccepted,
+			expectedMessage: "cluster-b: not Accepted ListenersNotValid, listener api Conflicted HostnameConflict",
+		},
+		{
+			name:            "accepted not affected by unreported downstream gateways",
+			condition:       buildAcceptedCondition(1, metav1.ConditionTrue),
+			statuses:        map[string]*gatewayapiv1.GatewayStatus{"cluster-a": {}},
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  string(gatewayapiv1.GatewayReasonAccepted),
+			expectedMessage: "Handled by " + ControllerName,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := aggregateDownstreamCondition(testCase.condition, []string{"cluster-a", "cluster-b"}, testCase.statuses)
+			if got.Status != testCase.expectedStatus || got.Reason != testCase.expectedReason || got.Message != testCase.expectedMessage {
+				t.Errorf("expected %s %s %q, got %s %s %q", testCase.expectedStatus, testCase.expectedReason, testCase.expectedMessage, got.Status, got.Reason, got.Message)
+			}
+		})
+	}
+}
@@ -3,8 +3,10 @@ package aws
 import (
 	"context"
 	"fmt"
+	"net"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/route53/route53iface"
 	"github.com/rs/xid"
@@ -25,6 +27,9 @@ const (
 
 var (
 	callerReference func(id string) *string
+
+	// lookupIP resolves the hostname targets of the endpoints
+	lookupIP = net.DefaultResolver.LookupIP
 )
 
 type Route53HealthCheckReconciler struct {
@@ -98,6 +103,9 @@ func (c *Route53HealthCheckReconciler) findHealthCheck(ctx context.Context, endp
 	response, err := c.client.GetHealthCheckWithContext(ctx, &route53.GetHealthCheckInput{
 		HealthCheckId: &id,
 	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == route53.ErrCodeNoSuchHealthCheck {
+		return nil, false, nil
+	}
 	if err != nil {
 		return nil, false, err
 	}
@@ -107,8 +115,10 @@ func (c *Route53HealthCheckReconciler) findHealthCheck(ctx context.Context, endp
 }
 
 func (c *Route53HealthCheckReconciler) createHealthCheck(ctx context.Context, spec dns.HealthCheckSpec, endpoint *v1alpha1.Endpoint) (*route53.HealthCheck, error) {
-	address, _ := endpoint.GetAddress()
-	host := endpoint.DNSName
+	address, host, err := healthCheckTarget(ctx, spec, endpoint)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create the health check
 	output, err := c.client.CreateHealthCheck(&route53.CreateHealthCheckInput{
@@ -151,14 +161,17 @@ func (c *Route53HealthCheckReconciler) createHealthCheck(ctx context.Context, sp
 }
 
 func (r *Route53HealthCheckReconciler) updateHealthCheck(ctx context.Context, spec dns.HealthCheckSpec, endpoint *v1alpha1.Endpoint, healthCheck *route53.HealthCheck) (dns.HealthCheckReconciliationResult, error) {
-	diff := healthCheckDiff(healthCheck, spec, endpoint)
+	diff, err := healthCheckDiff(ctx, healthCheck, spec, endpoint)
+	if err != nil {
+		return dns.HealthCheckFailed, err
+	}
 	if diff == nil {
 		return dns.HealthCheckNoop, nil
 	}
 
 	log.Log.Info("Updating health check", "diff", *diff)
 
-	_, err := r.client.UpdateHealthCheckWithContext(ctx, diff)
+	_, err = r.client.UpdateHealthCheckWithContext(ctx, diff)
 	if err != nil {
 		return dns.HealthCheckFailed, err
 	}
@@ -169,7 +182,7 @@ func (r *Route53HealthCheckReconciler) updateHealthCheck(ctx context.Context, sp
 // healthCheckDiff creates a `UpdateHealthCheckInput` object with the fields to
 // update on healthCheck based on the given spec.
 // If the health check matches the spec, returns `nil`
-func healthCheckDiff(healthCheck *route53.HealthCheck, spec dns.HealthCheckSpec, endpoint *v1alpha1.Endpoint) *route53.UpdateHealthCheckInput {
+func healthCheckDiff(ctx context.Context, healthCheck *route53.HealthCheck, spec dns.HealthCheckSpec, endpoint *v1alpha1.Endpoint) (*route53.UpdateHealthCheckInput, error) {
 	var result *route53.UpdateHealthCheckInput
 
 	// "Lazily" set the value for result only once and only when there is
@@ -184,11 +197,13 @@ func healthCheckDiff(healthCheck *route53.HealthCheck, spec dns.HealthCheckSpec,
 		return result
 	}
 
-	if !valuesEqual(&endpoint.DNSName, healthCheck.HealthCheckConfig.FullyQualifiedDomainName) {
-		diff().FullyQualifiedDomainName = &endpoint.DNSName
+	address, host, err := healthCheckTarget(ctx, spec, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if !valuesEqual(&host, healthCheck.HealthCheckConfig.FullyQualifiedDomainName) {
+		diff().FullyQualifiedDomainName = &host
 	}
-
-	address, _ := endpoint.GetAddress()
 	if !valuesEqual(&address, healthCheck.HealthCheckConfig.IPAddress) {
 		diff().IPAddress = &address
 	}
@@ -202,7 +217,7 @@ func healthCheckDiff(healthCheck *route53.HealthCheck, spec dns.HealthCheckSpec,
 		diff().FailureThreshold = spec.FailureThreshold
 	}
 
-	return result
+	return result, nil
 }
 
 func init() {
@@ -253,6 +268,42 @@ func valuesEqualWithDefault[T comparable](ptr1, ptr2 *T, defaultValue T) bool {
 	return value1 == value2
 }
 
+// healthCheckTarget returns the IP address to check for the endpoint, and the
+// domain name sent in the Host header and SNI of the checks. Route 53 only
+// checks a target other than the domain name by its IP address, so endpoints
+// targeting a hostname, such as the weighted records of a load balanced
+// policy, are checked at an address the hostname resolves to. The address is
+// resolved again every time the health check is reconciled
+func healthCheckTarget(ctx context.Context, spec dns.HealthCheckSpec, endpoint *v1alpha1.Endpoint) (string, string, error) {
+	host := spec.Host
+	if host == "" {
+		host = endpoint.DNSName
+	}
+
+	address, ok := endpoint.GetAddress()
+	if !ok {
+		return "", "", fmt.Errorf("endpoint %s has no target to check", endpoint.SetID())
+	}
+	if net.ParseIP(address) != nil {
+		return address, host, nil
+	}
+
+	ips, err := lookupIP(ctx, "ip", address)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve health check target %s: %w", address, err)
+	}
+	// prefer IPv4, which every Route 53 health checker supports
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip.String(), host, nil
+		}
+	}
+	if len(ips) == 0 {
+		return "", "", fmt.Errorf("health check target %s has no addresses", address)
+	}
+	return ips[0].String(), host, nil
+}
+
 func getHealthCheckId(endpoint *v1alpha1.Endpoint) (string, bool) {
 	return endpoint.GetProviderSpecific(ProviderSpecificHealthCheckID)
 }
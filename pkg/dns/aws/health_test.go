@@ -5,6 +5,7 @@ package aws
 import (
 	"context"
 	"fmt"
+	"net"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws/request"
@@ -17,6 +18,15 @@ import (
 )
 
 func TestHealthCheckReconcile(t *testing.T) {
+	lookup := lookupIP
+	defer func() { lookupIP = lookup }()
+	lookupIP = func(_ context.Context, _, host string) ([]net.IP, error) {
+		if host != "lb.example.com" {
+			return nil, fmt.Errorf("lookup %s: no such host", host)
+		}
+		return []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.1")}, nil
+	}
+
 	testCases := []struct {
 		name string
 
@@ -31,8 +41,13 @@ func TestHealthCheckReconcile(t *testing.T) {
 
 			spec: dns.HealthCheckSpec{
 				Name: "test-health-check",
+				Host: "api.example.com",
+			},
+			endpoint: &v1alpha1.Endpoint{
+				DNSName:       "default.lb-1ab1.example.com",
+				SetIdentifier: "lb.example.com",
+				Targets:       v1alpha1.Targets{"lb.example.com"},
 			},
-			endpoint: &v1alpha1.Endpoint{},
 			existingHealthChecks: []*mockHealthCheck{
 				{
 					HealthCheck: &route53.HealthCheck{
@@ -59,6 +74,10 @@ func TestHealthCheckReconcile(t *testing.T) {
 				if len(mra.healthChecks) != 2 {
 					return fmt.Errorf("expected 2 health checks after update, got %v", mra.healthChecks)
 				}
+				config := mra.healthChecks[1].HealthCheckConfig
+				if *config.IPAddress != "192.0.2.1" || *config.FullyQualifiedDomainName != "api.example.com" {
+					return fmt.Errorf("expected the resolved load balancer to be checked as api.example.com, got %v", config)
+				}
 
 				return nil
 			},
@@ -72,6 +91,9 @@ func TestHealthCheckReconcile(t *testing.T) {
 				Path: "/",
 			},
 			endpoint: &v1alpha1.Endpoint{
+				DNSName:       "default.lb-1ab1.example.com",
+				SetIdentifier: "lb.example.com",
+				Targets:       v1alpha1.Targets{"lb.example.com"},
 				ProviderSpecific: v1alpha1.ProviderSpecific{
 					{
 						Name:  ProviderSpecificHealthCheckID,
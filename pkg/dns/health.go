@@ -4,10 +4,17 @@ import (
 	"context"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
 )
 
+// HealthCheckResyncPeriod is the period at which health checks are reconciled
+// with the provider even when their spec is unchanged, so that a health check
+// of an endpoint targeting a hostname checks an address the hostname still
+// resolves to
+const HealthCheckResyncPeriod = 5 * time.Minute
+
 type HealthCheckReconciler interface {
 	Reconcile(ctx context.Context, spec HealthCheckSpec, endpoint *v1alpha1.Endpoint) (HealthCheckResult, error)
 
@@ -22,6 +29,9 @@ type HealthCheckSpec struct {
 	Protocol         *HealthCheckProtocol
 
 	Path string
+	// Host is the hostname sent in the Host header and SNI of the checks,
+	// defaults to the DNS name of the endpoint
+	Host string
 }
 
 type HealthCheckResult struct {
@@ -72,6 +82,12 @@ type CachedHealthCheckReconciler struct {
 
 var _ HealthCheckReconciler = &CachedHealthCheckReconciler{}
 
+// cachedHealthCheck is the spec a health check was last reconciled with
+type cachedHealthCheck struct {
+	spec HealthCheckSpec
+	at   time.Time
+}
+
 func NewCachedHealthCheckReconciler(provider Provider, reconciler HealthCheckReconciler) *CachedHealthCheckReconciler {
 	return &CachedHealthCheckReconciler{
 		reconciler: reconciler,
@@ -98,21 +114,18 @@ func (r *CachedHealthCheckReconciler) Reconcile(ctx context.Context, spec Health
 		return r.reconciler.Reconcile(ctx, spec, endpoint)
 	}
 
-	// Update the cache with the new spec
-	defer r.syncCache.Store(id, spec)
-
-	// If the health heck is not cached, delegate the reconciliation
-	existingSpec, ok := r.syncCache.Load(id)
-	if !ok {
-		return r.reconciler.Reconcile(ctx, spec, endpoint)
-	}
-
-	// If the spec is unchanged, return Noop
-	if reflect.DeepEqual(spec, existingSpec.(HealthCheckSpec)) {
-		return NewHealthCheckResult(HealthCheckNoop, "Spec unchanged"), nil
+	// If the health check was cached with the same spec less than a resync
+	// period ago, return Noop
+	if existing, ok := r.syncCache.Load(id); ok {
+		cached := existing.(cachedHealthCheck)
+		if reflect.DeepEqual(spec, cached.spec) && time.Since(cached.at) < HealthCheckResyncPeriod {
+			return NewHealthCheckResult(HealthCheckNoop, "Spec unchanged"), nil
+		}
 	}
 
-	// Otherwise, delegate the reconciliation
+	// Otherwise, delegate the reconciliation and update the cache with the
+	// new spec
+	defer r.syncCache.Store(id, cachedHealthCheck{spec: spec, at: time.Now()})
 	return r.reconciler.Reconcile(ctx, spec, endpoint)
 }
 
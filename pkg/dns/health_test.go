@@ -0,0 +1,60 @@
+//go:build unit
+
+package dns
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+)
+
+// countingHealthCheckReconciler counts the reconciliations delegated to it
+type countingHealthCheckReconciler struct {
+	FakeHealthCheckReconciler
+	reconciled int
+}
+
+func (r *countingHealthCheckReconciler) Reconcile(ctx context.Context, spec HealthCheckSpec, endpoint *v1alpha1.Endpoint) (HealthCheckResult, error) {
+	r.reconciled++
+	return r.FakeHealthCheckReconciler.Reconcile(ctx, spec, endpoint)
+}
+
+func TestCachedHealthCheckReconciler(t *testing.T) {
+	ctx := context.Background()
+	delegate := &countingHealthCheckReconciler{}
+	reconciler := NewCachedHealthCheckReconciler(&FakeProvider{}, delegate)
+
+	endpoint := &v1alpha1.Endpoint{DNSName: "test.example.com"}
+	endpoint.SetProviderSpecific("fake/health-check-id", "test")
+	spec := HealthCheckSpec{Id: "test", Name: "test", Host: "test.example.com"}
+
+	reconcile := func() HealthCheckResult {
+		t.Helper()
+		result, err := reconciler.Reconcile(ctx, spec, endpoint)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		return result
+	}
+
+	reconcile()
+	if result := reconcile(); result.Result != HealthCheckNoop || delegate.reconciled != 1 {
+		t.Errorf("expected an unchanged spec not to be reconciled again, got %s after %d reconciliations", result.Result, delegate.reconciled)
+	}
+
+	spec.Path = "/healthz"
+	if reconcile(); delegate.reconciled != 2 {
+		t.Errorf("expected a changed spec to be reconciled, got %d reconciliations", delegate.reconciled)
+	}
+
+	// the health check was last reconciled a resync period ago
+	reconciler.syncCache.Store("test", cachedHealthCheck{spec: spec, at: time.Now().Add(-HealthCheckResyncPeriod)})
+	if reconcile(); delegate.reconciled != 3 {
+		t.Errorf("expected an unchanged spec to be reconciled after a resync period, got %d reconciliations", delegate.reconciled)
+	}
+	if result := reconcile(); result.Result != HealthCheckNoop || delegate.reconciled != 3 {
+		t.Errorf("expected the resync to be cached, got %s after %d reconciliations", result.Result, delegate.reconciled)
+	}
+}
@@ -0,0 +1,151 @@
+package health
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+)
+
+// MaxResponseBodySize is the maximum number of bytes of the response body read
+// by health checks. Body assertions are evaluated against this prefix only
+const MaxResponseBodySize = 64 * 1024
+
+// readBody reads up to MaxResponseBodySize bytes of the response body
+func readBody(res *http.Response) ([]byte, error) {
+	if res.Body == nil {
+		return nil, nil
+	}
+	return io.ReadAll(io.LimitReader(res.Body, MaxResponseBodySize))
+}
+
+// ResponseAssertions are the response assertions of a probe with their regular
+// expressions compiled, so that they are compiled once when the probe is added
+// or updated rather than on every check
+type ResponseAssertions struct {
+	headers []headerAssertion
+	body    []bodyAssertion
+}
+
+type headerAssertion struct {
+	v1alpha1.HeaderAssertion
+	matches *regexp.Regexp
+}
+
+type bodyAssertion struct {
+	v1alpha1.BodyAssertion
+	matches *regexp.Regexp
+}
+
+// CompileAssertions compiles the regular expressions of the assertions,
+// returning an error if any of them is invalid. Returns nil for nil assertions
+func CompileAssertions(assertions *v1alpha1.ResponseAssertions) (*ResponseAssertions, error) {
+	if assertions == nil {
+		return nil, nil
+	}
+	if err := assertions.Validate("responseAssertions"); err != nil {
+		return nil, err
+	}
+
+	compiled := &ResponseAssertions{}
+	for _, h := range assertions.Headers {
+		assertion := headerAssertion{HeaderAssertion: h}
+		if h.Matches != "" {
+			assertion.matches = regexp.MustCompile(h.Matches)
+		}
+		compiled.headers = append(compiled.headers, assertion)
+	}
+	for _, b := range assertions.Body {
+		assertion := bodyAssertion{BodyAssertion: b}
+		if b.Matches != "" {
+			assertion.matches = regexp.MustCompile(b.Matches)
+		}
+		compiled.body = append(compiled.body, assertion)
+	}
+	return compiled, nil
+}
+
+// checkAssertions evaluates the assertions against the response, returning
+// false and a description of the first failing assertion
+func checkAssertions(assertions *ResponseAssertions, header http.Header, body []byte) (string, bool) {
+	if assertions == nil {
+		return "", true
+	}
+
+	for _, h := range assertions.headers {
+		if reason, ok := checkHeaderAssertion(h, header); !ok {
+			return reason, false
+		}
+	}
+
+	for _, b := range assertions.body {
+		if reason, ok := checkBodyAssertion(b, body); !ok {
+			return reason, false
+		}
+	}
+
+	return "", true
+}
+
+func checkHeaderAssertion(assertion headerAssertion, header http.Header) (string, bool) {
+	values, present := header[http.CanonicalHeaderKey(assertion.Name)]
+	if !present {
+		return fmt.Sprintf("header assertion failed: header %s not present", assertion.Name), false
+	}
+	value := strings.Join(values, ",")
+
+	if assertion.Value != "" && value != assertion.Value {
+		return fmt.Sprintf("header assertion failed: header %s is %q, expected %q", assertion.Name, value, assertion.Value), false
+	}
+	if assertion.matches != nil && !assertion.matches.MatchString(value) {
+		return fmt.Sprintf("header assertion failed: header %s is %q, expected to match %q", assertion.Name, value, assertion.Matches), false
+	}
+	return "", true
+}
+
+func checkBodyAssertion(assertion bodyAssertion, body []byte) (string, bool) {
+	switch {
+	case assertion.Contains != "":
+		if !bytes.Contains(body, []byte(assertion.Contains)) {
+			return fmt.Sprintf("body assertion failed: expected body to contain %q", assertion.Contains), false
+		}
+	case assertion.matches != nil:
+		if !assertion.matches.Match(body) {
+			return fmt.Sprintf("body assertion failed: expected body to match %q", assertion.Matches), false
+		}
+	case assertion.JSONPath != "":
+		value, err := evaluateJSONPath(assertion.JSONPath, body)
+		if err != nil {
+			return fmt.Sprintf("body assertion failed: %s: %s", assertion.JSONPath, err), false
+		}
+		if value != assertion.Value {
+			return fmt.Sprintf("body assertion failed: %s is %q, expected %q", assertion.JSONPath, value, assertion.Value), false
+		}
+	}
+	return "", true
+}
+
+func evaluateJSONPath(expression string, body []byte) (string, error) {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", fmt.Errorf("invalid JSON body: %w", err)
+	}
+
+	j := jsonpath.New("assertion")
+	if err := j.Parse(v1alpha1.RelaxedJSONPath(expression)); err != nil {
+		return "", err
+	}
+
+	out := &bytes.Buffer{}
+	if err := j.Execute(out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
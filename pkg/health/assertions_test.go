@@ -0,0 +1,146 @@
+//go:build unit
+
+package health
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+)
+
+func TestCheckAssertions(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("X-Version", "v2")
+	body := []byte(`{"status":"degraded","checks":[{"name":"db","status":"ok"}]}`)
+
+	testCases := []struct {
+		name         string
+		assertions   *v1alpha1.ResponseAssertions
+		expectOK     bool
+		expectReason string
+	}{
+		{name: "no assertions", assertions: nil, expectOK: true},
+		{
+			name:       "body contains",
+			assertions: &v1alpha1.ResponseAssertions{Body: []v1alpha1.BodyAssertion{{Contains: `"db"`}}},
+			expectOK:   true,
+		},
+		{
+			name:         "body does not contain",
+			assertions:   &v1alpha1.ResponseAssertions{Body: []v1alpha1.BodyAssertion{{Contains: "healthy"}}},
+			expectReason: `expected body to contain "healthy"`,
+		},
+		{
+			name:         "body does not match",
+			assertions:   &v1alpha1.ResponseAssertions{Body: []v1alpha1.BodyAssertion{{Matches: `"status":"(ok|healthy)"}$`}}},
+			expectReason: "expected body to match",
+		},
+		{
+			name:       "json path matches",
+			assertions: &v1alpha1.ResponseAssertions{Body: []v1alpha1.BodyAssertion{{JSONPath: ".checks[0].status", Value: "ok"}}},
+			expectOK:   true,
+		},
+		{
+			name:         "json path does not match",
+			assertions:   &v1alpha1.ResponseAssertions{Body: []v1alpha1.BodyAssertion{{JSONPath: "{.status}", Value: "ok"}}},
+			expectReason: `{.status} is "degraded", expected "ok"`,
+		},
+		{
+			name:         "json path not found",
+			assertions:   &v1alpha1.ResponseAssertions{Body: []v1alpha1.BodyAssertion{{JSONPath: "{.missing}", Value: "ok"}}},
+			expectReason: "missing is not found",
+		},
+		{
+			name:       "header present and matching",
+			assertions: &v1alpha1.ResponseAssertions{Headers: []v1alpha1.HeaderAssertion{{Name: "content-type", Matches: "^application/json"}, {Name: "X-Version", Value: "v2"}, {Name: "X-Version"}}},
+			expectOK:   true,
+		},
+		{
+			name:         "header missing",
+			assertions:   &v1alpha1.ResponseAssertions{Headers: []v1alpha1.HeaderAssertion{{Name: "X-Ready"}}},
+			expectReason: "header X-Ready not present",
+		},
+		{
+			name:         "header value mismatch",
+			assertions:   &v1alpha1.ResponseAssertions{Headers: []v1alpha1.HeaderAssertion{{Name: "X-Version", Value: "v1"}}},
+			expectReason: `header X-Version is "v2", expected "v1"`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assertions, err := CompileAssertions(testCase.assertions)
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			reason, ok := checkAssertions(assertions, header, body)
+			if ok != testCase.expectOK {
+				t.Errorf("expected ok %v, got %v (reason: %s)", testCase.expectOK, ok, reason)
+			}
+			if !strings.Contains(reason, testCase.expectReason) {
+				t.Errorf("expected reason to contain %q, got %q", testCase.expectReason, reason)
+			}
+		})
+	}
+}
+
+func TestCompileAssertions_invalid(t *testing.T) {
+	for _, assertions := range []*v1alpha1.ResponseAssertions{
+		{Body: []v1alpha1.BodyAssertion{{Matches: "(unclosed"}}},
+		{Headers: []v1alpha1.HeaderAssertion{{Name: "X-Version", Matches: "[z-a]"}}},
+	} {
+		if _, err := CompileAssertions(assertions); err == nil {
+			t.Errorf("expected an error compiling %+v", assertions)
+		}
+	}
+}
+
+func TestQueuedProbeWorker_performRequest_assertions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"degraded"}`))
+	}))
+	defer server.Close()
+
+	host, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse server address: %s", err)
+	}
+	port, _ := strconv.Atoi(portStr)
+
+	assertions, err := CompileAssertions(&v1alpha1.ResponseAssertions{
+		Body: []v1alpha1.BodyAssertion{{JSONPath: "{.status}", Value: "ok"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	q := &QueuedProbeWorker{logger: logr.Discard()}
+	req := HealthRequest{
+		Host:               "test.example.com",
+		Address:            host,
+		Port:               port,
+		Path:               "/healthz",
+		Protocol:           v1alpha1.HttpProtocol,
+		ResponseAssertions: assertions,
+	}
+
+	result := q.performRequest(context.TODO(), req)
+	assertProbeResult(t, result, false, `body assertion failed: {.status} is "degraded", expected "ok"`)
+	if result.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, result.Status)
+	}
+
+	req.ResponseAssertions = nil
+	result = q.performRequest(context.TODO(), req)
+	assertProbeResult(t, result, true, "")
+}
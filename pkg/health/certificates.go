@@ -0,0 +1,84 @@
+package health
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CertificateErrorReason prefixes the reason of checks that failed because of
+// the certificate served by the endpoint, or the client certificate sent to it
+const CertificateErrorReason = "certificate error"
+
+// NewTLSConfig builds the TLS configuration of a probe from a PEM encoded CA
+// bundle and client certificate key pair. When the CA bundle is empty the
+// system roots are used, and no client certificate is sent when the pair is
+// empty
+func NewTLSConfig(caBundle, clientCert, clientKey []byte) (*tls.Config, error) {
+	config := &tls.Config{}
+
+	if len(caBundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("no valid PEM certificates found in CA bundle")
+		}
+		config.RootCAs = pool
+	}
+
+	if len(clientCert) > 0 || len(clientKey) > 0 {
+		pair, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{pair}
+	}
+
+	return config, nil
+}
+
+// tlsConfigFor returns the TLS configuration to use for the request, based on
+// the CA bundle and client certificate of the probe
+func tlsConfigFor(req HealthRequest, serverName string) *tls.Config {
+	config := &tls.Config{}
+	if req.TLSConfig != nil {
+		config = req.TLSConfig.Clone()
+	}
+	config.ServerName = serverName
+	config.InsecureSkipVerify = req.AllowInsecureCertificate
+	return config
+}
+
+// certificateError returns the reason for a check that failed because the
+// certificate served couldn't be verified, or the client certificate was
+// rejected, and false for any other error
+func certificateError(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+
+	var verificationErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var invalidErr x509.CertificateInvalidError
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &verificationErr) || errors.As(err, &unknownAuthorityErr) ||
+		errors.As(err, &invalidErr) || errors.As(err, &hostnameErr) {
+		return fmt.Sprintf("%s: %s", CertificateErrorReason, err.Error()), true
+	}
+
+	// gRPC only keeps the message of the underlying error
+	if strings.Contains(err.Error(), "x509: ") {
+		return fmt.Sprintf("%s: %s", CertificateErrorReason, err.Error()), true
+	}
+
+	// Alerts sent by servers rejecting the client certificate are not
+	// exported by crypto/tls
+	for _, alert := range []string{"tls: bad certificate", "tls: certificate required", "tls: unknown certificate"} {
+		if strings.Contains(err.Error(), alert) {
+			return fmt.Sprintf("%s: client certificate rejected: %s", CertificateErrorReason, err.Error()), true
+		}
+	}
+
+	return "", false
+}
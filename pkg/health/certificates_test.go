@@ -0,0 +1,145 @@
+//go:build unit
+
+package health
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	certPEM, keyPEM := newClientCertificate(t)
+
+	if _, err := NewTLSConfig([]byte("not a certificate"), nil, nil); err == nil {
+		t.Errorf("expected error for invalid CA bundle")
+	}
+	if _, err := NewTLSConfig(nil, certPEM, nil); err == nil {
+		t.Errorf("expected error for client certificate without key")
+	}
+
+	config, err := NewTLSConfig(certPEM, certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if config.RootCAs == nil || len(config.Certificates) != 1 {
+		t.Errorf("expected CA bundle and client certificate to be loaded, got %+v", config)
+	}
+}
+
+func TestQueuedProbeWorker_performRequest_certificates(t *testing.T) {
+	clientCertPEM, clientKeyPEM := newClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(clientCertPEM)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	host, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse server address: %s", err)
+	}
+	port, _ := strconv.Atoi(portStr)
+
+	testCases := []struct {
+		name          string
+		caBundle      []byte
+		clientCert    []byte
+		clientKey     []byte
+		expectHealthy bool
+		expectReason  string
+	}{
+		{
+			name:          "healthy with CA bundle and client certificate",
+			caBundle:      caBundle,
+			clientCert:    clientCertPEM,
+			clientKey:     clientKeyPEM,
+			expectHealthy: true,
+		},
+		{
+			name:         "certificate error when server certificate is not trusted",
+			clientCert:   clientCertPEM,
+			clientKey:    clientKeyPEM,
+			expectReason: CertificateErrorReason,
+		},
+		{
+			name:         "certificate error when client certificate is missing",
+			caBundle:     caBundle,
+			expectReason: CertificateErrorReason + ": client certificate rejected",
+		},
+	}
+
+	q := &QueuedProbeWorker{logger: logr.Discard()}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			tlsConfig, err := NewTLSConfig(testCase.caBundle, testCase.clientCert, testCase.clientKey)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			result := q.performRequest(context.TODO(), HealthRequest{
+				Host:      "example.com",
+				Address:   host,
+				Port:      port,
+				Protocol:  v1alpha1.HttpsProtocol,
+				TLSConfig: tlsConfig,
+			})
+			assertProbeResult(t, result, testCase.expectHealthy, testCase.expectReason)
+		})
+	}
+}
+
+// newClientCertificate returns a PEM encoded self-signed client certificate
+// and its key
+func newClientCertificate(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "probe"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %s", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// performTCPRequest checks that a TCP connection to the address can be
+// established before the context deadline
+func performTCPRequest(ctx context.Context, req HealthRequest) ProbeResult {
+	if req.Port == 0 {
+		return ProbeResult{CheckedAt: time.Now(), Healthy: false, Reason: "port is required for TCP probes"}
+	}
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(req.Address, strconv.Itoa(req.Port)))
+	if err != nil {
+		return ProbeResult{CheckedAt: time.Now(), Healthy: false, Reason: fmt.Sprintf("connection failed: %s", err.Error())}
+	}
+	defer conn.Close()
+
+	return ProbeResult{CheckedAt: time.Now(), Healthy: true}
+}
+
+// performTLSRequest checks that a TLS handshake with the address succeeds
+// before the context deadline, using the request ServerName (or Host) as SNI.
+// When CertificateExpiryThreshold is set the probe also fails if the served
+// certificate expires within the threshold
+func performTLSRequest(ctx context.Context, req HealthRequest) ProbeResult {
+	// Default port to 443
+	port := 443
+	if req.Port != 0 {
+		port = req.Port
+	}
+
+	serverName := req.ServerName
+	if serverName == "" {
+		serverName = req.Host
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{},
+		Config:    tlsConfigFor(req, serverName),
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(req.Address, strconv.Itoa(port)))
+	if reason, ok := certificateError(err); ok {
+		return ProbeResult{CheckedAt: time.Now(), Healthy: false, Reason: reason}
+	} else if err != nil {
+		return ProbeResult{CheckedAt: time.Now(), Healthy: false, Reason: fmt.Sprintf("TLS handshake failed: %s", err.Error())}
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if reason, ok := checkCertificateExpiry(certs, req.CertificateExpiryThreshold, time.Now()); !ok {
+		return ProbeResult{CheckedAt: time.Now(), Healthy: false, Reason: reason}
+	}
+
+	return ProbeResult{CheckedAt: time.Now(), Healthy: true}
+}
+
+// checkCertificateExpiry returns false, and the reason, if the leaf
+// certificate expires within threshold of now
+func checkCertificateExpiry(certs []*x509.Certificate, threshold time.Duration, now time.Time) (string, bool) {
+	if threshold <= 0 || len(certs) == 0 {
+		return "", true
+	}
+
+	leaf := certs[0]
+	if now.Add(threshold).After(leaf.NotAfter) {
+		return fmt.Sprintf("certificate for %s expires at %s, within %s", leaf.Subject.CommonName, leaf.NotAfter.Format(time.RFC3339), threshold), false
+	}
+	return "", true
+}
@@ -0,0 +1,130 @@
+//go:build unit
+
+package health
+
+import (
+	"context"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+)
+
+func TestPerformTCPRequest(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	openPort := listener.Addr().(*net.TCPAddr).Port
+
+	// grab a port and release it so nothing is listening on it
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	closed.Close()
+	defer listener.Close()
+
+	testCases := []struct {
+		name          string
+		port          int
+		expectHealthy bool
+		expectReason  string
+	}{
+		{name: "healthy when connection succeeds", port: openPort, expectHealthy: true},
+		{name: "unhealthy when connection is refused", port: closedPort, expectReason: "connection failed"},
+		{name: "unhealthy when port is not set", port: 0, expectReason: "port is required"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := performTCPRequest(context.TODO(), HealthRequest{
+				Host:     "test.example.com",
+				Address:  "127.0.0.1",
+				Port:     testCase.port,
+				Protocol: v1alpha1.TcpProtocol,
+			})
+			assertProbeResult(t, result, testCase.expectHealthy, testCase.expectReason)
+		})
+	}
+}
+
+func TestPerformTLSRequest(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	host, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse server address: %s", err)
+	}
+	port, _ := strconv.Atoi(portStr)
+
+	testCases := []struct {
+		name          string
+		req           HealthRequest
+		expectHealthy bool
+		expectReason  string
+	}{
+		{
+			name:          "healthy when handshake succeeds",
+			req:           HealthRequest{Host: "example.com", AllowInsecureCertificate: true},
+			expectHealthy: true,
+		},
+		{
+			name:         "certificate error when certificate is not trusted",
+			req:          HealthRequest{Host: "example.com"},
+			expectReason: CertificateErrorReason,
+		},
+		{
+			name: "unhealthy when certificate expires within threshold",
+			req: HealthRequest{
+				Host:                       "example.com",
+				AllowInsecureCertificate:   true,
+				CertificateExpiryThreshold: 200 * 365 * 24 * time.Hour,
+			},
+			expectReason: "expires at",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.req.Address = host
+			testCase.req.Port = port
+			testCase.req.Protocol = v1alpha1.TlsProtocol
+			result := performTLSRequest(context.TODO(), testCase.req)
+			assertProbeResult(t, result, testCase.expectHealthy, testCase.expectReason)
+		})
+	}
+}
+
+func TestCheckCertificateExpiry(t *testing.T) {
+	now := time.Now()
+	certs := []*x509.Certificate{{NotAfter: now.Add(48 * time.Hour)}}
+
+	if _, ok := checkCertificateExpiry(certs, 0, now); !ok {
+		t.Errorf("expected expiry check to be disabled with no threshold")
+	}
+	if _, ok := checkCertificateExpiry(certs, 24*time.Hour, now); !ok {
+		t.Errorf("expected certificate to be valid for longer than threshold")
+	}
+	if _, ok := checkCertificateExpiry(certs, 72*time.Hour, now); ok {
+		t.Errorf("expected certificate to expire within threshold")
+	}
+}
+
+func assertProbeResult(t *testing.T, result ProbeResult, expectHealthy bool, expectReason string) {
+	t.Helper()
+	if result.Healthy != expectHealthy {
+		t.Errorf("expected healthy %v, got %v (reason: %s)", expectHealthy, result.Healthy, result.Reason)
+	}
+	if !strings.Contains(result.Reason, expectReason) {
+		t.Errorf("expected reason to contain %q, got %q", expectReason, result.Reason)
+	}
+}
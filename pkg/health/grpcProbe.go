@@ -0,0 +1,64 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// performGRPCRequest calls the standard grpc.health.v1.Health/Check method on
+// the address, using the request Host as authority. The endpoint is healthy
+// if the requested service reports SERVING
+func performGRPCRequest(ctx context.Context, req HealthRequest) ProbeResult {
+	// Default port to 443, or 80 for plaintext
+	port := 443
+	if req.GRPCPlaintext {
+		port = 80
+	}
+	if req.Port != 0 {
+		port = req.Port
+	}
+
+	var creds credentials.TransportCredentials
+	if req.GRPCPlaintext {
+		creds = insecure.NewCredentials()
+	} else {
+		serverName := req.ServerName
+		if serverName == "" {
+			serverName = req.Host
+		}
+		creds = credentials.NewTLS(tlsConfigFor(req, serverName))
+	}
+
+	conn, err := grpc.DialContext(ctx, net.JoinHostPort(req.Address, strconv.Itoa(port)),
+		grpc.WithTransportCredentials(creds),
+		grpc.WithAuthority(req.Host),
+	)
+	if err != nil {
+		return ProbeResult{CheckedAt: time.Now(), Healthy: false, Reason: fmt.Sprintf("connection failed: %s", err.Error())}
+	}
+	defer conn.Close()
+
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: req.GRPCService})
+	if err != nil {
+		if reason, ok := certificateError(err); ok {
+			return ProbeResult{CheckedAt: time.Now(), Healthy: false, Reason: reason}
+		}
+		s := status.Convert(err)
+		return ProbeResult{CheckedAt: time.Now(), Healthy: false, Reason: fmt.Sprintf("gRPC health check failed: %s: %s", s.Code(), s.Message())}
+	}
+
+	if res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return ProbeResult{CheckedAt: time.Now(), Healthy: false, Reason: fmt.Sprintf("gRPC health status: %s", res.GetStatus())}
+	}
+
+	return ProbeResult{CheckedAt: time.Now(), Healthy: true}
+}
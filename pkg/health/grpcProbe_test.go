@@ -0,0 +1,62 @@
+//go:build unit
+
+package health
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+)
+
+func TestPerformGRPCRequest(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	healthServer := grpchealth.NewServer()
+	healthServer.SetServingStatus("serving", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("not-serving", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	testCases := []struct {
+		name          string
+		service       string
+		plaintext     bool
+		expectHealthy bool
+		expectReason  string
+	}{
+		{name: "healthy when server is serving", service: "", plaintext: true, expectHealthy: true},
+		{name: "healthy when service is serving", service: "serving", plaintext: true, expectHealthy: true},
+		{name: "unhealthy when service is not serving", service: "not-serving", plaintext: true, expectReason: "gRPC health status: NOT_SERVING"},
+		{name: "unhealthy when service is unknown", service: "unknown", plaintext: true, expectReason: "NotFound"},
+		{name: "unhealthy when TLS is expected", service: "serving", expectReason: "gRPC health check failed: Unavailable"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := performGRPCRequest(context.TODO(), HealthRequest{
+				Host:                     "test.example.com",
+				Address:                  "127.0.0.1",
+				Port:                     listener.Addr().(*net.TCPAddr).Port,
+				Protocol:                 v1alpha1.GrpcProtocol,
+				GRPCService:              testCase.service,
+				GRPCPlaintext:            testCase.plaintext,
+				AllowInsecureCertificate: true,
+			})
+			assertProbeResult(t, result, testCase.expectHealthy, testCase.expectReason)
+		})
+	}
+}
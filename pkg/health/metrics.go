@@ -24,27 +24,90 @@ var (
 		},
 		[]string{"gateway_name", "gateway_namespace", "listener"},
 	)
+
+	healthCheckQueueDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "mgc_dns_health_check_queue_depth",
+			Help: "MGC DNS Health Check number of health checks waiting to be performed",
+		},
+	)
+
+	healthChecksInFlight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "mgc_dns_health_check_in_flight",
+			Help: "MGC DNS Health Check number of health checks being performed",
+		},
+	)
+
+	healthCheckLateness = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "mgc_dns_health_check_lateness_seconds",
+			Help:    "MGC DNS Health Check time between a health check being due and it being started",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60},
+		},
+	)
+
+	healthCheckLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "mgc_dns_health_check_latency_seconds",
+			Help:    "MGC DNS Health Check Probe time taken by health checks",
+			Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		},
+		[]string{"gateway_name", "gateway_namespace", "listener", "address"},
+	)
+
+	healthCheckShardMembers = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mgc_dns_health_check_shard_members",
+			Help: "MGC DNS Health Check number of replicas sharing the probes, as seen by the shard",
+		},
+		[]string{"shard"},
+	)
+
+	healthCheckShardProbes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mgc_dns_health_check_shard_probes",
+			Help: "MGC DNS Health Check number of probes checked by the shard",
+		},
+		[]string{"shard"},
+	)
+
+	healthCheckShardRebalances = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mgc_dns_health_check_shard_rebalances_total",
+			Help: "MGC DNS Health Check total number of times the probes were reassigned because replicas joined or left",
+		},
+		[]string{"shard"},
+	)
 )
 
 func init() {
 	metrics.Registry.MustRegister(
 		healthCheckAttempts,
 		healthCheckFailures,
+		healthCheckQueueDepth,
+		healthChecksInFlight,
+		healthCheckLateness,
+		healthCheckLatency,
+		healthCheckShardMembers,
+		healthCheckShardProbes,
+		healthCheckShardRebalances,
 	)
 }
 
 // InstrumentedProbeNotifier wraps a notifier by incrementing the failure counter
-// when the result is unhealthy
+// when the result is unhealthy and observing the latency of the health check
 type InstrumentedProbeNotifier struct {
-	gatewayName, gatewayNamespace, listener string
-	notifier                                ProbeNotifier
+	gatewayName, gatewayNamespace, listener, address string
+	notifier                                         ProbeNotifier
 }
 
-func NewInstrumentedProbeNotifier(gatewayName, gatewayNamespace, listener string, notifier ProbeNotifier) *InstrumentedProbeNotifier {
+func NewInstrumentedProbeNotifier(gatewayName, gatewayNamespace, listener, address string, notifier ProbeNotifier) *InstrumentedProbeNotifier {
 	return &InstrumentedProbeNotifier{
 		gatewayName:      gatewayName,
 		gatewayNamespace: gatewayNamespace,
 		listener:         listener,
+		address:          address,
 		notifier:         notifier,
 	}
 }
@@ -54,6 +117,7 @@ func (n *InstrumentedProbeNotifier) Notify(ctx context.Context, result ProbeResu
 	if !result.Healthy {
 		healthCheckFailures.WithLabelValues(n.gatewayName, n.gatewayNamespace, n.listener).Inc()
 	}
+	healthCheckLatency.WithLabelValues(n.gatewayName, n.gatewayNamespace, n.listener, n.address).Observe(result.Latency.Seconds())
 
 	return n.notifier.Notify(ctx, result)
 }
@@ -8,10 +8,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
+// Monitor runs the probe queuers. It only runs on the leader, so that a
+// single replica checks the probes: queuers added before the monitor is
+// started are held until it starts, and all of them are stopped when it stops,
+// either on shutdown or when leadership is lost. The new leader restores the
+// schedule of each probe from its status, see ProbeQueuer.LastCheckedAt.
+//
+// When Shard is set the monitor runs on every replica instead, and only checks
+// the probes owned by the replica
 type Monitor struct {
 	ProbeQueuers []*ProbeQueuer
+	Shard        *Shard
 
-	mux sync.Mutex
+	running bool
+	mux     sync.Mutex
 }
 
 func NewMonitor() *Monitor {
@@ -24,12 +34,20 @@ func (m *Monitor) Start(ctx context.Context) error {
 	logger := log.FromContext(ctx)
 	logger.V(3).Info("Starting health check monitor")
 
+	m.mux.Lock()
+	m.running = true
+	for _, probeQueuer := range m.ProbeQueuers {
+		probeQueuer.Start()
+	}
+	m.mux.Unlock()
+
 	<-ctx.Done()
 	m.mux.Lock()
 	defer m.mux.Unlock()
 
 	logger.Info("Stopping health check monitor")
 
+	m.running = false
 	for _, probeQueuer := range m.ProbeQueuers {
 		probeQueuer.Stop()
 	}
@@ -37,7 +55,27 @@ func (m *Monitor) Start(ctx context.Context) error {
 	return nil
 }
 
+// NeedLeaderElection makes the manager start the monitor only once elected,
+// unless the probes are sharded between the replicas
+func (m *Monitor) NeedLeaderElection() bool {
+	return m.Shard == nil
+}
+
+// Owns returns whether the probe should be checked by this replica
+func (m *Monitor) Owns(id string) bool {
+	return m.Shard == nil || m.Shard.Owns(id)
+}
+
+// recordShardProbes updates the number of probes checked by the shard
+func (m *Monitor) recordShardProbes() {
+	if m.Shard == nil {
+		return
+	}
+	healthCheckShardProbes.WithLabelValues(m.Shard.Identity).Set(float64(len(m.ProbeQueuers)))
+}
+
 var _ manager.Runnable = &Monitor{}
+var _ manager.LeaderElectionRunnable = &Monitor{}
 
 func (m *Monitor) HasProbe(id string) bool {
 	m.mux.Lock()
@@ -74,7 +112,10 @@ func (m *Monitor) AddProbeQueuer(probeQueuer *ProbeQueuer) bool {
 	}
 
 	m.ProbeQueuers = append(m.ProbeQueuers, probeQueuer)
-	probeQueuer.Start()
+	m.recordShardProbes()
+	if m.running {
+		probeQueuer.Start()
+	}
 	return true
 }
 
@@ -93,4 +134,5 @@ func (m *Monitor) RemoveProbe(id string) {
 	}
 
 	m.ProbeQueuers = updatedProbes
+	m.recordShardProbes()
 }
@@ -0,0 +1,135 @@
+//go:build unit
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestProbeQueuer_firstCheckDelay(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	interval := 30 * time.Second
+
+	testCases := []struct {
+		name          string
+		lastCheckedAt time.Time
+		expected      time.Duration
+	}{
+		{
+			name:     "never checked",
+			expected: 0,
+		},
+		{
+			name:          "checked within the interval",
+			lastCheckedAt: now.Add(-10 * time.Second),
+			expected:      20 * time.Second,
+		},
+		{
+			name:          "checked longer than the interval ago",
+			lastCheckedAt: now.Add(-time.Minute),
+			expected:      0,
+		},
+		{
+			name:          "checked in the future",
+			lastCheckedAt: now.Add(time.Minute),
+			expected:      interval,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			p := &ProbeQueuer{Interval: interval, LastCheckedAt: testCase.lastCheckedAt}
+			if delay := p.firstCheckDelay(now); delay != testCase.expected {
+				t.Errorf("expected delay %s, got %s", testCase.expected, delay)
+			}
+		})
+	}
+}
+
+func TestMonitor_leadership(t *testing.T) {
+	queue := NewRequestQueue(1, rate.Inf, 1)
+	monitor := NewMonitor()
+
+	if !monitor.NeedLeaderElection() {
+		t.Fatalf("expected the monitor to need leader election")
+	}
+
+	// a stale probe added before the monitor is started is held until then
+	monitor.AddProbeQueuer(&ProbeQueuer{
+		ID:            "test/stale",
+		Interval:      time.Hour,
+		LastCheckedAt: time.Now().Add(-2 * time.Hour),
+		Queue:         queue,
+	})
+	time.Sleep(10 * time.Millisecond)
+	if queued := queuedRequests(queue); queued != 0 {
+		t.Fatalf("expected no health checks before the monitor starts, got %d", queued)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- monitor.Start(ctx)
+	}()
+
+	// a probe checked recently waits for the rest of its interval
+	monitor.AddProbeQueuer(&ProbeQueuer{
+		ID:            "test/recent",
+		Interval:      time.Hour,
+		LastCheckedAt: time.Now(),
+		Queue:         queue,
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for queuedRequests(queue) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if queued := queuedRequests(queue); queued != 1 {
+		t.Fatalf("expected the stale probe to be checked straight away, got %d health checks", queued)
+	}
+	queue.mux.Lock()
+	if _, ok := queue.pending["test/stale"]; !ok {
+		t.Errorf("expected the health check to be for the stale probe")
+	}
+	queue.mux.Unlock()
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	for _, probeQueuer := range monitor.ProbeQueuers {
+		if probeQueuer.started {
+			t.Errorf("expected probe %s to be stopped", probeQueuer.ID)
+		}
+	}
+}
+
+func queuedRequests(q *QueuedProbeWorker) int {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	return q.queue.Len()
+}
+
+func TestMonitor_sharded(t *testing.T) {
+	shard := &Shard{Identity: "replica-a", members: []string{"replica-a", "replica-b"}}
+	monitor := NewMonitor()
+	monitor.Shard = shard
+
+	if monitor.NeedLeaderElection() {
+		t.Errorf("expected a sharded monitor to run on every replica")
+	}
+	for i := 0; i < 10; i++ {
+		id := fmt.Sprintf("test/probe-%d", i)
+		if monitor.Owns(id) != shard.Owns(id) {
+			t.Errorf("expected the monitor to own probe %s only if the shard does", id)
+		}
+	}
+	if !NewMonitor().Owns("test/probe-0") {
+		t.Errorf("expected a monitor without shard to own every probe")
+	}
+}
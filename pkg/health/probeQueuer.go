@@ -2,6 +2,7 @@ package health
 
 import (
 	"context"
+	"crypto/tls"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -14,15 +15,27 @@ import (
 type ProbeQueuer struct {
 	ID string
 
-	Interval                 time.Duration
-	Protocol                 v1alpha1.HealthProtocol
-	Path                     string
-	IPAddress                string
-	Host                     string
-	Port                     int
-	AdditionalHeaders        v1alpha1.AdditionalHeaders
-	ExpectedResponses        []int
-	AllowInsecureCertificate bool
+	Interval                   time.Duration
+	Timeout                    time.Duration
+	Protocol                   v1alpha1.HealthProtocol
+	Path                       string
+	IPAddress                  string
+	Host                       string
+	Port                       int
+	AdditionalHeaders          v1alpha1.AdditionalHeaders
+	ExpectedResponses          []int
+	AllowInsecureCertificate   bool
+	ServerName                 string
+	CertificateExpiryThreshold time.Duration
+	GRPCService                string
+	GRPCPlaintext              bool
+	ResponseAssertions         *ResponseAssertions
+	TLSConfig                  *tls.Config
+
+	// LastCheckedAt is when the probe was last checked, restored from its
+	// persisted status. The first check is due one Interval after it, and
+	// straight away when that has already passed
+	LastCheckedAt time.Time
 
 	Notifier ProbeNotifier
 	Queue    *QueuedProbeWorker
@@ -37,6 +50,8 @@ type ProbeResult struct {
 	Reason    string
 	Status    int
 	Healthy   bool
+	// Latency is the time the health check took
+	Latency time.Duration
 }
 
 type ProbeNotifier interface {
@@ -45,6 +60,16 @@ type ProbeNotifier interface {
 
 type NotificationResult struct {
 	Requeue bool
+	// Transition is set when the result changed the health of the probe
+	Transition *Transition
+}
+
+// Transition is a change in the health of a probe
+type Transition struct {
+	// WasHealthy is nil for the first result of the probe
+	WasHealthy *bool
+	Healthy    bool
+	Reason     string
 }
 
 func (p *ProbeQueuer) Start() {
@@ -58,20 +83,31 @@ func (p *ProbeQueuer) Start() {
 
 	p.logger.V(3).Info("Starting probe queuer", "id", p.ID)
 
+	delay := p.firstCheckDelay(time.Now())
 	go func() {
 		for {
 			select {
-			case <-time.After(p.Interval):
+			case <-time.After(delay):
+				delay = p.Interval
 				p.Queue.EnqueueCheck(HealthRequest{
-					Host:                     p.Host,
-					Path:                     p.Path,
-					Protocol:                 p.Protocol,
-					Address:                  p.IPAddress,
-					Port:                     p.Port,
-					AdditionalHeaders:        p.AdditionalHeaders,
-					ExpectedResponses:        p.ExpectedResponses,
-					Notifier:                 p.Notifier,
-					AllowInsecureCertificate: p.AllowInsecureCertificate,
+					ID:                         p.ID,
+					DueAt:                      time.Now(),
+					Timeout:                    p.Timeout,
+					Host:                       p.Host,
+					Path:                       p.Path,
+					Protocol:                   p.Protocol,
+					Address:                    p.IPAddress,
+					Port:                       p.Port,
+					AdditionalHeaders:          p.AdditionalHeaders,
+					ExpectedResponses:          p.ExpectedResponses,
+					Notifier:                   p.Notifier,
+					AllowInsecureCertificate:   p.AllowInsecureCertificate,
+					ServerName:                 p.ServerName,
+					CertificateExpiryThreshold: p.CertificateExpiryThreshold,
+					GRPCService:                p.GRPCService,
+					GRPCPlaintext:              p.GRPCPlaintext,
+					ResponseAssertions:         p.ResponseAssertions,
+					TLSConfig:                  p.TLSConfig,
 				})
 			case <-ctx.Done():
 				return
@@ -89,4 +125,23 @@ func (p *ProbeQueuer) Stop() {
 
 	p.logger.V(3).Info("stopping probe", "id", p.ID)
 	p.cancel()
+	p.started = false
+}
+
+// firstCheckDelay returns the time to wait before the first check, so that
+// probes restored after a restart or a change of leader keep their schedule
+// rather than waiting a full Interval. Probes never checked are due straight
+// away
+func (p *ProbeQueuer) firstCheckDelay(now time.Time) time.Duration {
+	if p.LastCheckedAt.IsZero() {
+		return 0
+	}
+	delay := p.LastCheckedAt.Add(p.Interval).Sub(now)
+	if delay < 0 {
+		return 0
+	}
+	if delay > p.Interval {
+		return p.Interval
+	}
+	return delay
 }
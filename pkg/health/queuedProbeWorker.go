@@ -1,16 +1,17 @@
 package health
 
 import (
+	"container/heap"
 	"context"
 	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
-	"runtime"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
 
 	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -18,124 +19,248 @@ import (
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
 )
 
-// QueuedProbeWorker funnels incoming health check requests from health probes,
-// processing them one at a time and spacing them by a specified duration
-type QueuedProbeWorker struct {
-	Throttle time.Duration
+// DefaultProbeTimeout is the time a health check can take when the request
+// doesn't set a timeout
+const DefaultProbeTimeout = 10 * time.Second
+
+const defaultRequeueDelay = time.Second
 
-	requests []HealthRequest
-	logger   logr.Logger
+// QueuedProbeWorker schedules incoming health check requests from health
+// probes and performs them on a bounded pool of workers. Requests are started
+// in order of when they are due, at a rate limited by a token bucket. Only one
+// request per probe is queued at any time, so the queue is bounded by the
+// number of probes
+type QueuedProbeWorker struct {
+	// Workers is the maximum number of health checks performed concurrently
+	Workers int
+	// Limiter limits the rate at which health checks are started
+	Limiter *rate.Limiter
+	// Sharded runs the worker on every replica rather than on the leader only,
+	// as each replica checks its share of the probes. See Shard
+	Sharded bool
+	// RequeueDelay is the time after which a request requeued by its
+	// notifier is due again
+	RequeueDelay time.Duration
+
+	queue   requestHeap
+	pending map[string]*queuedRequest
+	ready   chan struct{}
+	logger  logr.Logger
 
 	mux sync.Mutex
 }
 
-func NewRequestQueue(throttle time.Duration) *QueuedProbeWorker {
+func NewRequestQueue(workers int, limit rate.Limit, burst int) *QueuedProbeWorker {
+	if workers < 1 {
+		workers = 1
+	}
 	return &QueuedProbeWorker{
-		Throttle: throttle,
-		requests: make([]HealthRequest, 0),
+		Workers:      workers,
+		Limiter:      rate.NewLimiter(limit, burst),
+		RequeueDelay: defaultRequeueDelay,
+		pending:      map[string]*queuedRequest{},
+		ready:        make(chan struct{}, 1),
+		logger:       logr.Discard(),
 	}
 }
 
 type HealthRequest struct {
+	// ID identifies the probe the request is for
+	ID string
+	// DueAt is when the request should ideally be performed. Defaults to the
+	// time it is enqueued
+	DueAt time.Time
+	// Timeout is the time the health check can take. Defaults to DefaultProbeTimeout
+	Timeout time.Duration
+
 	Host, Path, Address      string
 	Protocol                 v1alpha1.HealthProtocol
 	Port                     int
 	AdditionalHeaders        v1alpha1.AdditionalHeaders
 	ExpectedResponses        []int
 	AllowInsecureCertificate bool
-	Notifier                 ProbeNotifier
+	// ServerName overrides the SNI sent by TLS probes. Defaults to Host
+	ServerName string
+	// CertificateExpiryThreshold fails TLS probes when the served certificate
+	// expires within the given duration. Disabled when zero
+	CertificateExpiryThreshold time.Duration
+	// GRPCService is the service name sent in gRPC health checks. An empty
+	// name checks the overall health of the server
+	GRPCService string
+	// GRPCPlaintext disables TLS for gRPC health checks
+	GRPCPlaintext bool
+	// ResponseAssertions are evaluated against the response of HTTP and HTTPS
+	// health checks
+	ResponseAssertions *ResponseAssertions
+	// TLSConfig holds the CA bundle and client certificate used by HTTPS, TLS
+	// and gRPC health checks, see NewTLSConfig. ServerName and
+	// InsecureSkipVerify are set from the request
+	TLSConfig *tls.Config
+	Notifier  ProbeNotifier
 }
 
+// EnqueueCheck schedules the request. If a request for the same probe is
+// already queued the earliest due of the two is kept, so slow checks don't
+// pile up behind each other
 func (q *QueuedProbeWorker) EnqueueCheck(req HealthRequest) {
 	q.mux.Lock()
 	defer q.mux.Unlock()
 
+	if req.DueAt.IsZero() {
+		req.DueAt = time.Now()
+	}
+
+	if req.ID != "" {
+		if queued, ok := q.pending[req.ID]; ok {
+			q.logger.V(3).Info("health check already queued", "id", req.ID)
+			if req.DueAt.Before(queued.DueAt) {
+				queued.HealthRequest = req
+				heap.Fix(&q.queue, queued.index)
+			}
+			return
+		}
+	}
+
 	q.logger.V(3).Info("enqueueing health check", "request", req)
-	q.requests = append(q.requests, req)
+	queued := &queuedRequest{HealthRequest: req}
+	heap.Push(&q.queue, queued)
+	if req.ID != "" {
+		q.pending[req.ID] = queued
+	}
+	healthCheckQueueDepth.Set(float64(q.queue.Len()))
+	q.signal()
+}
+
+// signal wakes up a worker waiting for a request, without blocking if one
+// has already been signalled
+func (q *QueuedProbeWorker) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
 }
 
-// deqeue takes the next element of the queue and returns it. It blocks
-// if the queue is empty, and returns false if the context is cancelled
+// dequeue takes the request that is due first and returns it. It blocks
+// until a request is available, and returns false if the context is cancelled
 func (q *QueuedProbeWorker) dequeue(ctx context.Context) (HealthRequest, bool) {
-	reqChn := make(chan HealthRequest)
-
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				close(reqChn)
-				return
-			default:
+	for {
+		q.mux.Lock()
+		if q.queue.Len() > 0 {
+			queued := heap.Pop(&q.queue).(*queuedRequest)
+			if queued.ID != "" {
+				delete(q.pending, queued.ID)
 			}
-
-			q.mux.Lock()
-			if len(q.requests) == 0 {
-				q.mux.Unlock()
-				runtime.Gosched()
-				continue
+			healthCheckQueueDepth.Set(float64(q.queue.Len()))
+			if q.queue.Len() > 0 {
+				q.signal()
 			}
-
-			req := q.requests[0]
-			q.requests = q.requests[1:]
 			q.mux.Unlock()
-
-			reqChn <- req
-			return
+			return queued.HealthRequest, true
 		}
+		q.mux.Unlock()
 
-	}()
-
-	req, ok := <-reqChn
-	return req, ok
+		select {
+		case <-ctx.Done():
+			return HealthRequest{}, false
+		case <-q.ready:
+		}
+	}
 }
 
 func (q *QueuedProbeWorker) Start(ctx context.Context) error {
 	q.logger = log.FromContext(ctx)
-	q.logger.V(3).Info("Starting health check queue")
+	q.logger.V(3).Info("Starting health check queue", "workers", q.Workers, "limit", q.Limiter.Limit(), "burst", q.Limiter.Burst())
 	defer q.logger.Info("Stopping health check queue")
 
+	wg := sync.WaitGroup{}
+	for i := 0; i < q.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(ctx)
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() != context.Canceled {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// NeedLeaderElection makes the manager start the worker only once elected,
+// unless the probes are sharded between the replicas
+func (q *QueuedProbeWorker) NeedLeaderElection() bool {
+	return !q.Sharded
+}
+
+// work processes requests until the context is cancelled
+func (q *QueuedProbeWorker) work(ctx context.Context) {
 	for {
-		select {
-		case <-ctx.Done():
-			if ctx.Err() != context.Canceled {
-				return ctx.Err()
-			}
-			return nil
-		case <-time.After(q.Throttle):
-			q.logger.V(3).Info("dequeing health check")
-			req, ok := q.dequeue(ctx)
-			if !ok {
-				return nil
-			}
+		if err := q.Limiter.Wait(ctx); err != nil {
+			return
+		}
 
-			q.process(ctx, req)
+		req, ok := q.dequeue(ctx)
+		if !ok {
+			return
 		}
+
+		q.process(ctx, req)
 	}
 }
 
 func (q *QueuedProbeWorker) process(ctx context.Context, req HealthRequest) {
-	go func() {
-		result := q.performRequest(ctx, req)
-		notificationResult, err := req.Notifier.Notify(ctx, result)
-		if err != nil {
-			q.logger.Error(err, "failed to notify health check result")
-		}
+	healthCheckLateness.Observe(time.Since(req.DueAt).Seconds())
+	healthChecksInFlight.Inc()
+	defer healthChecksInFlight.Dec()
 
-		if notificationResult.Requeue {
-			q.EnqueueCheck(req)
-		}
-	}()
+	timeout := req.Timeout
+	if timeout == 0 {
+		timeout = DefaultProbeTimeout
+	}
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	started := time.Now()
+	result := q.performRequest(probeCtx, req)
+	result.Latency = time.Since(started)
+	cancel()
+
+	notificationResult, err := req.Notifier.Notify(ctx, result)
+	if err != nil {
+		q.logger.Error(err, "failed to notify health check result")
+	}
+
+	if notificationResult.Requeue {
+		// the request is due again once the delay has passed, rather than at
+		// its past DueAt ahead of the requests that are due
+		req.DueAt = time.Now().Add(q.RequeueDelay)
+		time.AfterFunc(q.RequeueDelay, func() {
+			if ctx.Err() == nil {
+				q.EnqueueCheck(req)
+			}
+		})
+	}
 }
 
 func (q *QueuedProbeWorker) performRequest(ctx context.Context, req HealthRequest) ProbeResult {
 	q.logger.V(3).Info("performing health check", "request", req)
 
-	probeClient := &http.Client{
-		Transport: TransportWithDNSResponse(map[string]string{req.Host: req.Address}),
+	switch {
+	case req.Protocol.IsTcp():
+		return performTCPRequest(ctx, req)
+	case req.Protocol.IsTls():
+		return performTLSRequest(ctx, req)
+	case req.Protocol.IsGrpc():
+		return performGRPCRequest(ctx, req)
 	}
 
-	if req.AllowInsecureCertificate {
-		probeClient.Transport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	serverName := req.ServerName
+	if serverName == "" {
+		serverName = req.Host
+	}
+
+	probeClient := &http.Client{
+		Transport: TransportWithDNSResponse(map[string]string{req.Host: req.Address}, tlsConfigFor(req, serverName)),
 	}
 
 	// Default port to 80
@@ -159,10 +284,16 @@ func (q *QueuedProbeWorker) performRequest(ctx context.Context, req HealthReques
 	res, err := probeClient.Do(httpReq)
 	if utilnet.IsConnectionReset(err) {
 		res = &http.Response{StatusCode: 104}
+	} else if reason, ok := certificateError(err); ok {
+		return ProbeResult{CheckedAt: time.Now(), Healthy: false, Reason: reason}
 	} else if err != nil {
 		return ProbeResult{CheckedAt: time.Now(), Healthy: false, Reason: fmt.Sprintf("error: %s, response: %+v", err.Error(), res)}
 	}
 
+	if res.Body != nil {
+		defer res.Body.Close()
+	}
+
 	// Create the result based on the response
 	if req.ExpectedResponses == nil {
 		req.ExpectedResponses = []int{200, 201}
@@ -173,6 +304,12 @@ func (q *QueuedProbeWorker) performRequest(ctx context.Context, req HealthReques
 	if !checkResponse(res.StatusCode, req.ExpectedResponses) {
 		healthy = false
 		reason = fmt.Sprintf("Status code: %d", res.StatusCode)
+	} else if req.ResponseAssertions != nil {
+		body, err := readBody(res)
+		if err != nil {
+			return ProbeResult{CheckedAt: time.Now(), Healthy: false, Status: res.StatusCode, Reason: fmt.Sprintf("error reading response body: %s", err.Error())}
+		}
+		reason, healthy = checkAssertions(req.ResponseAssertions, res.Header, body)
 	}
 
 	return ProbeResult{
@@ -193,8 +330,11 @@ func checkResponse(response int, expected []int) bool {
 }
 
 // TransportWithDNSResponse creates a new transport which overrides hostnames.
-func TransportWithDNSResponse(overrides map[string]string) http.RoundTripper {
+// tlsConfig, if set, configures the CA bundle and client certificate used for
+// HTTPS requests
+func TransportWithDNSResponse(overrides map[string]string, tlsConfig *tls.Config) http.RoundTripper {
 	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
 	dialer := &net.Dialer{
 		Timeout:   30 * time.Second,
 		KeepAlive: 30 * time.Second,
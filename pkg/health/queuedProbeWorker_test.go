@@ -0,0 +1,230 @@
+//go:build unit
+
+package health
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+)
+
+type notifierFunc func(ctx context.Context, result ProbeResult) (NotificationResult, error)
+
+func (f notifierFunc) Notify(ctx context.Context, result ProbeResult) (NotificationResult, error) {
+	return f(ctx, result)
+}
+
+func TestQueuedProbeWorker_dequeueOrder(t *testing.T) {
+	q := NewRequestQueue(1, rate.Inf, 1)
+	now := time.Now()
+
+	q.EnqueueCheck(HealthRequest{ID: "c", DueAt: now.Add(3 * time.Second)})
+	q.EnqueueCheck(HealthRequest{ID: "a", DueAt: now.Add(1 * time.Second)})
+	q.EnqueueCheck(HealthRequest{ID: "b", DueAt: now.Add(2 * time.Second)})
+	// a second request for a queued probe is coalesced, keeping the earliest
+	q.EnqueueCheck(HealthRequest{ID: "c", DueAt: now})
+	q.EnqueueCheck(HealthRequest{ID: "a", DueAt: now.Add(4 * time.Second)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var got []string
+	for i := 0; i < 3; i++ {
+		req, ok := q.dequeue(ctx)
+		if !ok {
+			t.Fatalf("expected request %d to be dequeued", i)
+		}
+		got = append(got, req.ID)
+	}
+
+	expected := []string{"c", "a", "b"}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected requests in order %v, got %v", expected, got)
+		}
+	}
+
+	if _, ok := q.dequeue(ctx); ok {
+		t.Errorf("expected queue to be empty")
+	}
+}
+
+func TestQueuedProbeWorker_concurrency(t *testing.T) {
+	const workers = 3
+	const requests = 12
+
+	var inFlight, maxInFlight int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	host, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse server address: %s", err)
+	}
+	port, _ := strconv.Atoi(portStr)
+
+	wg := sync.WaitGroup{}
+	wg.Add(requests)
+	notifier := notifierFunc(func(_ context.Context, _ ProbeResult) (NotificationResult, error) {
+		wg.Done()
+		return NotificationResult{}, nil
+	})
+
+	q := NewRequestQueue(workers, rate.Inf, 1)
+	for i := 0; i < requests; i++ {
+		q.EnqueueCheck(HealthRequest{
+			ID:       strconv.Itoa(i),
+			Host:     "test.example.com",
+			Address:  host,
+			Port:     port,
+			Protocol: v1alpha1.HttpProtocol,
+			Notifier: notifier,
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = q.Start(ctx)
+	}()
+
+	// let the workers pick up as many requests as they can
+	time.Sleep(200 * time.Millisecond)
+	if max := atomic.LoadInt32(&maxInFlight); max != workers {
+		t.Errorf("expected %d health checks in flight, got %d", workers, max)
+	}
+
+	for i := 0; i < requests; i++ {
+		release <- struct{}{}
+	}
+	wg.Wait()
+}
+
+func TestQueuedProbeWorker_timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	host, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse server address: %s", err)
+	}
+	port, _ := strconv.Atoi(portStr)
+
+	results := make(chan ProbeResult, 1)
+	q := NewRequestQueue(1, rate.Inf, 1)
+	q.EnqueueCheck(HealthRequest{
+		ID:       "slow",
+		Host:     "test.example.com",
+		Address:  host,
+		Port:     port,
+		Protocol: v1alpha1.HttpProtocol,
+		Timeout:  100 * time.Millisecond,
+		Notifier: notifierFunc(func(_ context.Context, result ProbeResult) (NotificationResult, error) {
+			results <- result
+			return NotificationResult{}, nil
+		}),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = q.Start(ctx)
+	}()
+
+	select {
+	case result := <-results:
+		if result.Healthy {
+			t.Errorf("expected timed out health check to be unhealthy")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("health check did not time out")
+	}
+}
+
+func TestQueuedProbeWorker_requeue(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	q := NewRequestQueue(1, rate.Inf, 1)
+	q.RequeueDelay = 200 * time.Millisecond
+
+	checks := make(chan string, 3)
+	requeued := false
+	q.EnqueueCheck(HealthRequest{
+		ID:       "requeued",
+		DueAt:    time.Now().Add(-time.Minute),
+		Address:  "127.0.0.1",
+		Port:     port,
+		Protocol: v1alpha1.TcpProtocol,
+		Notifier: notifierFunc(func(_ context.Context, _ ProbeResult) (NotificationResult, error) {
+			checks <- "requeued"
+			if !requeued {
+				requeued = true
+				// a request due meanwhile is checked before the requeued one
+				q.EnqueueCheck(HealthRequest{
+					ID:       "due",
+					Address:  "127.0.0.1",
+					Port:     port,
+					Protocol: v1alpha1.TcpProtocol,
+					Notifier: notifierFunc(func(_ context.Context, _ ProbeResult) (NotificationResult, error) {
+						checks <- "due"
+						return NotificationResult{}, nil
+					}),
+				})
+				return NotificationResult{Requeue: true}, nil
+			}
+			return NotificationResult{}, nil
+		}),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	started := time.Now()
+	go func() {
+		_ = q.Start(ctx)
+	}()
+
+	got := []string{}
+	for len(got) < 3 {
+		select {
+		case check := <-checks:
+			got = append(got, check)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected 3 checks, got %v", got)
+		}
+	}
+	if elapsed := time.Since(started); elapsed < q.RequeueDelay {
+		t.Errorf("expected the requeued request to be checked after %s, got %s", q.RequeueDelay, elapsed)
+	}
+	if expected := []string{"requeued", "due", "requeued"}; got[0] != expected[0] || got[1] != expected[1] || got[2] != expected[2] {
+		t.Errorf("expected checks %v, got %v", expected, got)
+	}
+}
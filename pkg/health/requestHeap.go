@@ -0,0 +1,36 @@
+package health
+
+// queuedRequest is a HealthRequest held in the requestHeap
+type queuedRequest struct {
+	HealthRequest
+	index int
+}
+
+// requestHeap implements heap.Interface ordering requests by when they are due
+type requestHeap []*queuedRequest
+
+func (h requestHeap) Len() int { return len(h) }
+
+func (h requestHeap) Less(i, j int) bool { return h[i].DueAt.Before(h[j].DueAt) }
+
+func (h requestHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *requestHeap) Push(x any) {
+	item := x.(*queuedRequest)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *requestHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*h = old[:n-1]
+	return item
+}
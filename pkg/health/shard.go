@@ -0,0 +1,328 @@
+package health
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// ShardGroupLabel labels the Leases of the replicas sharing the probes
+	ShardGroupLabel = "kuadrant.io/health-check-shard-group"
+
+	defaultShardLeaseDuration = 15 * time.Second
+	defaultShardRenewInterval = 5 * time.Second
+)
+
+// Shard splits the probes between the replicas of the controller. Each replica
+// holds a Lease labelled with the group and renews it every RenewInterval. The
+// replicas whose Lease hasn't expired are the members of the group, and each
+// probe is owned by a single member chosen by rendezvous hashing of the probe
+// ID, so that a member joining or leaving only moves the probes it gains or
+// loses.
+//
+// A member only claims a probe once its previous owner has stopped checking
+// it. The probes of a member that left are claimed once its Lease is deleted
+// or has expired, as a member that fails to renew its Lease stops checking
+// its probes by then. A member that joins waits for a LeaseDuration, by which
+// time the previous owners have listed the members again, before claiming the
+// probes of the members that joined earlier. Expired Leases are deleted
+type Shard struct {
+	// Client writes the Lease of the replica
+	Client client.Client
+	// Reader reads the Leases directly from the API server, rather than
+	// through a cache of every Lease in the cluster
+	Reader        client.Reader
+	Namespace     string
+	Group         string
+	Identity      string
+	LeaseDuration time.Duration
+	RenewInterval time.Duration
+
+	events    chan event.GenericEvent
+	members   []string
+	acquired  map[string]time.Time
+	settled   bool
+	renewedAt time.Time
+	mux       sync.RWMutex
+}
+
+func NewShard(c client.Client, reader client.Reader, namespace, group, identity string) *Shard {
+	return &Shard{
+		Client:        c,
+		Reader:        reader,
+		Namespace:     namespace,
+		Group:         group,
+		Identity:      identity,
+		LeaseDuration: defaultShardLeaseDuration,
+		RenewInterval: defaultShardRenewInterval,
+		events:        make(chan event.GenericEvent, 1),
+	}
+}
+
+var _ manager.Runnable = &Shard{}
+var _ manager.LeaderElectionRunnable = &Shard{}
+
+// Start keeps the Lease of the replica and the members of the group up to
+// date until the context is cancelled, then deletes the Lease so that the
+// other members take over its probes straight away
+func (s *Shard) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithValues("shard", s.Identity, "group", s.Group)
+	logger.V(3).Info("Starting health check shard")
+
+	ticker := time.NewTicker(s.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.sync(ctx); err != nil {
+			logger.Error(err, "failed to sync health check shard")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			logger.Info("Stopping health check shard")
+			s.setMembers(nil, nil, false)
+
+			releaseCtx, cancel := context.WithTimeout(context.Background(), s.RenewInterval)
+			defer cancel()
+			if err := s.Client.Delete(releaseCtx, s.lease()); client.IgnoreNotFound(err) != nil {
+				logger.Error(err, "failed to release health check shard lease")
+			}
+			return nil
+		}
+	}
+}
+
+// NeedLeaderElection runs the shard on every replica
+func (s *Shard) NeedLeaderElection() bool {
+	return false
+}
+
+// Owns returns whether the probe is checked by this replica
+func (s *Shard) Owns(id string) bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	if rendezvousOwner(s.members, id) != s.Identity {
+		return false
+	}
+	if s.settled {
+		return true
+	}
+
+	// the member is joining, and waits for the probes of the members that
+	// joined earlier to be released
+	earlier := []string{}
+	for _, member := range s.members {
+		if s.joinedBefore(member, s.Identity) {
+			earlier = append(earlier, member)
+		}
+	}
+	return rendezvousOwner(earlier, id) == ""
+}
+
+// joinedBefore returns whether member a acquired its Lease before member b
+func (s *Shard) joinedBefore(a, b string) bool {
+	if s.acquired[a].Equal(s.acquired[b]) {
+		return a < b
+	}
+	return s.acquired[a].Before(s.acquired[b])
+}
+
+// Members returns the replicas in the group, sorted by identity
+func (s *Shard) Members() []string {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	return slices.Clone(s.members)
+}
+
+// Events receives an event every time the members of the group change, so
+// that the probes can be reassigned
+func (s *Shard) Events() <-chan event.GenericEvent {
+	return s.events
+}
+
+// sync renews the Lease of the replica and updates the members from the
+// Leases of the group that haven't expired, deleting the expired ones. Each
+// sync is bounded by syncTimeout. A replica that fails to renew its Lease by
+// the deadline leaves the group when the Lease would expire before the next
+// sync could renew it, even if a call is still blocked, as the other members
+// take over its probes once the Lease has expired
+func (s *Shard) sync(ctx context.Context) error {
+	now := time.Now()
+	deadline := now.Add(s.syncTimeout())
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	lapses := !s.renewedAt.Add(s.LeaseDuration).After(deadline.Add(s.RenewInterval))
+	// renewal is settled by either the renew or the deadline, whichever comes first
+	var renewal atomic.Int32
+	const (
+		pending int32 = iota
+		renewed
+		timedOut
+	)
+	timer := time.AfterFunc(time.Until(deadline), func() {
+		if renewal.CompareAndSwap(pending, timedOut) && lapses {
+			s.setMembers(nil, nil, false)
+		}
+	})
+	defer timer.Stop()
+
+	acquiredAt, err := s.renew(ctx, now)
+	if err == nil && !renewal.CompareAndSwap(pending, renewed) {
+		err = fmt.Errorf("failed to renew health check shard lease by %s", deadline)
+	}
+	if err != nil {
+		if lapses {
+			s.setMembers(nil, nil, false)
+		}
+		return err
+	}
+	s.renewedAt = now
+
+	leases := &coordinationv1.LeaseList{}
+	if err := s.Reader.List(ctx, leases, client.InNamespace(s.Namespace), client.MatchingLabels{ShardGroupLabel: s.Group}); err != nil {
+		return fmt.Errorf("failed to list health check shard leases: %w", err)
+	}
+
+	members := []string{s.Identity}
+	acquired := map[string]time.Time{s.Identity: acquiredAt}
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == s.Identity {
+			continue
+		}
+		if leaseExpired(*lease, now) {
+			// the precondition keeps a Lease renewed in the meantime
+			err := s.Client.Delete(ctx, lease, client.Preconditions{ResourceVersion: &lease.ResourceVersion})
+			if client.IgnoreNotFound(err) != nil && !k8serrors.IsConflict(err) {
+				log.FromContext(ctx).Error(err, "failed to delete expired health check shard lease", "lease", lease.Name)
+			}
+			continue
+		}
+		members = append(members, *lease.Spec.HolderIdentity)
+		if lease.Spec.AcquireTime != nil {
+			acquired[*lease.Spec.HolderIdentity] = lease.Spec.AcquireTime.Time
+		}
+	}
+	slices.Sort(members)
+
+	s.setMembers(members, acquired, now.Sub(acquiredAt) >= s.LeaseDuration)
+	return nil
+}
+
+// syncTimeout bounds a sync well within LeaseDuration - RenewInterval, so
+// that a sync started on time completes before the Lease renewed by the
+// previous sync expires, and a failed renewal can be retried once
+func (s *Shard) syncTimeout() time.Duration {
+	return (s.LeaseDuration - s.RenewInterval) / 4
+}
+
+// renew creates the Lease of the replica or updates its renew time, returning
+// when the Lease was acquired. An expired Lease is acquired again
+func (s *Shard) renew(ctx context.Context, at time.Time) (time.Time, error) {
+	now := metav1.NewMicroTime(at)
+	lease := s.lease()
+
+	if err := s.Reader.Get(ctx, client.ObjectKeyFromObject(lease), lease); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return time.Time{}, fmt.Errorf("failed to get health check shard lease: %w", err)
+		}
+
+		duration := int32(s.LeaseDuration.Seconds())
+		lease.Labels = map[string]string{ShardGroupLabel: s.Group}
+		lease.Spec = coordinationv1.LeaseSpec{
+			HolderIdentity:       &s.Identity,
+			LeaseDurationSeconds: &duration,
+			AcquireTime:          &now,
+			RenewTime:            &now,
+		}
+		if err := s.Client.Create(ctx, lease); err != nil {
+			return time.Time{}, fmt.Errorf("failed to create health check shard lease: %w", err)
+		}
+		return at, nil
+	}
+
+	if leaseExpired(*lease, at) || lease.Spec.AcquireTime == nil {
+		lease.Spec.AcquireTime = &now
+	}
+	lease.Spec.RenewTime = &now
+	if err := s.Client.Update(ctx, lease); err != nil {
+		return time.Time{}, fmt.Errorf("failed to renew health check shard lease: %w", err)
+	}
+	return lease.Spec.AcquireTime.Time, nil
+}
+
+func (s *Shard) lease() *coordinationv1.Lease {
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", s.Group, s.Identity),
+			Namespace: s.Namespace,
+		},
+	}
+}
+
+// setMembers records the members of the group, when they acquired their
+// Lease and whether the replica has finished joining, notifying Events when
+// the probes it owns may have changed. Pending notifications are coalesced
+func (s *Shard) setMembers(members []string, acquired map[string]time.Time, settled bool) {
+	s.mux.Lock()
+	changed := !slices.Equal(s.members, members) || s.settled != settled
+	s.members = members
+	s.acquired = acquired
+	s.settled = settled
+	s.mux.Unlock()
+
+	if !changed {
+		return
+	}
+
+	healthCheckShardMembers.WithLabelValues(s.Identity).Set(float64(len(members)))
+	healthCheckShardRebalances.WithLabelValues(s.Identity).Inc()
+
+	select {
+	case s.events <- event.GenericEvent{Object: s.lease()}:
+	default:
+	}
+}
+
+func leaseExpired(lease coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return now.After(expiry)
+}
+
+// rendezvousOwner returns the member with the highest score for the probe
+func rendezvousOwner(members []string, id string) string {
+	var owner string
+	var highest uint64
+	for _, member := range members {
+		if score := rendezvousScore(member, id); owner == "" || score > highest {
+			owner, highest = member, score
+		}
+	}
+	return owner
+}
+
+func rendezvousScore(member, id string) uint64 {
+	sum := sha256.Sum256([]byte(member + "/" + id))
+	return binary.BigEndian.Uint64(sum[:8])
+}
@@ -0,0 +1,192 @@
+//go:build unit
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestShard(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+
+	shardA := NewShard(c, c, "test", "probes", "replica-a")
+	shardB := NewShard(c, c, "test", "probes", "replica-b")
+
+	for _, shard := range []*Shard{shardA, shardB, shardA} {
+		if err := shard.sync(ctx); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+	for _, shard := range []*Shard{shardA, shardB} {
+		if members := shard.Members(); !slices.Equal(members, []string{"replica-a", "replica-b"}) {
+			t.Fatalf("expected both replicas to be members, got %v", members)
+		}
+	}
+	select {
+	case <-shardA.Events():
+	default:
+		t.Errorf("expected an event when members changed")
+	}
+
+	// replica-b waits for replica-a to release the probes it takes over
+	for i := 0; i < 100; i++ {
+		if id := fmt.Sprintf("test/probe-%d", i); shardB.Owns(id) {
+			t.Fatalf("expected joining replica-b not to claim probe %s", id)
+		}
+	}
+
+	// replica-b has been a member for a lease duration
+	lease := shardB.lease()
+	if err := c.Get(ctx, client.ObjectKeyFromObject(lease), lease); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	joined := metav1.NewMicroTime(time.Now().Add(-time.Minute))
+	lease.Spec.AcquireTime = &joined
+	if err := c.Update(ctx, lease); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := shardB.sync(ctx); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	select {
+	case <-shardB.Events():
+	default:
+		t.Errorf("expected an event when replica-b finished joining")
+	}
+
+	ownedByA := []string{}
+	for i := 0; i < 100; i++ {
+		id := fmt.Sprintf("test/probe-%d", i)
+		if shardA.Owns(id) == shardB.Owns(id) {
+			t.Fatalf("expected probe %s to be owned by exactly one replica", id)
+		}
+		if shardA.Owns(id) {
+			ownedByA = append(ownedByA, id)
+		}
+	}
+	if len(ownedByA) == 0 || len(ownedByA) == 100 {
+		t.Errorf("expected the probes to be split between the replicas, replica-a owns %d", len(ownedByA))
+	}
+
+	// replica-b stops renewing its lease
+	lease = shardB.lease()
+	if err := c.Get(ctx, client.ObjectKeyFromObject(lease), lease); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	expired := metav1.NewMicroTime(time.Now().Add(-time.Minute))
+	lease.Spec.RenewTime = &expired
+	if err := c.Update(ctx, lease); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	if err := shardA.sync(ctx); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if members := shardA.Members(); !slices.Equal(members, []string{"replica-a"}) {
+		t.Fatalf("expected replica-b to leave, got %v", members)
+	}
+	for i := 0; i < 100; i++ {
+		if id := fmt.Sprintf("test/probe-%d", i); !shardA.Owns(id) {
+			t.Errorf("expected replica-a to take over probe %s", id)
+		}
+	}
+
+	leases := &coordinationv1.LeaseList{}
+	if err := c.List(ctx, leases, client.MatchingLabels{ShardGroupLabel: "probes"}); err != nil || len(leases.Items) != 1 {
+		t.Errorf("expected the expired lease to be deleted, got %v (%v)", leases.Items, err)
+	}
+}
+
+func TestShard_stop(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	shard := NewShard(c, c, "test", "probes", "replica-a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- shard.Start(ctx)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(shard.Members()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !shard.Owns("test/probe") {
+		t.Fatalf("expected the only replica to own every probe")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if shard.Owns("test/probe") {
+		t.Errorf("expected a stopped replica to own no probe")
+	}
+	leases := &coordinationv1.LeaseList{}
+	if err := c.List(context.Background(), leases); err != nil || len(leases.Items) != 0 {
+		t.Errorf("expected the lease to be released, got %v (%v)", leases.Items, err)
+	}
+}
+
+func TestShard_syncDeadline(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+	shard := NewShard(c, c, "test", "probes", "replica-a")
+	shard.LeaseDuration = 2 * time.Second
+	shard.RenewInterval = 400 * time.Millisecond
+
+	if err := shard.sync(ctx); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if !shard.Owns("test/probe") {
+		t.Fatalf("expected the only replica to own every probe")
+	}
+
+	// the API server stops responding, and the lease renewed by the last
+	// sync expires before the next sync could renew it
+	release := make(chan struct{})
+	shard.Reader = interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			<-release
+			return ctx.Err()
+		},
+	})
+	shard.renewedAt = time.Now().Add(-shard.LeaseDuration + shard.RenewInterval)
+
+	done := make(chan error)
+	go func() {
+		done <- shard.sync(ctx)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(shard.Members()) != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case <-done:
+		t.Fatalf("expected the sync to still be blocked")
+	default:
+	}
+	if shard.Owns("test/probe") {
+		t.Errorf("expected the replica to leave the group once the sync deadline passed")
+	}
+
+	close(release)
+	if err := <-done; err == nil {
+		t.Errorf("expected an error from a sync that missed its deadline")
+	}
+	if shard.Owns("test/probe") {
+		t.Errorf("expected the replica to stay out of the group")
+	}
+}
@@ -0,0 +1,308 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// WebhookSignatureHeader holds the HMAC-SHA256 signature of the payload,
+	// hex encoded and prefixed with "sha256=", when the webhook has a key
+	WebhookSignatureHeader = "X-Kuadrant-Signature"
+
+	HealthyState   = "Healthy"
+	UnhealthyState = "Unhealthy"
+	UnknownState   = "Unknown"
+
+	defaultWebhookTimeout   = 10 * time.Second
+	defaultWebhookRetries   = 4
+	defaultWebhookBackoff   = time.Second
+	defaultWebhookQueueSize = 100
+	defaultWebhookIdleTime  = 5 * time.Minute
+)
+
+// Webhook is an HTTP endpoint notified when a probe becomes healthy or
+// unhealthy. When Key is set the payload is signed with it
+type Webhook struct {
+	URL string
+	Key []byte
+}
+
+// WebhookPayload is the JSON body posted to the webhooks
+type WebhookPayload struct {
+	Probe     string    `json:"probe"`
+	Gateway   string    `json:"gateway,omitempty"`
+	Listener  string    `json:"listener,omitempty"`
+	Address   string    `json:"address"`
+	OldState  string    `json:"oldState"`
+	NewState  string    `json:"newState"`
+	Reason    string    `json:"reason,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WebhookSender posts payloads to webhooks, retrying failed requests Retries
+// times. The wait between attempts starts at Backoff and doubles every retry.
+//
+// Payloads are queued per webhook URL and posted in order by a single worker
+// for each URL, started with the sender by the manager. A queue holds up to
+// QueueSize payloads, further payloads are dropped until it drains. The queue
+// and worker of a URL are removed once no payload was queued for IdleTime, so
+// that URLs no longer used by any probe don't hold on to them
+type WebhookSender struct {
+	Client    *http.Client
+	Retries   int
+	Backoff   time.Duration
+	QueueSize int
+	IdleTime  time.Duration
+	// Sharded runs the sender on every replica rather than on the leader only,
+	// as every replica checks probes
+	Sharded bool
+
+	mux    sync.Mutex
+	ctx    context.Context
+	queues map[string]chan webhookDelivery
+}
+
+// webhookDelivery is a payload queued for a webhook
+type webhookDelivery struct {
+	webhook Webhook
+	payload WebhookPayload
+	logger  logr.Logger
+}
+
+func NewWebhookSender() *WebhookSender {
+	return &WebhookSender{
+		Client:    &http.Client{Timeout: defaultWebhookTimeout},
+		Retries:   defaultWebhookRetries,
+		Backoff:   defaultWebhookBackoff,
+		QueueSize: defaultWebhookQueueSize,
+		IdleTime:  defaultWebhookIdleTime,
+	}
+}
+
+// Start runs a worker for every webhook queue until ctx is done. Payloads
+// queued before the sender starts are posted once it does
+func (s *WebhookSender) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+	logger.V(3).Info("Starting health check webhook sender")
+
+	s.mux.Lock()
+	s.ctx = ctx
+	for url, queue := range s.queues {
+		go s.work(ctx, url, queue)
+	}
+	s.mux.Unlock()
+
+	<-ctx.Done()
+	logger.Info("Stopping health check webhook sender")
+	return nil
+}
+
+// NeedLeaderElection makes the manager start the sender only once elected,
+// unless the probes are sharded between the replicas
+func (s *WebhookSender) NeedLeaderElection() bool {
+	return !s.Sharded
+}
+
+// Enqueue queues the payload for the webhook without waiting for it to be
+// posted. It returns false when the queue of the webhook is full
+func (s *WebhookSender) Enqueue(ctx context.Context, webhook Webhook, payload WebhookPayload) bool {
+	// the payload is queued while holding the lock, so that the queue isn't
+	// removed by its worker in the meantime
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.queues == nil {
+		s.queues = map[string]chan webhookDelivery{}
+	}
+	queue, ok := s.queues[webhook.URL]
+	if !ok {
+		size := s.QueueSize
+		if size <= 0 {
+			size = defaultWebhookQueueSize
+		}
+		queue = make(chan webhookDelivery, size)
+		s.queues[webhook.URL] = queue
+		if s.ctx != nil {
+			go s.work(s.ctx, webhook.URL, queue)
+		}
+	}
+
+	select {
+	case queue <- webhookDelivery{webhook: webhook, payload: payload, logger: log.FromContext(ctx)}:
+		return true
+	default:
+		return false
+	}
+}
+
+// work posts the payloads of a queue in order until ctx is done, or until the
+// queue has been idle for IdleTime and is removed
+func (s *WebhookSender) work(ctx context.Context, url string, queue chan webhookDelivery) {
+	idleTime := s.IdleTime
+	if idleTime <= 0 {
+		idleTime = defaultWebhookIdleTime
+	}
+	idle := time.NewTimer(idleTime)
+	defer idle.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case delivery := <-queue:
+			if err := s.Send(ctx, delivery.webhook, delivery.payload); err != nil {
+				delivery.logger.Error(err, "failed to notify webhook", "url", delivery.webhook.URL, "probe", delivery.payload.Probe)
+			}
+			if !idle.Stop() {
+				select {
+				case <-idle.C:
+				default:
+				}
+			}
+			idle.Reset(idleTime)
+		case <-idle.C:
+			if s.removeIdle(url, queue) {
+				return
+			}
+			idle.Reset(idleTime)
+		}
+	}
+}
+
+// removeIdle removes the queue of the URL unless a payload was queued since
+// it was last drained
+func (s *WebhookSender) removeIdle(url string, queue chan webhookDelivery) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if len(queue) > 0 || s.queues[url] != queue {
+		return false
+	}
+	delete(s.queues, url)
+	return true
+}
+
+// Send posts the payload to the webhook. Network errors, 429 and 5xx responses
+// are retried, any other response is final
+func (s *WebhookSender) Send(ctx context.Context, webhook Webhook, payload WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	backoff := s.Backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(ctx, webhook, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= s.Retries {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// post sends a single request, returning whether a failure can be retried
+func (s *WebhookSender) post(ctx context.Context, webhook Webhook, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(webhook.Key) > 0 {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+Sign(webhook.Key, body))
+	}
+
+	res, err := s.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return false, nil
+	}
+	retry := res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+	return retry, fmt.Errorf("webhook %s responded with status %d", webhook.URL, res.StatusCode)
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of body with key
+func Sign(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookProbeNotifier wraps a notifier by posting a payload to the webhooks
+// every time the wrapped notifier reports a transition. The payloads are
+// queued in the sender so that slow endpoints don't hold the health checks
+type WebhookProbeNotifier struct {
+	sender   *WebhookSender
+	webhooks []Webhook
+	payload  WebhookPayload
+	notifier ProbeNotifier
+}
+
+var _ ProbeNotifier = &WebhookProbeNotifier{}
+
+// NewWebhookProbeNotifier creates a notifier that posts payload to webhooks,
+// with the state and reason set from the transition
+func NewWebhookProbeNotifier(sender *WebhookSender, webhooks []Webhook, payload WebhookPayload, notifier ProbeNotifier) *WebhookProbeNotifier {
+	return &WebhookProbeNotifier{
+		sender:   sender,
+		webhooks: webhooks,
+		payload:  payload,
+		notifier: notifier,
+	}
+}
+
+func (n *WebhookProbeNotifier) Notify(ctx context.Context, result ProbeResult) (NotificationResult, error) {
+	notificationResult, err := n.notifier.Notify(ctx, result)
+	if err != nil || notificationResult.Transition == nil {
+		return notificationResult, err
+	}
+
+	logger := log.FromContext(ctx)
+	payload := n.payload
+	payload.OldState = stateOf(notificationResult.Transition.WasHealthy)
+	payload.NewState = stateOf(&notificationResult.Transition.Healthy)
+	payload.Reason = notificationResult.Transition.Reason
+	payload.Timestamp = result.CheckedAt
+
+	for _, webhook := range n.webhooks {
+		if !n.sender.Enqueue(ctx, webhook, payload) {
+			logger.Error(fmt.Errorf("webhook queue is full"), "dropped webhook notification", "url", webhook.URL, "probe", payload.Probe)
+		}
+	}
+
+	return notificationResult, nil
+}
+
+func stateOf(healthy *bool) string {
+	if healthy == nil {
+		return UnknownState
+	}
+	if *healthy {
+		return HealthyState
+	}
+	return UnhealthyState
+}
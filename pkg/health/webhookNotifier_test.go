@@ -0,0 +1,247 @@
+//go:build unit
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookSender(t *testing.T) {
+	testCases := []struct {
+		name         string
+		responses    []int
+		key          []byte
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{
+			name:         "posts payload",
+			responses:    []int{http.StatusOK},
+			wantAttempts: 1,
+		},
+		{
+			name:         "signs payload",
+			responses:    []int{http.StatusNoContent},
+			key:          []byte("secret"),
+			wantAttempts: 1,
+		},
+		{
+			name:         "retries server errors",
+			responses:    []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			wantAttempts: 3,
+		},
+		{
+			name:         "gives up after retries",
+			responses:    []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "does not retry client errors",
+			responses:    []int{http.StatusBadRequest, http.StatusOK},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := attempts.Add(1)
+
+				body, _ := io.ReadAll(r.Body)
+				payload := WebhookPayload{}
+				if err := json.Unmarshal(body, &payload); err != nil || payload.Probe != "test/probe" || payload.NewState != UnhealthyState {
+					t.Errorf("unexpected payload %s", body)
+				}
+
+				signature := r.Header.Get(WebhookSignatureHeader)
+				if testCase.key == nil && signature != "" {
+					t.Errorf("expected no signature, got %s", signature)
+				}
+				if testCase.key != nil && signature != "sha256="+Sign(testCase.key, body) {
+					t.Errorf("unexpected signature %s", signature)
+				}
+
+				w.WriteHeader(testCase.responses[attempt-1])
+			}))
+			defer server.Close()
+
+			sender := &WebhookSender{Client: server.Client(), Retries: 2, Backoff: time.Millisecond}
+			err := sender.Send(context.Background(), Webhook{URL: server.URL, Key: testCase.key}, WebhookPayload{
+				Probe:    "test/probe",
+				OldState: HealthyState,
+				NewState: UnhealthyState,
+			})
+			if (err != nil) != testCase.wantErr {
+				t.Errorf("expected error %v, got %v", testCase.wantErr, err)
+			}
+			if attempts.Load() != testCase.wantAttempts {
+				t.Errorf("expected %d attempts, got %d", testCase.wantAttempts, attempts.Load())
+			}
+		})
+	}
+}
+
+func TestWebhookProbeNotifier(t *testing.T) {
+	payloads := make(chan WebhookPayload, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := WebhookPayload{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("unexpected error %s", err)
+		}
+		payloads <- payload
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sender := &WebhookSender{Client: server.Client(), Backoff: time.Millisecond}
+	go func() {
+		_ = sender.Start(ctx)
+	}()
+
+	var transition *Transition
+	notifier := NewWebhookProbeNotifier(
+		sender,
+		[]Webhook{{URL: server.URL}},
+		WebhookPayload{Probe: "test/probe", Gateway: "test/gateway", Listener: "api", Address: "172.0.0.1"},
+		notifierFunc(func(_ context.Context, _ ProbeResult) (NotificationResult, error) {
+			return NotificationResult{Transition: transition}, nil
+		}),
+	)
+
+	checkedAt := time.Now()
+	if _, err := notifier.Notify(context.Background(), ProbeResult{CheckedAt: checkedAt}); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	wasHealthy := true
+	transition = &Transition{WasHealthy: &wasHealthy, Healthy: false, Reason: "connection refused"}
+	if _, err := notifier.Notify(context.Background(), ProbeResult{CheckedAt: checkedAt}); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	select {
+	case payload := <-payloads:
+		expected := WebhookPayload{
+			Probe:     "test/probe",
+			Gateway:   "test/gateway",
+			Listener:  "api",
+			Address:   "172.0.0.1",
+			OldState:  HealthyState,
+			NewState:  UnhealthyState,
+			Reason:    "connection refused",
+			Timestamp: payload.Timestamp,
+		}
+		if payload != expected || !payload.Timestamp.Equal(checkedAt) {
+			t.Errorf("expected payload %+v, got %+v", expected, payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for the webhook")
+	}
+
+	select {
+	case payload := <-payloads:
+		t.Errorf("expected a single notification, got %+v", payload)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWebhookSenderQueue(t *testing.T) {
+	probes := make(chan string, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := WebhookPayload{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("unexpected error %s", err)
+		}
+		probes <- payload.Probe
+	}))
+	defer server.Close()
+
+	sender := &WebhookSender{Client: server.Client(), Backoff: time.Millisecond, QueueSize: 3}
+	webhook := Webhook{URL: server.URL}
+	for _, probe := range []string{"test/a", "test/b", "test/c"} {
+		if !sender.Enqueue(context.Background(), webhook, WebhookPayload{Probe: probe}) {
+			t.Fatalf("expected %s to be queued", probe)
+		}
+	}
+	if sender.Enqueue(context.Background(), webhook, WebhookPayload{Probe: "test/d"}) {
+		t.Errorf("expected payload to be dropped when the queue is full")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = sender.Start(ctx)
+	}()
+
+	for _, expected := range []string{"test/a", "test/b", "test/c"} {
+		select {
+		case probe := <-probes:
+			if probe != expected {
+				t.Errorf("expected %s to be posted, got %s", expected, probe)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s", expected)
+		}
+	}
+}
+
+func TestWebhookSenderIdle(t *testing.T) {
+	probes := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := WebhookPayload{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("unexpected error %s", err)
+		}
+		probes <- payload.Probe
+	}))
+	defer server.Close()
+
+	sender := &WebhookSender{Client: server.Client(), Backoff: time.Millisecond, IdleTime: 50 * time.Millisecond}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = sender.Start(ctx)
+	}()
+
+	queues := func() int {
+		sender.mux.Lock()
+		defer sender.mux.Unlock()
+		return len(sender.queues)
+	}
+
+	webhook := Webhook{URL: server.URL}
+	for _, probe := range []string{"test/a", "test/b"} {
+		if !sender.Enqueue(context.Background(), webhook, WebhookPayload{Probe: probe}) {
+			t.Fatalf("expected %s to be queued", probe)
+		}
+		select {
+		case posted := <-probes:
+			if posted != probe {
+				t.Errorf("expected %s to be posted, got %s", probe, posted)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s", probe)
+		}
+
+		// the queue of the webhook is removed once idle, and created again
+		// for the next payload
+		deadline := time.Now().Add(5 * time.Second)
+		for queues() != 0 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if n := queues(); n != 0 {
+			t.Fatalf("expected the idle webhook queue to be removed, got %d queues", n)
+		}
+	}
+}
@@ -0,0 +1,678 @@
+package placement
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/clusterSecret"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/gracePeriod"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/metadata"
+)
+
+const (
+	// ClusterLabelSelectorAnnotation selects the clusters a gateway is placed
+	// on by the labels of their ManagedClusters, or of their cluster secrets
+	// without OCM
+	ClusterLabelSelectorAnnotation = "kuadrant.io/gateway-cluster-label-selector"
+	// PlacementHashAnnotation records the hash of an object applied to a
+	// cluster, so that it's only updated when it changes
+	PlacementHashAnnotation = "kuadrant.io/placement-hash"
+	// GatewayClustersAnnotation records the clusters a gateway is placed on,
+	// as a JSON list, so that they are cleaned up while they can't be reached
+	GatewayClustersAnnotation = "kuadrant.io/gateway-clusters"
+	// PlacementOwnerLabelPrefix prefixes the labels, named by the key of each
+	// upstream gateway, of the objects placed on a cluster for them, as
+	// objects such as TLS secrets can be shared by several gateways
+	PlacementOwnerLabelPrefix = "placement.kuadrant.io/"
+
+	defaultStatusSyncPeriod = 30 * time.Second
+)
+
+// placedKinds are the kinds of objects placed on the clusters, which are
+// removed from a cluster when they are no longer placed there
+var placedKinds = []schema.GroupVersionKind{
+	gatewayapiv1.SchemeGroupVersion.WithKind("Gateway"),
+	corev1.SchemeGroupVersion.WithKind("Secret"),
+}
+
+// ClientFunc creates a client for the cluster of a cluster secret
+type ClientFunc func(secret *corev1.Secret) (client.Client, error)
+
+type clusterClient struct {
+	resourceVersion string
+	client          client.Client
+}
+
+// clusterSecretPlacer places gateways directly onto the clusters described by
+// Argo CD style cluster secrets, without Open Cluster Management
+type clusterSecretPlacer struct {
+	c                client.Client
+	namespace        string
+	clientFor        ClientFunc
+	statusSyncPeriod time.Duration
+
+	clients map[types.NamespacedName]clusterClient
+	mux     sync.Mutex
+}
+
+// NewClusterSecretPlacer creates a placer for the cluster secrets in the
+// namespace, or in the namespace of each gateway when empty. The status of the
+// downstream gateways is read back every statusSyncPeriod
+func NewClusterSecretPlacer(c client.Client, namespace string, statusSyncPeriod time.Duration) *clusterSecretPlacer {
+	placer := NewClusterSecretPlacerWithClients(c, namespace, clusterSecret.ClientFromSecret)
+	placer.statusSyncPeriod = statusSyncPeriod
+	return placer
+}
+
+// NewClusterSecretPlacerWithClients creates a placer that uses clientFor to
+// create the clients of the clusters
+func NewClusterSecretPlacerWithClients(c client.Client, namespace string, clientFor ClientFunc) *clusterSecretPlacer {
+	return &clusterSecretPlacer{
+		c:                c,
+		namespace:        namespace,
+		clientFor:        clientFor,
+		statusSyncPeriod: defaultStatusSyncPeriod,
+		clients:          map[types.NamespacedName]clusterClient{},
+	}
+}
+
+// StatusSyncPeriod returns how often the gateways are reconciled to read back
+// the status of their downstream gateways, which the placer can't watch
+func (sp *clusterSecretPlacer) StatusSyncPeriod() time.Duration {
+	return sp.statusSyncPeriod
+}
+
+// Place applies the downstream gateway and its children to the selected
+// clusters, and removes them from the clusters that are no longer selected.
+// Namespaces among the children are created when missing, but never updated
+// or removed, as they may be shared by other gateways.
+//
+// A cluster that fails doesn't stop the others from being placed, the errors
+// of every cluster are returned together. Clusters recorded in the
+// GatewayClustersAnnotation of the gateway that can't be reached are kept in
+// the returned clusters until they are cleaned up
+func (sp *clusterSecretPlacer) Place(ctx context.Context, upStreamGateway *gatewayapiv1.Gateway, downStreamGateway *gatewayapiv1.Gateway, overrides Overrides, clusterChildren ClusterChildren, children ...metav1.Object) (sets.Set[string], error) {
+	log := log.Log
+	log.V(3).Info("placement: placing ", "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
+	key := WorkName(upStreamGateway)
+
+	secrets, err := sp.getClusterSecrets(ctx, upStreamGateway)
+	if err != nil {
+		return sets.New[string](), err
+	}
+	placementTargets, err := sp.GetClusters(ctx, upStreamGateway)
+	if err != nil {
+		return sets.New[string](), err
+	}
+	existingClusters, err := sp.GetPlacedClusters(ctx, upStreamGateway)
+	if err != nil {
+		return sets.New[string](), err
+	}
+	recorded, err := recordedClusters(upStreamGateway)
+	if err != nil {
+		return existingClusters, err
+	}
+	// clusters whose secret was removed are no longer managed, and can't be
+	// cleaned up
+	for _, cluster := range recorded.UnsortedList() {
+		if _, ok := secrets[cluster]; ok {
+			existingClusters.Insert(cluster)
+		}
+	}
+	log.V(3).Info("placement: ", "targets", placementTargets.UnsortedList(), "existing", existingClusters.UnsortedList(), "gateway", upStreamGateway.Name)
+
+	var errs []error
+	if upStreamGateway.GetDeletionTimestamp() != nil {
+		for _, cluster := range existingClusters.UnsortedList() {
+			c, err := sp.getClient(secrets[cluster])
+			if err == nil {
+				err = prune(ctx, c, key, sets.New[string]())
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to remove gateway from cluster %s: %w", cluster, err))
+				continue
+			}
+			existingClusters.Delete(cluster)
+		}
+		return existingClusters, errors.Join(errs...)
+	}
+
+	for _, cluster := range placementTargets.UnsortedList() {
+		log.V(3).Info("placement: ", "adding gateway to cluster ", cluster, "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
+		if err := sp.placeOn(ctx, secrets[cluster], cluster, key, downStreamGateway, overrides, clusterChildren, children); err != nil {
+			log.V(3).Info("placement: ", "adding gateway to cluster ", cluster, "gateway", upStreamGateway.Name, "error", err)
+			errs = append(errs, fmt.Errorf("failed to place gateway on cluster %s: %w", cluster, err))
+			continue
+		}
+		existingClusters.Insert(cluster)
+	}
+
+	removeFrom := existingClusters.Difference(placementTargets)
+	for _, cluster := range removeFrom.UnsortedList() {
+		log.V(3).Info("placement: ", "removing gateway from cluster ", cluster, "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
+		if err := sp.removeFrom(ctx, secrets[cluster], key); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove gateway from cluster %s: %w", cluster, err))
+			continue
+		}
+		existingClusters.Delete(cluster)
+	}
+
+	return existingClusters, errors.Join(errs...)
+}
+
+// placeOn applies the gateway, with the overrides of the cluster, and its
+// children, common and specific to the cluster, to the cluster, and removes
+// the children that are no longer placed
+func (sp *clusterSecretPlacer) placeOn(ctx context.Context, secret *corev1.Secret, cluster, key string, downStreamGateway *gatewayapiv1.Gateway, overrides Overrides, clusterChildren ClusterChildren, children []metav1.Object) error {
+	c, err := sp.getClient(secret)
+	if err != nil {
+		return err
+	}
+	gateway, err := overrides.apply(ctx, cluster, downStreamGateway)
+	if err != nil {
+		return err
+	}
+	gatewayChildren, err := clusterChildren.apply(ctx, cluster, gateway)
+	if err != nil {
+		return err
+	}
+	objects := append([]metav1.Object{gateway}, children...)
+	objects = append(objects, gatewayChildren...)
+	placed := sets.New[string]()
+	for _, obj := range namespacesFirst(objects) {
+		o, ok := obj.(client.Object)
+		if !ok {
+			return fmt.Errorf("unable to place %T on cluster %s", obj, cluster)
+		}
+		if ns, ok := o.(*corev1.Namespace); ok {
+			if err := c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns.Name}}); err != nil && !k8serrors.IsAlreadyExists(err) {
+				return err
+			}
+			continue
+		}
+		if err := apply(ctx, c, key, o); err != nil {
+			return err
+		}
+		placed.Insert(objectID(c, o))
+	}
+	// remove the children that are no longer placed, such as TLS secrets of removed listeners
+	return prune(ctx, c, key, placed)
+}
+
+// removeFrom gracefully deletes the gateway from the cluster, then its
+// children
+func (sp *clusterSecretPlacer) removeFrom(ctx context.Context, secret *corev1.Secret, key string) error {
+	c, err := sp.getClient(secret)
+	if err != nil {
+		return err
+	}
+	gateways, err := listPlaced(ctx, c, key, gatewayapiv1.SchemeGroupVersion.WithKind("Gateway"))
+	if err != nil {
+		return err
+	}
+	for i := range gateways {
+		if err := gracePeriod.GracefulDelete(ctx, c, &gateways[i], false); client.IgnoreNotFound(err) != nil {
+			log.Log.V(3).Info("error during graceful delete", "error", err)
+			return err
+		}
+	}
+	log.Log.V(3).Info("graceful delete of gateway complete, deleting children")
+	return prune(ctx, c, key, sets.New[string]())
+}
+
+// recordedClusters returns the clusters recorded in the
+// GatewayClustersAnnotation of the gateway
+func recordedClusters(gateway *gatewayapiv1.Gateway) (sets.Set[string], error) {
+	clusters := []string{}
+	if value := metadata.GetAnnotation(gateway, GatewayClustersAnnotation); value != "" {
+		if err := json.Unmarshal([]byte(value), &clusters); err != nil {
+			return sets.New[string](), fmt.Errorf("invalid %s annotation: %w", GatewayClustersAnnotation, err)
+		}
+	}
+	return sets.New(clusters...), nil
+}
+
+// GetPlacedClusters returns the clusters the downstream gateway exists on.
+// Clusters that can't be reached are not considered placed
+func (sp *clusterSecretPlacer) GetPlacedClusters(ctx context.Context, gateway *gatewayapiv1.Gateway) (sets.Set[string], error) {
+	placed := sets.New[string]()
+	secrets, err := sp.getClusterSecrets(ctx, gateway)
+	if err != nil {
+		return placed, err
+	}
+	for cluster, secret := range secrets {
+		downstream, err := sp.getDownstreamGateway(ctx, secret, gateway)
+		if err != nil {
+			log.Log.V(3).Info("placement: failed to get downstream gateway", "cluster", cluster, "error", err)
+			continue
+		}
+		if downstream != nil && downstream.GetDeletionTimestamp() == nil {
+			placed.Insert(cluster)
+		}
+	}
+	return placed, nil
+}
+
+// GetClusters returns the clusters whose cluster secret matches the label
+// selector of the gateway. It does not check the placement has happened
+func (sp *clusterSecretPlacer) GetClusters(ctx context.Context, gateway *gatewayapiv1.Gateway) (sets.Set[string], error) {
+	targetClusters := sets.New[string]()
+	selector, err := ClusterSelector(gateway)
+	if err != nil || selector == nil {
+		return targetClusters, err
+	}
+	secrets, err := sp.getClusterSecrets(ctx, gateway)
+	if err != nil {
+		return targetClusters, err
+	}
+	for cluster, secret := range secrets {
+		if selector.Matches(labels.Set(secret.Labels)) {
+			targetClusters.Insert(cluster)
+		}
+	}
+	return targetClusters, nil
+}
+
+// GetClusterLabels returns the labels of the cluster secret of the cluster
+func (sp *clusterSecretPlacer) GetClusterLabels(ctx context.Context, gateway *gatewayapiv1.Gateway, cluster string) (map[string]string, error) {
+	secrets, err := sp.getClusterSecrets(ctx, gateway)
+	if err != nil {
+		return nil, err
+	}
+	return secrets[cluster].GetLabels(), nil
+}
+
+func (sp *clusterSecretPlacer) GetAddresses(ctx context.Context, gateway *gatewayapiv1.Gateway, downstream string) ([]gatewayapiv1.GatewayAddress, error) {
+	addresses := []gatewayapiv1.GatewayAddress{}
+	downstreamGateway, err := sp.getPlacedGateway(ctx, gateway, downstream)
+	if err != nil {
+		return addresses, err
+	}
+	for _, address := range downstreamGateway.Status.Addresses {
+		addresses = append(addresses, gatewayapiv1.GatewayAddress{
+			Type:  address.Type,
+			Value: address.Value,
+		})
+	}
+	return addresses, nil
+}
+
+func (sp *clusterSecretPlacer) GetDownstreamStatus(ctx context.Context, gateway *gatewayapiv1.Gateway, downstream string) (*gatewayapiv1.GatewayStatus, error) {
+	downstreamGateway, err := sp.getPlacedGateway(ctx, gateway, downstream)
+	if err != nil {
+		return &gatewayapiv1.GatewayStatus{}, err
+	}
+	return &downstreamGateway.Status, nil
+}
+
+func (sp *clusterSecretPlacer) ListenerTotalAttachedRoutes(ctx context.Context, gateway *gatewayapiv1.Gateway, listenerName string, downstream string) (int, error) {
+	downstreamGateway, err := sp.getPlacedGateway(ctx, gateway, downstream)
+	if err != nil {
+		return 0, err
+	}
+	for _, listener := range downstreamGateway.Status.Listeners {
+		if string(listener.Name) == listenerName {
+			return int(listener.AttachedRoutes), nil
+		}
+	}
+	return 0, fmt.Errorf("no listener %s status found", listenerName)
+}
+
+// ClusterSelector returns the cluster label selector of the gateway, or nil
+// when it doesn't have one
+func ClusterSelector(gateway metav1.Object) (labels.Selector, error) {
+	value := metadata.GetAnnotation(gateway, ClusterLabelSelectorAnnotation)
+	if value == "" {
+		return nil, nil
+	}
+	selector, err := labels.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", ClusterLabelSelectorAnnotation, err)
+	}
+	return selector, nil
+}
+
+func (sp *clusterSecretPlacer) getPlacedGateway(ctx context.Context, gateway *gatewayapiv1.Gateway, cluster string) (*gatewayapiv1.Gateway, error) {
+	secrets, err := sp.getClusterSecrets(ctx, gateway)
+	if err != nil {
+		return nil, err
+	}
+	secret, ok := secrets[cluster]
+	if !ok {
+		return nil, fmt.Errorf("no cluster secret found for cluster %s", cluster)
+	}
+	downstream, err := sp.getDownstreamGateway(ctx, secret, gateway)
+	if err != nil {
+		return nil, err
+	}
+	if downstream == nil {
+		return nil, fmt.Errorf("gateway %s not placed on cluster %s", gateway.Name, cluster)
+	}
+	return downstream, nil
+}
+
+// GetPlacedGateway returns the downstream gateway applied to the cluster, or
+// nil when it's not placed there
+func (sp *clusterSecretPlacer) GetPlacedGateway(ctx context.Context, gateway *gatewayapiv1.Gateway, cluster string) (*gatewayapiv1.Gateway, error) {
+	secrets, err := sp.getClusterSecrets(ctx, gateway)
+	if err != nil {
+		return nil, err
+	}
+	secret, ok := secrets[cluster]
+	if !ok {
+		return nil, nil
+	}
+	downstream, err := sp.getDownstreamGateway(ctx, secret, gateway)
+	if err != nil || downstream == nil {
+		return nil, err
+	}
+
+	placed := &gatewayapiv1.Gateway{
+		TypeMeta: downstream.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        downstream.Name,
+			Namespace:   downstream.Namespace,
+			Labels:      downstream.Labels,
+			Annotations: downstream.Annotations,
+		},
+		Spec: downstream.Spec,
+	}
+	delete(placed.Annotations, PlacementHashAnnotation)
+	return placed, nil
+}
+
+// GetPlacedChildren returns the children applied to the cluster with the
+// downstream gateway, other than namespaces
+func (sp *clusterSecretPlacer) GetPlacedChildren(ctx context.Context, gateway *gatewayapiv1.Gateway, cluster string) ([]metav1.Object, error) {
+	secrets, err := sp.getClusterSecrets(ctx, gateway)
+	if err != nil {
+		return nil, err
+	}
+	secret, ok := secrets[cluster]
+	if !ok {
+		return nil, nil
+	}
+	c, err := sp.getClient(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	children := []metav1.Object{}
+	for _, gvk := range placedKinds {
+		if gvk.Kind == "Gateway" {
+			continue
+		}
+		list, err := listPlaced(ctx, c, WorkName(gateway), gvk)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list {
+			obj := &list[i]
+			placed := obj.DeepCopy()
+			placed.Object["metadata"] = map[string]interface{}{}
+			placed.SetGroupVersionKind(gvk)
+			placed.SetName(obj.GetName())
+			placed.SetNamespace(obj.GetNamespace())
+			labels := map[string]string{}
+			for k, v := range obj.GetLabels() {
+				if k != WorkManifestLabel && !strings.HasPrefix(k, PlacementOwnerLabelPrefix) {
+					labels[k] = v
+				}
+			}
+			placed.SetLabels(labels)
+			annotations := obj.GetAnnotations()
+			delete(annotations, PlacementHashAnnotation)
+			placed.SetAnnotations(annotations)
+			children = append(children, placed)
+		}
+	}
+	return children, nil
+}
+
+// getDownstreamGateway finds the downstream gateway of the upstream gateway
+// on the cluster by its label, returning nil when there is none
+func (sp *clusterSecretPlacer) getDownstreamGateway(ctx context.Context, secret *corev1.Secret, gateway *gatewayapiv1.Gateway) (*gatewayapiv1.Gateway, error) {
+	c, err := sp.getClient(secret)
+	if err != nil {
+		return nil, err
+	}
+	gateways := &gatewayapiv1.GatewayList{}
+	if err := c.List(ctx, gateways, client.MatchingLabels{WorkManifestLabel: WorkName(gateway)}); err != nil {
+		return nil, err
+	}
+	if len(gateways.Items) == 0 {
+		return nil, nil
+	}
+	return &gateways.Items[0], nil
+}
+
+// getClusterSecrets returns the cluster secrets available to the gateway by
+// cluster name
+func (sp *clusterSecretPlacer) getClusterSecrets(ctx context.Context, gateway *gatewayapiv1.Gateway) (map[string]*corev1.Secret, error) {
+	namespace := sp.namespace
+	if namespace == "" {
+		namespace = gateway.GetNamespace()
+	}
+	secretList := &corev1.SecretList{}
+	listOptions := client.MatchingLabels{
+		clusterSecret.CLUSTER_SECRET_LABEL: clusterSecret.CLUSTER_SECRET_LABEL_VALUE,
+	}
+	if err := sp.c.List(ctx, secretList, client.InNamespace(namespace), listOptions); err != nil {
+		return nil, err
+	}
+	secrets := map[string]*corev1.Secret{}
+	for i := range secretList.Items {
+		secret := &secretList.Items[i]
+		secrets[clusterName(secret)] = secret
+	}
+	return secrets, nil
+}
+
+// getClient returns the client of the cluster secret, recreating it when the
+// secret changes
+func (sp *clusterSecretPlacer) getClient(secret *corev1.Secret) (client.Client, error) {
+	if secret == nil {
+		return nil, fmt.Errorf("no cluster secret found")
+	}
+	sp.mux.Lock()
+	defer sp.mux.Unlock()
+
+	key := client.ObjectKeyFromObject(secret)
+	if cached, ok := sp.clients[key]; ok && cached.resourceVersion == secret.ResourceVersion {
+		return cached.client, nil
+	}
+	c, err := sp.clientFor(secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for cluster %s: %w", clusterName(secret), err)
+	}
+	sp.clients[key] = clusterClient{resourceVersion: secret.ResourceVersion, client: c}
+	return c, nil
+}
+
+func clusterName(secret *corev1.Secret) string {
+	if name := string(secret.Data["name"]); name != "" {
+		return name
+	}
+	return secret.Name
+}
+
+// namespacesFirst orders the namespaces before the objects they contain
+func namespacesFirst(objects []metav1.Object) []metav1.Object {
+	ordered := make([]metav1.Object, 0, len(objects))
+	for _, obj := range objects {
+		if _, ok := obj.(*corev1.Namespace); ok {
+			ordered = append(ordered, obj)
+		}
+	}
+	for _, obj := range objects {
+		if _, ok := obj.(*corev1.Namespace); !ok {
+			ordered = append(ordered, obj)
+		}
+	}
+	return ordered
+}
+
+// apply creates or updates the object on the cluster, labelled with the key
+// of the upstream gateway. An object placed for other gateways too, such as a
+// shared TLS secret, keeps their labels
+func apply(ctx context.Context, c client.Client, key string, obj client.Object) error {
+	desired := obj.DeepCopyObject().(client.Object)
+	hash, err := hashObject(desired)
+	if err != nil {
+		return err
+	}
+	metadata.AddAnnotation(desired, PlacementHashAnnotation, hash)
+
+	existing := desired.DeepCopyObject().(client.Object)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if k8serrors.IsNotFound(err) {
+			log.Log.V(3).Info("placement: object not found creating it ", "object", objectID(c, desired))
+			setOwners(desired, key, sets.New(key))
+			return c.Create(ctx, desired)
+		}
+		return err
+	}
+	owners := placementOwners(existing)
+	if metadata.GetAnnotation(existing, PlacementHashAnnotation) == hash && owners.Has(key) {
+		return nil
+	}
+	log.Log.V(3).Info("placement: object changed updating it ", "object", objectID(c, desired))
+	first := existing.GetLabels()[WorkManifestLabel]
+	if first == "" {
+		first = key
+	}
+	setOwners(desired, first, owners.Insert(key))
+	desired.SetResourceVersion(existing.GetResourceVersion())
+	return c.Update(ctx, desired)
+}
+
+// prune removes the key of the upstream gateway from the objects placed on the
+// cluster for it, except the ones to keep, deleting the objects that are no
+// longer placed for any gateway
+func prune(ctx context.Context, c client.Client, key string, keep sets.Set[string]) error {
+	for _, gvk := range placedKinds {
+		list, err := listPlaced(ctx, c, key, gvk)
+		if err != nil {
+			return err
+		}
+		for i := range list {
+			obj := &list[i]
+			if keep.Has(objectID(c, obj)) {
+				continue
+			}
+			owners := placementOwners(obj).Delete(key)
+			if owners.Len() > 0 {
+				log.Log.V(3).Info("placement: object placed for other gateways, releasing it ", "object", objectID(c, obj), "owners", sets.List(owners))
+				first := obj.GetLabels()[WorkManifestLabel]
+				if first == key || first == "" {
+					first = sets.List(owners)[0]
+				}
+				setOwners(obj, first, owners)
+				if err := c.Update(ctx, obj); client.IgnoreNotFound(err) != nil {
+					return err
+				}
+				continue
+			}
+			log.Log.V(3).Info("placement: removing object ", "object", objectID(c, obj))
+			if err := c.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// listPlaced lists the objects of the kind placed on the cluster for the
+// upstream gateway of the key, including the ones placed before they were
+// labelled with their owners
+func listPlaced(ctx context.Context, c client.Client, key string, gvk schema.GroupVersionKind) ([]unstructured.Unstructured, error) {
+	placed := []unstructured.Unstructured{}
+	seen := sets.New[string]()
+	for _, selector := range []client.ListOption{client.HasLabels{PlacementOwnerLabelPrefix + key}, client.MatchingLabels{WorkManifestLabel: key}} {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.List(ctx, list, selector); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			obj := list.Items[i]
+			obj.SetGroupVersionKind(gvk)
+			if id := objectID(c, &obj); !seen.Has(id) {
+				seen.Insert(id)
+				placed = append(placed, obj)
+			}
+		}
+	}
+	return placed, nil
+}
+
+// placementOwners returns the keys of the upstream gateways the object is
+// placed on the cluster for
+func placementOwners(obj client.Object) sets.Set[string] {
+	owners := sets.New[string]()
+	for k := range obj.GetLabels() {
+		if strings.HasPrefix(k, PlacementOwnerLabelPrefix) {
+			owners.Insert(strings.TrimPrefix(k, PlacementOwnerLabelPrefix))
+		}
+	}
+	// objects placed before they were labelled with their owners
+	if owners.Len() == 0 && obj.GetLabels()[WorkManifestLabel] != "" {
+		owners.Insert(obj.GetLabels()[WorkManifestLabel])
+	}
+	return owners
+}
+
+// setOwners labels the object with the keys of the upstream gateways it's
+// placed for, the WorkManifestLabel keeping the first one so that the label
+// doesn't change with each of them
+func setOwners(obj client.Object, first string, owners sets.Set[string]) {
+	labels := map[string]string{}
+	for k, v := range obj.GetLabels() {
+		if !strings.HasPrefix(k, PlacementOwnerLabelPrefix) {
+			labels[k] = v
+		}
+	}
+	labels[WorkManifestLabel] = first
+	for owner := range owners {
+		labels[PlacementOwnerLabelPrefix+owner] = "true"
+	}
+	obj.SetLabels(labels)
+}
+
+// objectID identifies an object on a cluster by kind, namespace and name
+func objectID(c client.Client, obj client.Object) string {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if gvk, err := apiutil.GVKForObject(obj, c.Scheme()); err == nil {
+		kind = gvk.Kind
+	}
+	return fmt.Sprintf("%s/%s/%s", kind, obj.GetNamespace(), obj.GetName())
+}
+
+func hashObject(obj client.Object) (string, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
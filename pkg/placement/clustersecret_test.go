@@ -0,0 +1,368 @@
+//go:build unit
+
+package placement_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/clusterSecret"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/gracePeriod"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+)
+
+func clusterSecretTestScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := gatewayapiv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func testClusterSecret(name, clusterType string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name + "-secret",
+			Namespace: "test",
+			Labels: map[string]string{
+				clusterSecret.CLUSTER_SECRET_LABEL: clusterSecret.CLUSTER_SECRET_LABEL_VALUE,
+				"type":                             clusterType,
+			},
+		},
+		Data: map[string][]byte{
+			"name": []byte(name),
+		},
+	}
+}
+
+func TestClusterSecretPlacer(t *testing.T) {
+	ctx := context.Background()
+	s := clusterSecretTestScheme(t)
+	hub := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		testClusterSecret("cluster-a", "test"),
+		testClusterSecret("cluster-b", "other"),
+	).Build()
+	spokes := map[string]client.Client{
+		"cluster-a": fake.NewClientBuilder().WithScheme(s).Build(),
+		"cluster-b": fake.NewClientBuilder().WithScheme(s).Build(),
+	}
+	placer := placement.NewClusterSecretPlacerWithClients(hub, "", func(secret *corev1.Secret) (client.Client, error) {
+		return spokes[string(secret.Data["name"])], nil
+	})
+
+	upstream := &gatewayapiv1.Gateway{
+		TypeMeta: metav1.TypeMeta{Kind: "Gateway", APIVersion: "gateway.networking.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: "test",
+			Annotations: map[string]string{
+				placement.ClusterLabelSelectorAnnotation: "type=test",
+			},
+		},
+		Spec: gatewayapiv1.GatewaySpec{
+			Listeners: []gatewayapiv1.Listener{{Name: "api", Port: 443, Protocol: gatewayapiv1.HTTPSProtocolType}},
+		},
+	}
+	downstream := upstream.DeepCopy()
+	downstream.ObjectMeta = metav1.ObjectMeta{Name: "gateway", Namespace: "kuadrant-test"}
+	tlsSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "kuadrant-test"}}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kuadrant-test"}}
+
+	// placed on the cluster matching the selector
+	placed, err := placer.Place(ctx, upstream, downstream, nil, nil, tlsSecret, namespace)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if !placed.Equal(sets.New("cluster-a")) {
+		t.Fatalf("expected gateway to be placed on cluster-a, got %v", placed.UnsortedList())
+	}
+	assertPlaced(t, spokes["cluster-a"], &gatewayapiv1.Gateway{}, "gateway", true)
+	assertPlaced(t, spokes["cluster-a"], &corev1.Secret{}, "tls", true)
+	assertPlaced(t, spokes["cluster-a"], &corev1.Namespace{}, "", true)
+	assertPlaced(t, spokes["cluster-b"], &gatewayapiv1.Gateway{}, "gateway", false)
+
+	placed, err = placer.GetPlacedClusters(ctx, upstream)
+	if err != nil || !placed.Equal(sets.New("cluster-a")) {
+		t.Fatalf("expected gateway to be placed on cluster-a, got %v (%v)", placed.UnsortedList(), err)
+	}
+
+	// status is read back from the downstream gateway
+	placedGateway := &gatewayapiv1.Gateway{}
+	if err := spokes["cluster-a"].Get(ctx, client.ObjectKey{Name: "gateway", Namespace: "kuadrant-test"}, placedGateway); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	ipAddressType := gatewayapiv1.IPAddressType
+	placedGateway.Status = gatewayapiv1.GatewayStatus{
+		Addresses: []gatewayapiv1.GatewayStatusAddress{{Type: &ipAddressType, Value: "172.16.0.1"}},
+		Listeners: []gatewayapiv1.ListenerStatus{{Name: "api", AttachedRoutes: 2}},
+	}
+	if err := spokes["cluster-a"].Update(ctx, placedGateway); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	addresses, err := placer.GetAddresses(ctx, upstream, "cluster-a")
+	if err != nil || len(addresses) != 1 || addresses[0].Value != "172.16.0.1" {
+		t.Errorf("expected the address of the downstream gateway, got %v (%v)", addresses, err)
+	}
+	if routes, err := placer.ListenerTotalAttachedRoutes(ctx, upstream, "api", "cluster-a"); err != nil || routes != 2 {
+		t.Errorf("expected 2 attached routes, got %d (%v)", routes, err)
+	}
+	if _, err := placer.ListenerTotalAttachedRoutes(ctx, upstream, "missing", "cluster-a"); err == nil {
+		t.Errorf("expected an error for a listener without status")
+	}
+
+	// children no longer placed are removed
+	if _, err := placer.Place(ctx, upstream, downstream, nil, nil); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	assertPlaced(t, spokes["cluster-a"], &corev1.Secret{}, "tls", false)
+	assertPlaced(t, spokes["cluster-a"], &gatewayapiv1.Gateway{}, "gateway", true)
+
+	// moved to the newly selected cluster once the grace period expires
+	upstream.Annotations[placement.ClusterLabelSelectorAnnotation] = "type=other"
+	if _, err := placer.Place(ctx, upstream, downstream, nil, nil); !errors.Is(err, gracePeriod.ErrGracePeriodNotExpired) {
+		t.Fatalf("expected the grace period not to have expired, got %v", err)
+	}
+	if err := spokes["cluster-a"].Get(ctx, client.ObjectKeyFromObject(placedGateway), placedGateway); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	placedGateway.Annotations[gracePeriod.GraceTimestampAnnotation] = strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	if err := spokes["cluster-a"].Update(ctx, placedGateway); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	placed, err = placer.Place(ctx, upstream, downstream, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if !placed.Equal(sets.New("cluster-b")) {
+		t.Fatalf("expected gateway to be placed on cluster-b, got %v", placed.UnsortedList())
+	}
+	assertPlaced(t, spokes["cluster-a"], &gatewayapiv1.Gateway{}, "gateway", false)
+	assertPlaced(t, spokes["cluster-b"], &gatewayapiv1.Gateway{}, "gateway", true)
+	// namespaces may be shared, so they're not removed, nor created unless placed
+	assertPlaced(t, spokes["cluster-a"], &corev1.Namespace{}, "", true)
+	assertPlaced(t, spokes["cluster-b"], &corev1.Namespace{}, "", false)
+
+	// removed from every cluster when deleted
+	upstream.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	placed, err = placer.Place(ctx, upstream, downstream, nil, nil)
+	if err != nil || placed.Len() != 0 {
+		t.Fatalf("expected gateway to be removed from every cluster, got %v (%v)", placed.UnsortedList(), err)
+	}
+	assertPlaced(t, spokes["cluster-b"], &gatewayapiv1.Gateway{}, "gateway", false)
+}
+
+func TestClusterSecretPlacer_sharedSecret(t *testing.T) {
+	ctx := context.Background()
+	s := clusterSecretTestScheme(t)
+	hub := fake.NewClientBuilder().WithScheme(s).WithObjects(testClusterSecret("cluster-a", "test")).Build()
+	spoke := fake.NewClientBuilder().WithScheme(s).Build()
+	placer := placement.NewClusterSecretPlacerWithClients(hub, "", func(secret *corev1.Secret) (client.Client, error) {
+		return spoke, nil
+	})
+	gateway := func(name string) (*gatewayapiv1.Gateway, *gatewayapiv1.Gateway) {
+		upstream := &gatewayapiv1.Gateway{
+			TypeMeta: metav1.TypeMeta{Kind: "Gateway", APIVersion: "gateway.networking.k8s.io/v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "test",
+				Annotations: map[string]string{placement.ClusterLabelSelectorAnnotation: "type=test"},
+			},
+		}
+		downstream := upstream.DeepCopy()
+		downstream.ObjectMeta = metav1.ObjectMeta{Name: name, Namespace: "kuadrant-test"}
+		return upstream, downstream
+	}
+	upstream1, downstream1 := gateway("gateway-1")
+	upstream2, downstream2 := gateway("gateway-2")
+	tlsSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "kuadrant-test"}}
+	placedSecret := func() *corev1.Secret {
+		t.Helper()
+		secret := &corev1.Secret{}
+		if err := spoke.Get(ctx, client.ObjectKeyFromObject(tlsSecret), secret); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		return secret
+	}
+
+	// the secret is placed for both gateways, and isn't updated by each in turn
+	for i := 0; i < 2; i++ {
+		for _, gateway := range [][2]*gatewayapiv1.Gateway{{upstream1, downstream1}, {upstream2, downstream2}} {
+			if _, err := placer.Place(ctx, gateway[0], gateway[1], nil, nil, tlsSecret); err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+		}
+	}
+	resourceVersion := placedSecret().ResourceVersion
+	for _, gateway := range [][2]*gatewayapiv1.Gateway{{upstream1, downstream1}, {upstream2, downstream2}} {
+		if _, err := placer.Place(ctx, gateway[0], gateway[1], nil, nil, tlsSecret); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+	if secret := placedSecret(); secret.ResourceVersion != resourceVersion {
+		t.Errorf("expected the shared secret not to be updated, got %v", secret.Labels)
+	}
+
+	// it's kept while a gateway still uses it
+	upstream1.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	if _, err := placer.Place(ctx, upstream1, downstream1, nil, nil); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	assertPlaced(t, spoke, &gatewayapiv1.Gateway{}, "gateway-1", false)
+	secret := placedSecret()
+	if _, ok := secret.Labels[placement.PlacementOwnerLabelPrefix+placement.WorkName(upstream1)]; ok {
+		t.Errorf("expected gateway-1 to be removed from the owners of the secret, got %v", secret.Labels)
+	}
+	if secret.Labels[placement.WorkManifestLabel] != placement.WorkName(upstream2) {
+		t.Errorf("expected the secret to be labelled for gateway-2, got %v", secret.Labels)
+	}
+
+	// and removed with the last one
+	upstream2.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	if _, err := placer.Place(ctx, upstream2, downstream2, nil, nil); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	assertPlaced(t, spoke, &corev1.Secret{}, "tls", false)
+}
+
+func TestClusterSecretPlacer_unreachable(t *testing.T) {
+	ctx := context.Background()
+	s := clusterSecretTestScheme(t)
+	hub := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		testClusterSecret("cluster-a", "test"),
+		testClusterSecret("cluster-b", "test"),
+	).Build()
+	spokes := map[string]client.Client{
+		"cluster-a": fake.NewClientBuilder().WithScheme(s).Build(),
+		"cluster-b": fake.NewClientBuilder().WithScheme(s).Build(),
+	}
+	unreachable := true
+	placer := placement.NewClusterSecretPlacerWithClients(hub, "", func(secret *corev1.Secret) (client.Client, error) {
+		name := string(secret.Data["name"])
+		if name == "cluster-b" && unreachable {
+			return nil, errors.New("connection refused")
+		}
+		return spokes[name], nil
+	})
+
+	upstream := &gatewayapiv1.Gateway{
+		TypeMeta: metav1.TypeMeta{Kind: "Gateway", APIVersion: "gateway.networking.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: "test",
+			Annotations: map[string]string{
+				placement.ClusterLabelSelectorAnnotation: "type=test",
+				placement.GatewayClustersAnnotation:      `["cluster-a","cluster-b"]`,
+			},
+		},
+	}
+	downstream := upstream.DeepCopy()
+	downstream.ObjectMeta = metav1.ObjectMeta{Name: "gateway", Namespace: "kuadrant-test"}
+
+	// the reachable clusters are placed regardless
+	placed, err := placer.Place(ctx, upstream, downstream, nil, nil)
+	if err == nil {
+		t.Fatalf("expected an error for the unreachable cluster")
+	}
+	if !placed.Equal(sets.New("cluster-a", "cluster-b")) {
+		t.Fatalf("expected the recorded unreachable cluster to be kept, got %v", placed.UnsortedList())
+	}
+	assertPlaced(t, spokes["cluster-a"], &gatewayapiv1.Gateway{}, "gateway", true)
+
+	// the gateway isn't removed until every recorded cluster is cleaned up
+	upstream.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	placed, err = placer.Place(ctx, upstream, downstream, nil, nil)
+	if err == nil || !placed.Equal(sets.New("cluster-b")) {
+		t.Fatalf("expected the unreachable cluster to remain, got %v (%v)", placed.UnsortedList(), err)
+	}
+	assertPlaced(t, spokes["cluster-a"], &gatewayapiv1.Gateway{}, "gateway", false)
+
+	unreachable = false
+	placed, err = placer.Place(ctx, upstream, downstream, nil, nil)
+	if err != nil || placed.Len() != 0 {
+		t.Fatalf("expected gateway to be removed from every cluster, got %v (%v)", placed.UnsortedList(), err)
+	}
+}
+
+func TestClusterSecretPlacer_GetClusters(t *testing.T) {
+	s := clusterSecretTestScheme(t)
+	hub := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		testClusterSecret("cluster-a", "test"),
+		testClusterSecret("cluster-b", "test"),
+		testClusterSecret("cluster-c", "other"),
+	).Build()
+	placer := placement.NewClusterSecretPlacer(hub, "test", time.Minute)
+
+	testCases := []struct {
+		name     string
+		selector string
+		expected sets.Set[string]
+		err      bool
+	}{
+		{
+			name:     "no selector",
+			expected: sets.New[string](),
+		},
+		{
+			name:     "matching selector",
+			selector: "type=test",
+			expected: sets.New("cluster-a", "cluster-b"),
+		},
+		{
+			name:     "set based selector",
+			selector: "type in (test,other)",
+			expected: sets.New("cluster-a", "cluster-b", "cluster-c"),
+		},
+		{
+			name:     "invalid selector",
+			selector: "type in (test",
+			expected: sets.New[string](),
+			err:      true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			gateway := &gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "other"}}
+			if testCase.selector != "" {
+				gateway.Annotations = map[string]string{placement.ClusterLabelSelectorAnnotation: testCase.selector}
+			}
+			clusters, err := placer.GetClusters(context.Background(), gateway)
+			if (err != nil) != testCase.err {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !clusters.Equal(testCase.expected) {
+				t.Errorf("expected clusters %v, got %v", sets.List(testCase.expected), sets.List(clusters))
+			}
+		})
+	}
+}
+
+func assertPlaced(t *testing.T, c client.Client, obj client.Object, name string, expected bool) {
+	t.Helper()
+	key := client.ObjectKey{Name: name, Namespace: "kuadrant-test"}
+	if _, ok := obj.(*corev1.Namespace); ok {
+		key = client.ObjectKey{Name: "kuadrant-test"}
+	}
+	err := c.Get(context.Background(), key, obj)
+	if expected && err != nil {
+		t.Errorf("expected %T %s to be placed, got %s", obj, key, err)
+	}
+	if !expected && !k8serrors.IsNotFound(err) {
+		t.Errorf("expected %T %s not to be placed, got %v", obj, key, err)
+	}
+}
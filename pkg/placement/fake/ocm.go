@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
 	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
 )
 
@@ -19,7 +20,7 @@ func NewTestGatewayPlacer() *FakeGatewayPlacer {
 	return &FakeGatewayPlacer{}
 }
 
-func (p *FakeGatewayPlacer) Place(_ context.Context, upstream *gatewayapiv1.Gateway, _ *gatewayapiv1.Gateway, _ ...metav1.Object) (sets.Set[string], error) {
+func (p *FakeGatewayPlacer) Place(_ context.Context, upstream *gatewayapiv1.Gateway, _ *gatewayapiv1.Gateway, _ placement.Overrides, _ placement.ClusterChildren, _ ...metav1.Object) (sets.Set[string], error) {
 	if upstream.Labels == nil {
 		return nil, nil
 	}
@@ -60,3 +61,12 @@ func (p *FakeGatewayPlacer) GetAddresses(_ context.Context, _ *gatewayapiv1.Gate
 		},
 	}, nil
 }
+
+func (p *FakeGatewayPlacer) GetDownstreamStatus(_ context.Context, _ *gatewayapiv1.Gateway, _ string) (*gatewayapiv1.GatewayStatus, error) {
+	return &gatewayapiv1.GatewayStatus{
+		Conditions: []metav1.Condition{
+			{Type: string(gatewayapiv1.GatewayConditionAccepted), Status: metav1.ConditionTrue},
+			{Type: string(gatewayapiv1.GatewayConditionProgrammed), Status: metav1.ConditionTrue},
+		},
+	}, nil
+}
@@ -4,19 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	placement "open-cluster-management.io/api/cluster/v1beta1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 	workv1 "open-cluster-management.io/api/work/v1"
 
-	v1 "k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
 	rbac "k8s.io/api/rbac/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/meta"
 	k8smeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -24,25 +26,57 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
-
-	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/gracePeriod"
 )
 
 const (
 	OCMPlacementLabel = "cluster.open-cluster-management.io/placement"
+	// ManagedWorkLabel marks the ManifestWorks of this controller
+	ManagedWorkLabel  = "kuadrant.io"
 	rbacName          = "open-cluster-management:klusterlet-work:gateway"
 	rbacManifest      = "gateway-rbac"
 	WorkManifestLabel = "kuadrant.io/manifestKey"
+
+	conditionsFeedback = "conditions"
+	listenersFeedback  = "listeners"
 )
 
+// Overrides returns the downstream gateway to place on a cluster, customised
+// from the downstream gateway common to every cluster
+type Overrides func(ctx context.Context, cluster string, downstream *gatewayapiv1.Gateway) (*gatewayapiv1.Gateway, error)
+
+func (o Overrides) apply(ctx context.Context, cluster string, downstream *gatewayapiv1.Gateway) (*gatewayapiv1.Gateway, error) {
+	if o == nil {
+		return downstream, nil
+	}
+	return o(ctx, cluster, downstream)
+}
+
+// ClusterChildren returns the children to place on a cluster along with the
+// gateway placed on it, such as the TLS secrets of its listeners
+type ClusterChildren func(ctx context.Context, cluster string, gateway *gatewayapiv1.Gateway) ([]metav1.Object, error)
+
+func (c ClusterChildren) apply(ctx context.Context, cluster string, gateway *gatewayapiv1.Gateway) ([]metav1.Object, error) {
+	if c == nil {
+		return nil, nil
+	}
+	return c(ctx, cluster, gateway)
+}
+
 type ocmPlacer struct {
-	c client.Client
+	c     client.Client
+	works *Works
 }
 
 func NewOCMPlacer(c client.Client) *ocmPlacer {
+	return NewOCMPlacerWithWorkMode(c, WorkPerObject)
+}
 
+// NewOCMPlacerWithWorkMode returns an OCM placer grouping the objects it
+// places into ManifestWorks according to the mode
+func NewOCMPlacerWithWorkMode(c client.Client, mode WorkMode) *ocmPlacer {
 	return &ocmPlacer{
-		c: c,
+		c:     c,
+		works: NewWorks(c, mode),
 	}
 }
 
@@ -50,17 +84,11 @@ func (op *ocmPlacer) GetAddresses(ctx context.Context, gateway *gatewayapiv1.Gat
 	workname := WorkName(gateway)
 	addresses := []gatewayapiv1.GatewayAddress{}
 	rootMeta, _ := k8smeta.Accessor(gateway)
-	mw := &workv1.ManifestWork{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      workname,
-			Namespace: downstream,
-		},
-	}
-	var err error
-	if err = op.c.Get(ctx, client.ObjectKeyFromObject(mw), mw, &client.GetOptions{}); err != nil {
+	mw, err := op.works.Get(ctx, downstream, workname)
+	if err != nil {
 		return addresses, err
 	}
-	for _, m := range mw.Status.ResourceStatus.Manifests {
+	for _, m := range op.works.ManifestStatuses(mw, workname) {
 		if m.ResourceMeta.Group == gateway.GetObjectKind().GroupVersionKind().Group && m.ResourceMeta.Name == rootMeta.GetName() {
 			for _, value := range m.StatusFeedbacks.Values {
 				if value.Name == "addresses" {
@@ -76,16 +104,11 @@ func (op *ocmPlacer) GetAddresses(ctx context.Context, gateway *gatewayapiv1.Gat
 func (op *ocmPlacer) ListenerTotalAttachedRoutes(ctx context.Context, gateway *gatewayapiv1.Gateway, listenerName string, downstream string) (int, error) {
 	workname := WorkName(gateway)
 	rootMeta, _ := k8smeta.Accessor(gateway)
-	mw := &workv1.ManifestWork{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      workname,
-			Namespace: downstream,
-		},
-	}
-	if err := op.c.Get(ctx, client.ObjectKeyFromObject(mw), mw, &client.GetOptions{}); err != nil {
+	mw, err := op.works.Get(ctx, downstream, workname)
+	if err != nil {
 		return 0, err
 	}
-	for _, m := range mw.Status.ResourceStatus.Manifests {
+	for _, m := range op.works.ManifestStatuses(mw, workname) {
 		if m.ResourceMeta.Group == gateway.GetObjectKind().GroupVersionKind().Group && m.ResourceMeta.Name == rootMeta.GetName() {
 			for _, value := range m.StatusFeedbacks.Values {
 				attachedRoutesStatusKey := strings.ToLower(fmt.Sprintf("listener%sAttachedRoutes", listenerName))
@@ -99,6 +122,86 @@ func (op *ocmPlacer) ListenerTotalAttachedRoutes(ctx context.Context, gateway *g
 
 }
 
+// GetDownstreamStatus returns the conditions of the downstream gateway and of
+// its listeners from the status feedback of the ManifestWork. The status has
+// no conditions until the work agent reports them
+func (op *ocmPlacer) GetDownstreamStatus(ctx context.Context, gateway *gatewayapiv1.Gateway, downstream string) (*gatewayapiv1.GatewayStatus, error) {
+	status := &gatewayapiv1.GatewayStatus{}
+	rootMeta, _ := k8smeta.Accessor(gateway)
+	mw, err := op.works.Get(ctx, downstream, WorkName(gateway))
+	if err != nil {
+		return status, err
+	}
+	for _, m := range op.works.ManifestStatuses(mw, WorkName(gateway)) {
+		if m.ResourceMeta.Group != gateway.GetObjectKind().GroupVersionKind().Group || m.ResourceMeta.Name != rootMeta.GetName() {
+			continue
+		}
+		for _, value := range m.StatusFeedbacks.Values {
+			if value.Value.JsonRaw == nil {
+				continue
+			}
+			var err error
+			switch value.Name {
+			case conditionsFeedback:
+				err = json.Unmarshal([]byte(*value.Value.JsonRaw), &status.Conditions)
+			case listenersFeedback:
+				err = json.Unmarshal([]byte(*value.Value.JsonRaw), &status.Listeners)
+			}
+			if err != nil {
+				return status, fmt.Errorf("invalid %s status feedback: %w", value.Name, err)
+			}
+		}
+	}
+	return status, nil
+}
+
+// GetPlacedGateway returns the downstream gateway in the ManifestWork of the
+// gateway on the cluster, or nil when it's not placed there
+func (op *ocmPlacer) GetPlacedGateway(ctx context.Context, gateway *gatewayapiv1.Gateway, downstream string) (*gatewayapiv1.Gateway, error) {
+	mw, err := op.works.Get(ctx, downstream, WorkName(gateway))
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, m := range op.works.Manifests(mw, WorkName(gateway)) {
+		placed := &gatewayapiv1.Gateway{}
+		if err := json.Unmarshal(m.Raw, placed); err != nil {
+			return nil, fmt.Errorf("invalid manifest in %s/%s: %w", mw.Namespace, mw.Name, err)
+		}
+		if placed.GroupVersionKind().GroupKind() == gatewayapiv1.SchemeGroupVersion.WithKind("Gateway").GroupKind() {
+			return placed, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetPlacedChildren returns the children placed with the downstream gateway
+// in its ManifestWork on the cluster, other than namespaces
+func (op *ocmPlacer) GetPlacedChildren(ctx context.Context, gateway *gatewayapiv1.Gateway, downstream string) ([]metav1.Object, error) {
+	mw, err := op.works.Get(ctx, downstream, WorkName(gateway))
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	children := []metav1.Object{}
+	for _, m := range op.works.Manifests(mw, WorkName(gateway)) {
+		placed := &unstructured.Unstructured{}
+		if err := placed.UnmarshalJSON(m.Raw); err != nil {
+			return nil, fmt.Errorf("invalid manifest in %s/%s: %w", mw.Namespace, mw.Name, err)
+		}
+		switch placed.GroupVersionKind().GroupKind() {
+		case gatewayapiv1.SchemeGroupVersion.WithKind("Gateway").GroupKind(), corev1.SchemeGroupVersion.WithKind("Namespace").GroupKind():
+			continue
+		}
+		children = append(children, placed)
+	}
+	return children, nil
+}
+
 func WorkName(rootObj runtime.Object) string {
 	kind := rootObj.GetObjectKind().GroupVersionKind().Kind
 	rootMeta, _ := k8smeta.Accessor(rootObj)
@@ -106,8 +209,7 @@ func WorkName(rootObj runtime.Object) string {
 }
 
 // Place ensures the gateway is placed onto the chosen clusters by creating the required manifestwork resources
-func (op *ocmPlacer) Place(ctx context.Context, upStreamGateway *gatewayapiv1.Gateway, downStreamGateway *gatewayapiv1.Gateway, children ...metav1.Object) (sets.Set[string], error) {
-	//PoC currently each object is put into its own manifestwork. This shouldn't be needed but would require finding the manifest work and replacing the existing object
+func (op *ocmPlacer) Place(ctx context.Context, upStreamGateway *gatewayapiv1.Gateway, downStreamGateway *gatewayapiv1.Gateway, overrides Overrides, clusterChildren ClusterChildren, children ...metav1.Object) (sets.Set[string], error) {
 	log := log.Log
 	log.V(3).Info("placement: placing ", "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
 	workname := WorkName(upStreamGateway)
@@ -131,30 +233,38 @@ func (op *ocmPlacer) Place(ctx context.Context, upStreamGateway *gatewayapiv1.Ga
 		log.V(3).Info("placement: ", "deleting gateway from ", existingClusters.UnsortedList(), "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
 		for _, cluster := range existingClusters.UnsortedList() {
 			// being deleted need to remove from clusters
-			w := &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{
-				Name:      workname,
-				Namespace: cluster,
-			}}
-			if err := op.c.Delete(ctx, w, &client.DeleteOptions{}); client.IgnoreNotFound(err) != nil {
+			if err := op.works.Remove(ctx, cluster, workname, true); err != nil {
+				return existingClusters, err
+			}
+			if err := ReconcileClusterRBAC(ctx, op.c, cluster); err != nil {
 				return existingClusters, err
 			}
 			existingClusters.Delete(cluster)
 		}
 		return existingClusters, nil
 	}
-	objects := []metav1.Object{downStreamGateway}
-	objects = append(objects, children...)
 	for _, cluster := range placementTargets.UnsortedList() {
-		log.V(3).Info("placement: ", "adding gateway rbac to cluster ", cluster, "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
-		if err := op.defaultRBAC(ctx, cluster); err != nil {
-			log.V(3).Info("placement: ", "adding gateway rbac to cluster ", cluster, "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace, "error", err)
+		gateway, err := overrides.apply(ctx, cluster, downStreamGateway)
+		if err != nil {
+			return existingClusters, err
+		}
+		gatewayChildren, err := clusterChildren.apply(ctx, cluster, gateway)
+		if err != nil {
 			return existingClusters, err
 		}
+		objects := []metav1.Object{gateway}
+		objects = append(objects, children...)
+		objects = append(objects, gatewayChildren...)
 		log.V(3).Info("placement: ", "adding gateway to cluster ", cluster, "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
-		if err := op.createUpdateClusterManifests(ctx, workname, upStreamGateway, downStreamGateway, cluster, objects...); err != nil {
+		if err := op.createUpdateClusterManifests(ctx, workname, upStreamGateway, gateway, cluster, objects...); err != nil {
 			log.V(3).Info("placement: ", "adding gateway to cluster ", cluster, "gateway", upStreamGateway.Name, "error", err)
 			return existingClusters, err
 		}
+		log.V(3).Info("placement: ", "adding gateway rbac to cluster ", cluster, "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
+		if err := ReconcileClusterRBAC(ctx, op.c, cluster); err != nil {
+			log.V(3).Info("placement: ", "adding gateway rbac to cluster ", cluster, "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace, "error", err)
+			return existingClusters, err
+		}
 		log.V(3).Info("placement: ", "added gateway to cluster ", cluster, "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
 		existingClusters.Insert(cluster)
 	}
@@ -162,18 +272,6 @@ func (op *ocmPlacer) Place(ctx context.Context, upStreamGateway *gatewayapiv1.Ga
 	// remove from remove
 	for _, cluster := range removeFrom.UnsortedList() {
 		log.V(3).Info("placement: ", "removing gateway from cluster ", cluster, "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
-		//todo remove rbac
-		w := &workv1.ManifestWork{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      workname,
-				Namespace: cluster,
-			},
-		}
-		err = op.c.Get(ctx, client.ObjectKeyFromObject(w), w)
-		if err != nil {
-			return existingClusters, err
-		}
-
 		// Check if the ManagedCluster still exists,
 		// otherwise delete without any grace period.
 		// This can happen if a ManagedCluster is deleted,
@@ -189,20 +287,14 @@ func (op *ocmPlacer) Place(ctx context.Context, upStreamGateway *gatewayapiv1.Ga
 			log.V(3).Info(fmt.Sprintf("ManagedCluster not found '%s', ignoring grace period", cluster))
 			ignoreGrace = true
 		}
-		if err := gracePeriod.GracefulDelete(ctx, op.c, w, ignoreGrace); err != nil {
+		if err := op.works.Remove(ctx, cluster, workname, ignoreGrace); err != nil {
 			// use a multi-error
 			log.V(3).Info("error during graceful delete", "error", err)
 			return existingClusters, err
 		}
 
-		log.V(3).Info("graceful delete of gateway manifestwork complete, deleting RBAC")
-		rbac := &workv1.ManifestWork{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: cluster,
-				Name:      rbacManifest,
-			},
-		}
-		if err := op.c.Delete(ctx, rbac, &client.DeleteOptions{}); client.IgnoreNotFound(err) != nil {
+		log.V(3).Info("graceful removal of gateway from manifestwork complete, reconciling RBAC")
+		if err := ReconcileClusterRBAC(ctx, op.c, cluster); err != nil {
 			// use a multi-error
 			return existingClusters, err
 		}
@@ -214,34 +306,32 @@ func (op *ocmPlacer) Place(ctx context.Context, upStreamGateway *gatewayapiv1.Ga
 
 // GetPlacedClusters will return the list of clusters this gateway has been successfully placed on
 func (op *ocmPlacer) GetPlacedClusters(ctx context.Context, gateway *gatewayapiv1.Gateway) (sets.Set[string], error) {
-	existing := &workv1.ManifestWorkList{}
-	listOptions := client.MatchingLabels{
-		WorkManifestLabel: WorkName(gateway),
-	}
 	existingClusters := sets.Set[string](sets.NewString())
-	if err := op.c.List(ctx, existing, listOptions); err != nil {
+	existing, err := op.works.Clusters(ctx, WorkName(gateway))
+	if err != nil {
 		return existingClusters, err
 	}
 	//where the gateway currently exists
 
-	for _, e := range existing.Items {
+	for cluster, e := range existing {
 		deleting := e.DeletionTimestamp != nil
-		applied := meta.IsStatusConditionTrue(e.Status.Conditions, string(workv1.ManifestApplied))
-		if !deleting && applied {
-			existingClusters = existingClusters.Insert(e.GetNamespace())
+		applied := op.works.AppliedCondition(e, WorkName(gateway))
+		if !deleting && applied != nil && applied.Status == metav1.ConditionTrue {
+			existingClusters = existingClusters.Insert(cluster)
 		}
 	}
 	return existingClusters, nil
 }
 
-// GetClusters will return the set of clusters this gateway is targeted to be placed on. It does not check the placement has happened
+// GetClusters will return the set of clusters this gateway is targeted to be placed on. It does not check the placement has happened.
+// The decisions of the Placement in the placement label take precedence over the cluster label selector annotation
 func (op *ocmPlacer) GetClusters(ctx context.Context, gateway *gatewayapiv1.Gateway) (sets.Set[string], error) {
 	rootMeta, _ := k8smeta.Accessor(gateway)
 	labels := rootMeta.GetLabels()
 	selectedPlacement := labels[OCMPlacementLabel]
 	targetClusters := sets.Set[string](sets.NewString())
 	if selectedPlacement == "" {
-		return targetClusters, nil
+		return op.getSelectedClusters(ctx, gateway)
 	}
 
 	// find the placement decsion
@@ -265,6 +355,52 @@ func (op *ocmPlacer) GetClusters(ctx context.Context, gateway *gatewayapiv1.Gate
 	return targetClusters, nil
 }
 
+// getSelectedClusters returns the ManagedClusters matching the cluster label
+// selector annotation of the gateway among the ManagedClusterSets bound to
+// its namespace, as an OCM Placement would, ignoring the ones being deleted
+// or not available
+func (op *ocmPlacer) getSelectedClusters(ctx context.Context, gateway *gatewayapiv1.Gateway) (sets.Set[string], error) {
+	targetClusters := sets.New[string]()
+	selector, err := ClusterSelector(gateway)
+	if err != nil || selector == nil {
+		return targetClusters, err
+	}
+
+	bindings := &clusterv1beta2.ManagedClusterSetBindingList{}
+	if err := op.c.List(ctx, bindings, client.InNamespace(gateway.GetNamespace())); err != nil {
+		return targetClusters, err
+	}
+	for _, binding := range bindings.Items {
+		if !k8smeta.IsStatusConditionTrue(binding.Status.Conditions, clusterv1beta2.ClusterSetBindingBoundType) {
+			continue
+		}
+		clusterSet := &clusterv1beta2.ManagedClusterSet{}
+		if err := op.c.Get(ctx, client.ObjectKey{Name: binding.Spec.ClusterSet}, clusterSet); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return targetClusters, err
+		}
+		setSelector, err := clusterv1beta2.BuildClusterSelector(clusterSet)
+		if err != nil {
+			return targetClusters, err
+		}
+
+		managedClusters := &clusterv1.ManagedClusterList{}
+		if err := op.c.List(ctx, managedClusters, client.MatchingLabelsSelector{Selector: setSelector}); err != nil {
+			return targetClusters, err
+		}
+		for _, managedCluster := range managedClusters.Items {
+			if managedCluster.DeletionTimestamp != nil || !selector.Matches(labels.Set(managedCluster.Labels)) ||
+				!k8smeta.IsStatusConditionTrue(managedCluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable) {
+				continue
+			}
+			targetClusters.Insert(managedCluster.Name)
+		}
+	}
+	return targetClusters, nil
+}
+
 func (op *ocmPlacer) createUpdateClusterManifests(ctx context.Context, manifestName string, upstream *gatewayapiv1.Gateway, downstream *gatewayapiv1.Gateway, cluster string, obj ...metav1.Object) error {
 	log := log.Log
 	// set up gateway manifest
@@ -272,39 +408,22 @@ func (op *ocmPlacer) createUpdateClusterManifests(ctx context.Context, manifestN
 	if err != nil {
 		return err
 	}
-	work := workv1.ManifestWork{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      manifestName,
-			Namespace: cluster,
-			Labels:    map[string]string{"kuadrant.io": "managed", WorkManifestLabel: manifestName},
-			// this is crap, there has to be a better way to map to the parent object perhaps using cache
-			// there is also a resource https://github.com/open-cluster-management-io/api/blob/main/work/v1alpha1/types_manifestworkreplicaset.go that we may migrate to which would solve this
-			Annotations: map[string]string{"kuadrant.io/parent": key},
-		},
-	}
 	objManifests, err := op.manifest(obj...)
 	if err != nil {
 		return err
 	}
 	log.V(3).Info("placement:", "manifests prepared", len(objManifests))
 
-	work.Spec.Workload = workv1.ManifestsTemplate{
-		Manifests: objManifests,
-	}
-
-	work.Spec.ManifestConfigs = []workv1.ManifestConfigOption{
-		{
-			ResourceIdentifier: workv1.ResourceIdentifier{
-				Group:     "gateway.networking.k8s.io",
-				Resource:  "gateways",
-				Name:      downstream.GetName(),
-				Namespace: downstream.GetNamespace(),
-			},
+	config := workv1.ManifestConfigOption{
+		ResourceIdentifier: workv1.ResourceIdentifier{
+			Group:     "gateway.networking.k8s.io",
+			Resource:  "gateways",
+			Name:      downstream.GetName(),
+			Namespace: downstream.GetNamespace(),
+		},
+		FeedbackRules: []workv1.FeedbackRule{
+			{Type: workv1.JSONPathsType},
 		},
-	}
-	// using 0 index as there is only one config here
-	work.Spec.ManifestConfigs[0].FeedbackRules = []workv1.FeedbackRule{
-		{Type: workv1.JSONPathsType},
 	}
 
 	jsonPaths := []workv1.JsonPath{
@@ -312,19 +431,33 @@ func (op *ocmPlacer) createUpdateClusterManifests(ctx context.Context, manifestN
 			Name: "addresses",
 			Path: ".status.addresses",
 		},
+		{
+			Name: conditionsFeedback,
+			Path: ".status.conditions",
+		},
+		{
+			Name: listenersFeedback,
+			Path: ".status.listeners",
+		},
 	}
-	for _, l := range upstream.Spec.Listeners {
+	for _, l := range downstream.Spec.Listeners {
 		jsonPaths = append(jsonPaths, workv1.JsonPath{
 			Name: fmt.Sprintf("listener%sAttachedRoutes", l.Name),
 			Path: fmt.Sprintf(".status.listeners[?(@.name==\"%s\")].attachedRoutes", l.Name),
 		})
 	}
 
-	work.Spec.ManifestConfigs[0].FeedbackRules[0].JsonPaths = jsonPaths
-	log.V(3).Info("feedback rules set ", "feedback ", work.Spec.ManifestConfigs[0].FeedbackRules)
+	config.FeedbackRules[0].JsonPaths = jsonPaths
+	log.V(3).Info("feedback rules set ", "feedback ", config.FeedbackRules)
 	log.V(3).Info("placement: creating updating maniftests for ", "cluster", cluster)
-	return op.createUpdateManifest(ctx, cluster, work)
-
+	_, err = op.works.Apply(ctx, cluster, Bundle{
+		Key:       manifestName,
+		Parent:    key,
+		Namespace: downstream.GetNamespace(),
+		Manifests: objManifests,
+		Configs:   []workv1.ManifestConfigOption{config},
+	})
+	return err
 }
 
 func (op *ocmPlacer) manifest(obj ...metav1.Object) ([]workv1.Manifest, error) {
@@ -338,27 +471,76 @@ func (op *ocmPlacer) manifest(obj ...metav1.Object) ([]workv1.Manifest, error) {
 		}
 
 		manifests = append(manifests, workv1.Manifest{RawExtension: runtime.RawExtension{Raw: jsonData}})
-		//setup the ns TODO this is gross look for better options
-		ns := v1.Namespace{
-			TypeMeta: metav1.TypeMeta{
-				Kind:       "Namespace",
-				APIVersion: "v1",
-			},
+	}
+	return manifests, nil
+}
+
+// ReconcileClusterRBAC grants the work agent of the cluster access to
+// gateways, and to the other resources placed on the cluster by the
+// ManifestWorks of this controller, through the shared gateway-rbac
+// ManifestWork. The RBAC is extended as new resources, such as synced
+// policies, are placed on the cluster, and it's only removed once the last
+// of these ManifestWorks leaves the cluster.
+//
+// Access is kept to the resources the work agent still reports in the status
+// of the ManifestWorks, and ManifestWorks being deleted keep their access, so
+// that the agent can prune the resources removed from a ManifestWork, or
+// deleted with it. The RBAC must be reconciled again once these are gone,
+// which ClusterRBACReconciler does as the ManifestWorks change
+func ReconcileClusterRBAC(ctx context.Context, c client.Client, cluster string) error {
+	works := &workv1.ManifestWorkList{}
+	if err := c.List(ctx, works, client.InNamespace(cluster), client.MatchingLabels{ManagedWorkLabel: "managed"}); err != nil {
+		return err
+	}
+
+	inUse := false
+	resources := map[string]sets.Set[string]{gatewayapiv1.GroupName: sets.New("gateways")}
+	grant := func(group, resource string) {
+		// the work agent can already manage core resources such as
+		// namespaces and secrets
+		if group == "" {
+			return
+		}
+		if resources[group] == nil {
+			resources[group] = sets.New[string]()
+		}
+		resources[group].Insert(resource)
+	}
+	for _, work := range works.Items {
+		if work.Name == rbacManifest {
+			continue
+		}
+		inUse = true
+		for _, config := range work.Spec.ManifestConfigs {
+			grant(config.ResourceIdentifier.Group, config.ResourceIdentifier.Resource)
+		}
+		for _, manifest := range work.Status.ResourceStatus.Manifests {
+			grant(manifest.ResourceMeta.Group, manifest.ResourceMeta.Resource)
+		}
+	}
+
+	if !inUse {
+		log.FromContext(ctx).V(3).Info("placement: no gateway or policy left on cluster, removing rbac", "cluster", cluster)
+		rbacWork := &workv1.ManifestWork{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: meta.GetNamespace(),
+				Namespace: cluster,
+				Name:      rbacManifest,
 			},
 		}
-		jsonData, err = json.Marshal(ns)
-		if err != nil {
-			return nil, err
-		}
-		manifests = append(manifests, workv1.Manifest{RawExtension: runtime.RawExtension{Raw: jsonData}})
+		return client.IgnoreNotFound(c.Delete(ctx, rbacWork, &client.DeleteOptions{}))
 	}
-	return manifests, nil
+
+	work, err := rbacWork(cluster, resources)
+	if err != nil {
+		return err
+	}
+	_, err = createOrUpdateWork(ctx, c, work)
+	return err
 }
 
-func (op *ocmPlacer) defaultRBAC(ctx context.Context, clusterName string) error {
-	var m = []workv1.Manifest{}
+// rbacWork returns the gateway-rbac ManifestWork granting the work agent
+// access to the resources, by API group
+func rbacWork(clusterName string, resources map[string]sets.Set[string]) (*workv1.ManifestWork, error) {
 	cr := rbac.ClusterRole{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "rbac.authorization.k8s.io/v1",
@@ -367,18 +549,24 @@ func (op *ocmPlacer) defaultRBAC(ctx context.Context, clusterName string) error
 		ObjectMeta: metav1.ObjectMeta{
 			Name: rbacName,
 		},
-		Rules: []rbac.PolicyRule{
-			{
-				Verbs:     []string{"*"},
-				APIGroups: []string{"gateway.networking.k8s.io"},
-				Resources: []string{"gateways"},
-			},
-		},
+	}
+	// sorted so the ClusterRole doesn't change between reconciles
+	groups := make([]string, 0, len(resources))
+	for group := range resources {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		cr.Rules = append(cr.Rules, rbac.PolicyRule{
+			Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+			APIGroups: []string{group},
+			Resources: sets.List(resources[group]),
+		})
 	}
 
 	clusterRoleJSON, err := json.Marshal(cr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	crb := rbac.ClusterRoleBinding{
@@ -387,12 +575,12 @@ func (op *ocmPlacer) defaultRBAC(ctx context.Context, clusterName string) error
 			APIVersion: "rbac.authorization.k8s.io/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: "open-cluster-management:klusterlet-work:gateway",
+			Name: rbacName,
 		},
 		RoleRef: rbac.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
 			Kind:     "ClusterRole",
-			Name:     "open-cluster-management:klusterlet-work:gateway",
+			Name:     rbacName,
 		},
 		Subjects: []rbac.Subject{
 			{
@@ -405,50 +593,19 @@ func (op *ocmPlacer) defaultRBAC(ctx context.Context, clusterName string) error
 
 	clusterRoleBindingJSON, err := json.Marshal(crb)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	m = append(m, workv1.Manifest{RawExtension: runtime.RawExtension{Raw: clusterRoleJSON}}, workv1.Manifest{RawExtension: runtime.RawExtension{Raw: clusterRoleBindingJSON}})
-	manifests := workv1.ManifestsTemplate{Manifests: []workv1.Manifest{}}
-	manifests.Manifests = append(manifests.Manifests, m...)
-
-	work := workv1.ManifestWork{
+	return &workv1.ManifestWork{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "gateway-rbac",
+			Name:      rbacManifest,
 			Namespace: clusterName,
 		},
 		Spec: workv1.ManifestWorkSpec{
-			Workload: manifests,
-		},
-	}
-	return op.createUpdateManifest(ctx, clusterName, work)
-}
-
-func (op *ocmPlacer) createUpdateManifest(ctx context.Context, cluster string, m workv1.ManifestWork) error {
-	mw := &workv1.ManifestWork{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      m.Name,
-			Namespace: m.Namespace,
+			Workload: workv1.ManifestsTemplate{Manifests: []workv1.Manifest{
+				{RawExtension: runtime.RawExtension{Raw: clusterRoleJSON}},
+				{RawExtension: runtime.RawExtension{Raw: clusterRoleBindingJSON}},
+			}},
 		},
-	}
-	if err := op.c.Get(ctx, client.ObjectKeyFromObject(mw), mw, &client.GetOptions{}); err != nil {
-		if k8serrors.IsNotFound(err) {
-			log.Log.V(3).Info("placement: manifest not found creating it ", "cluster", mw.Namespace)
-			if err := op.c.Create(ctx, &m, &client.CreateOptions{}); err != nil {
-				return err
-			}
-			return nil
-		}
-	}
-
-	if !equality.Semantic.DeepEqual(mw.Spec, m.Spec) {
-		log.Log.V(3).Info("placement: manifest found updating it ")
-		mw.Spec = m.Spec
-		if err := op.c.Update(ctx, mw, &client.UpdateOptions{}); err != nil {
-			log.Log.V(3).Info("placement:  updating manifest ", "error", err)
-			return err
-		}
-	}
-
-	return nil
+	}, nil
 }
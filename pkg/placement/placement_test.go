@@ -6,11 +6,15 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	pd "open-cluster-management.io/api/cluster/v1beta1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 	workv1 "open-cluster-management.io/api/work/v1"
 
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,6 +34,12 @@ func init() {
 	if err := pd.AddToScheme(scheme.Scheme); err != nil {
 		panic(err)
 	}
+	if err := clusterv1.AddToScheme(scheme.Scheme); err != nil {
+		panic(err)
+	}
+	if err := clusterv1beta2.AddToScheme(scheme.Scheme); err != nil {
+		panic(err)
+	}
 }
 
 func TestGetAddresses(t *testing.T) {
@@ -469,6 +479,7 @@ func TestGetClusters(t *testing.T) {
 	testCases := []struct {
 		Name              string
 		PlacementDecision func(clusters sets.Set[string]) *pd.PlacementDecision
+		ManagedClusters   []client.Object
 		Gateway           *gatewayapiv1.Gateway
 		Clusters          sets.Set[string]
 		Assert            func(t *testing.T, err error, clusters, expected sets.Set[string])
@@ -533,11 +544,153 @@ func TestGetClusters(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			Name:     "test clusters selected by label selector",
+			Clusters: sets.New("c1", "c2"),
+			Gateway: &gatewayapiv1.Gateway{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{placement.ClusterLabelSelectorAnnotation: "region in (eu,us)"},
+					Namespace:   "test",
+				},
+			},
+			ManagedClusters: []client.Object{
+				testClusterSet("test-set"),
+				testClusterSetBinding("test", "test-set", true),
+				testManagedCluster("c1", "eu", false),
+				testManagedCluster("c2", "us", false),
+				testManagedCluster("c3", "ap", false),
+				testManagedCluster("c4", "eu", true),
+			},
+			Assert: func(t *testing.T, err error, got, expected sets.Set[string]) {
+				if err != nil {
+					t.Fatalf("did not expect an error but got one %s", err)
+				}
+				if !got.Equal(expected) {
+					t.Fatalf("expected clusters %v but got %v", expected.UnsortedList(), got.UnsortedList())
+				}
+			},
+			PlacementDecision: func(clusters sets.Set[string]) *pd.PlacementDecision {
+				return nil
+			},
+		},
+		{
+			Name:     "test clusters not in a cluster set bound to the namespace are not selected",
+			Clusters: sets.New("c1"),
+			Gateway: &gatewayapiv1.Gateway{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{placement.ClusterLabelSelectorAnnotation: "region=eu"},
+					Namespace:   "test",
+				},
+			},
+			ManagedClusters: []client.Object{
+				testClusterSet("test-set"),
+				testClusterSet("other-set"),
+				testClusterSet("unbound-set"),
+				testClusterSetBinding("test", "test-set", true),
+				testClusterSetBinding("other", "other-set", true),
+				testClusterSetBinding("test", "unbound-set", false),
+				testManagedCluster("c1", "eu", false),
+				inClusterSet(testManagedCluster("c2", "eu", false), "other-set"),
+				inClusterSet(testManagedCluster("c3", "eu", false), "unbound-set"),
+				inClusterSet(testManagedCluster("c4", "eu", false), ""),
+			},
+			Assert: func(t *testing.T, err error, got, expected sets.Set[string]) {
+				if err != nil {
+					t.Fatalf("did not expect an error but got one %s", err)
+				}
+				if !got.Equal(expected) {
+					t.Fatalf("expected clusters %v but got %v", expected.UnsortedList(), got.UnsortedList())
+				}
+			},
+			PlacementDecision: func(clusters sets.Set[string]) *pd.PlacementDecision {
+				return nil
+			},
+		},
+		{
+			Name:     "test unavailable clusters are not selected",
+			Clusters: sets.New("c1"),
+			Gateway: &gatewayapiv1.Gateway{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{placement.ClusterLabelSelectorAnnotation: "region=eu"},
+					Namespace:   "test",
+				},
+			},
+			ManagedClusters: []client.Object{
+				testClusterSet("test-set"),
+				testClusterSetBinding("test", "test-set", true),
+				testManagedCluster("c1", "eu", false),
+				unavailable(testManagedCluster("c2", "eu", false)),
+			},
+			Assert: func(t *testing.T, err error, got, expected sets.Set[string]) {
+				if err != nil {
+					t.Fatalf("did not expect an error but got one %s", err)
+				}
+				if !got.Equal(expected) {
+					t.Fatalf("expected clusters %v but got %v", expected.UnsortedList(), got.UnsortedList())
+				}
+			},
+			PlacementDecision: func(clusters sets.Set[string]) *pd.PlacementDecision {
+				return nil
+			},
+		},
+		{
+			Name:     "test placement label takes precedence over label selector",
+			Clusters: sets.New("c3"),
+			Gateway: &gatewayapiv1.Gateway{
+				ObjectMeta: v1.ObjectMeta{
+					Labels:      map[string]string{placement.OCMPlacementLabel: "test"},
+					Annotations: map[string]string{placement.ClusterLabelSelectorAnnotation: "region=eu"},
+					Namespace:   "test",
+				},
+			},
+			ManagedClusters: []client.Object{
+				testManagedCluster("c1", "eu", false),
+				testManagedCluster("c3", "ap", false),
+			},
+			Assert: func(t *testing.T, err error, got, expected sets.Set[string]) {
+				if err != nil {
+					t.Fatalf("did not expect an error but got one %s", err)
+				}
+				if !got.Equal(expected) {
+					t.Fatalf("expected clusters %v but got %v", expected.UnsortedList(), got.UnsortedList())
+				}
+			},
+			PlacementDecision: func(clusters sets.Set[string]) *pd.PlacementDecision {
+				return &pd.PlacementDecision{
+					ObjectMeta: v1.ObjectMeta{
+						Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+						Namespace: "test",
+					},
+					Status: pd.PlacementDecisionStatus{Decisions: []pd.ClusterDecision{{ClusterName: "c3"}}},
+				}
+			},
+		},
+		{
+			Name:     "test invalid label selector",
+			Clusters: sets.New[string](),
+			Gateway: &gatewayapiv1.Gateway{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{placement.ClusterLabelSelectorAnnotation: "region in (eu"},
+					Namespace:   "test",
+				},
+			},
+			Assert: func(t *testing.T, err error, got, expected sets.Set[string]) {
+				if err == nil {
+					t.Fatalf("expected an error but got none")
+				}
+				if !got.Equal(expected) {
+					t.Fatalf("expected clusters %v but got %v", expected.UnsortedList(), got.UnsortedList())
+				}
+			},
+			PlacementDecision: func(clusters sets.Set[string]) *pd.PlacementDecision {
+				return nil
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.Name, func(t *testing.T) {
-			f := fake.NewClientBuilder()
+			f := fake.NewClientBuilder().WithObjects(testCase.ManagedClusters...)
 			if pds := testCase.PlacementDecision(testCase.Clusters); pds != nil {
 				f.WithObjects(pds)
 			}
@@ -548,6 +701,55 @@ func TestGetClusters(t *testing.T) {
 	}
 }
 
+func testManagedCluster(name, region string, deleting bool) *clusterv1.ManagedCluster {
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"region": region, clusterv1beta2.ClusterSetLabel: "test-set"},
+		},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []v1.Condition{{Type: clusterv1.ManagedClusterConditionAvailable, Status: v1.ConditionTrue}},
+		},
+	}
+	if deleting {
+		managedCluster.DeletionTimestamp = &v1.Time{Time: time.Now()}
+		managedCluster.Finalizers = []string{"test"}
+	}
+	return managedCluster
+}
+
+func inClusterSet(managedCluster *clusterv1.ManagedCluster, clusterSet string) *clusterv1.ManagedCluster {
+	if clusterSet == "" {
+		delete(managedCluster.Labels, clusterv1beta2.ClusterSetLabel)
+		return managedCluster
+	}
+	managedCluster.Labels[clusterv1beta2.ClusterSetLabel] = clusterSet
+	return managedCluster
+}
+
+func unavailable(managedCluster *clusterv1.ManagedCluster) *clusterv1.ManagedCluster {
+	managedCluster.Status.Conditions[0].Status = v1.ConditionUnknown
+	return managedCluster
+}
+
+func testClusterSet(name string) *clusterv1beta2.ManagedClusterSet {
+	return &clusterv1beta2.ManagedClusterSet{ObjectMeta: v1.ObjectMeta{Name: name}}
+}
+
+func testClusterSetBinding(namespace, clusterSet string, bound bool) *clusterv1beta2.ManagedClusterSetBinding {
+	status := v1.ConditionFalse
+	if bound {
+		status = v1.ConditionTrue
+	}
+	return &clusterv1beta2.ManagedClusterSetBinding{
+		ObjectMeta: v1.ObjectMeta{Name: clusterSet, Namespace: namespace},
+		Spec:       clusterv1beta2.ManagedClusterSetBindingSpec{ClusterSet: clusterSet},
+		Status: clusterv1beta2.ManagedClusterSetBindingStatus{
+			Conditions: []v1.Condition{{Type: clusterv1beta2.ClusterSetBindingBoundType, Status: status}},
+		},
+	}
+}
+
 func TestDeschedule(t *testing.T) {
 	var manifestWorkFunc = func(downstream, name string) *workv1.ManifestWork {
 
@@ -683,7 +885,7 @@ func TestDeschedule(t *testing.T) {
 			p := placement.NewOCMPlacer(c)
 			// build a test function as we want to change state and execute twice
 
-			placed, err := p.Place(context.TODO(), testCase.Upstream, testCase.Downstream, testCase.TLSSecrets...)
+			placed, err := p.Place(context.TODO(), testCase.Upstream, testCase.Downstream, nil, nil, testCase.TLSSecrets...)
 			if placed != nil && !placed.Equal(testCase.Clusters) {
 				t.Fatalf("expected placed clusters %v to equal the target clusters %v", placed.UnsortedList(), testCase.Clusters.UnsortedList())
 			}
@@ -708,3 +910,267 @@ func TestDeschedule(t *testing.T) {
 		})
 	}
 }
+
+func TestPlaceClusterChildren(t *testing.T) {
+	upstream := &gatewayapiv1.Gateway{
+		TypeMeta: v1.TypeMeta{
+			Kind:       "Gateway",
+			APIVersion: "gateway.networking.k8s.io/v1",
+		},
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "test",
+			Name:      "test",
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+		},
+		Spec: gatewayapiv1.GatewaySpec{
+			Listeners: []gatewayapiv1.Listener{{Name: "api", Protocol: gatewayapiv1.HTTPProtocolType}},
+		},
+	}
+	decision := &pd.PlacementDecision{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "test",
+			Name:      "test",
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+		},
+		Status: pd.PlacementDecisionStatus{Decisions: []pd.ClusterDecision{{ClusterName: "c1"}}},
+	}
+	c := fake.NewClientBuilder().WithObjects(decision).Build()
+	p := placement.NewOCMPlacer(c)
+
+	// the overrides of the cluster add a TLS listener, whose secret is a
+	// child of the gateway placed on the cluster
+	overrides := func(_ context.Context, _ string, downstream *gatewayapiv1.Gateway) (*gatewayapiv1.Gateway, error) {
+		gateway := downstream.DeepCopy()
+		gateway.Spec.Listeners = append(gateway.Spec.Listeners, gatewayapiv1.Listener{
+			Name:     "tls",
+			Protocol: gatewayapiv1.HTTPSProtocolType,
+			TLS: &gatewayapiv1.GatewayTLSConfig{
+				CertificateRefs: []gatewayapiv1.SecretObjectReference{{Name: "tls-cert"}},
+			},
+		})
+		return gateway, nil
+	}
+	children := func(_ context.Context, _ string, gateway *gatewayapiv1.Gateway) ([]v1.Object, error) {
+		secrets := []v1.Object{}
+		for _, listener := range gateway.Spec.Listeners {
+			if listener.TLS == nil {
+				continue
+			}
+			secrets = append(secrets, &corev1.Secret{
+				TypeMeta:   v1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+				ObjectMeta: v1.ObjectMeta{Name: string(listener.TLS.CertificateRefs[0].Name), Namespace: gateway.Namespace},
+			})
+		}
+		return secrets, nil
+	}
+	if _, err := p.Place(context.TODO(), upstream, upstream.DeepCopy(), overrides, children); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	work := &workv1.ManifestWork{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "c1", Name: placement.WorkName(upstream)}, work); err != nil {
+		t.Fatalf("expected the gateway to be placed on c1, got %s", err)
+	}
+	secretPlaced := false
+	for _, manifest := range work.Spec.Workload.Manifests {
+		obj := map[string]interface{}{}
+		if err := json.Unmarshal(manifest.Raw, &obj); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if obj["kind"] == "Secret" {
+			secretPlaced = true
+		}
+	}
+	if !secretPlaced {
+		t.Errorf("expected the TLS secret of the listener added by the overrides to be placed")
+	}
+	paths := sets.New[string]()
+	for _, path := range work.Spec.ManifestConfigs[0].FeedbackRules[0].JsonPaths {
+		paths.Insert(path.Name)
+	}
+	if !paths.HasAll("listenerapiAttachedRoutes", "listenertlsAttachedRoutes") {
+		t.Errorf("expected the attached routes of every listener to be fed back, got %v", sets.List(paths))
+	}
+
+	placedChildren, err := p.GetPlacedChildren(context.TODO(), upstream, "c1")
+	if err != nil || len(placedChildren) != 1 || placedChildren[0].GetName() != "tls-cert" {
+		t.Errorf("expected the TLS secret to be returned as placed with the gateway, got %v (%v)", placedChildren, err)
+	}
+}
+
+func TestGetDownstreamStatus(t *testing.T) {
+	gateway := &gatewayapiv1.Gateway{
+		TypeMeta: v1.TypeMeta{
+			Kind:       "Gateway",
+			APIVersion: "gateway.networking.k8s.io/v1",
+		},
+		ObjectMeta: v1.ObjectMeta{
+			Name: "test",
+		},
+	}
+	conditions := `[{"type":"Programmed","status":"False","reason":"AddressNotAssigned","message":"no address","lastTransitionTime":"2024-01-01T00:00:00Z"}]`
+	listeners := `[{"name":"api","attachedRoutes":1,"supportedKinds":[],"conditions":[{"type":"Conflicted","status":"True","reason":"HostnameConflict","message":"","lastTransitionTime":"2024-01-01T00:00:00Z"}]}]`
+	mw := &workv1.ManifestWork{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      placement.WorkName(gateway),
+			Namespace: "test",
+		},
+		Status: workv1.ManifestWorkStatus{
+			ResourceStatus: workv1.ManifestResourceStatus{
+				Manifests: []workv1.ManifestCondition{
+					{
+						ResourceMeta: workv1.ManifestResourceMeta{
+							Group: "gateway.networking.k8s.io",
+							Name:  "test",
+						},
+						StatusFeedbacks: workv1.StatusFeedbackResult{
+							Values: []workv1.FeedbackValue{
+								{Name: "conditions", Value: workv1.FieldValue{JsonRaw: &conditions}},
+								{Name: "listeners", Value: workv1.FieldValue{JsonRaw: &listeners}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	p := placement.NewOCMPlacer(fake.NewClientBuilder().WithObjects(mw).Build())
+	status, err := p.GetDownstreamStatus(context.TODO(), gateway, "test")
+	if err != nil {
+		t.Fatalf("did not expect an error but got %s", err)
+	}
+	if len(status.Conditions) != 1 || status.Conditions[0].Reason != "AddressNotAssigned" {
+		t.Errorf("expected the downstream conditions, got %v", status.Conditions)
+	}
+	if len(status.Listeners) != 1 || status.Listeners[0].Name != "api" || len(status.Listeners[0].Conditions) != 1 {
+		t.Errorf("expected the downstream listener conditions, got %v", status.Listeners)
+	}
+
+	if _, err := p.GetDownstreamStatus(context.TODO(), gateway, "other"); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected a not found error for a cluster without manifest work, got %v", err)
+	}
+}
+
+func TestReconcileClusterRBAC(t *testing.T) {
+	work := func(name, group, resource string) *workv1.ManifestWork {
+		return &workv1.ManifestWork{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      name,
+				Namespace: "c1",
+				Labels:    map[string]string{"kuadrant.io": "managed", placement.WorkManifestLabel: name},
+			},
+			Spec: workv1.ManifestWorkSpec{
+				ManifestConfigs: []workv1.ManifestConfigOption{
+					{ResourceIdentifier: workv1.ResourceIdentifier{Group: group, Resource: resource, Name: "test", Namespace: "test"}},
+				},
+			},
+		}
+	}
+	clusterRole := func(t *testing.T, c client.Client) *rbacv1.ClusterRole {
+		rbacWork := &workv1.ManifestWork{}
+		if err := c.Get(context.TODO(), client.ObjectKey{Name: "gateway-rbac", Namespace: "c1"}, rbacWork); err != nil {
+			t.Fatalf("expected the rbac manifest work, got %s", err)
+		}
+		cr := &rbacv1.ClusterRole{}
+		if err := json.Unmarshal(rbacWork.Spec.Workload.Manifests[0].Raw, cr); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		return cr
+	}
+
+	c := fake.NewClientBuilder().WithObjects(
+		work("gateway-test-a", "gateway.networking.k8s.io", "gateways"),
+		work("gateway-test-b", "gateway.networking.k8s.io", "gateways"),
+		work("ratelimitpolicy-test-rlp", "kuadrant.io", "ratelimitpolicies"),
+		work("gateway-other", "", "secrets"),
+	).Build()
+
+	if err := placement.ReconcileClusterRBAC(context.TODO(), c, "c1"); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	cr := clusterRole(t, c)
+	if len(cr.Rules) != 2 {
+		t.Fatalf("expected a rule for gateways and one for policies, got %v", cr.Rules)
+	}
+	for _, rule := range cr.Rules {
+		if sets.New(rule.Verbs...).Has("*") {
+			t.Errorf("expected the verbs to be scoped, got %v", rule.Verbs)
+		}
+	}
+	if cr.Rules[0].APIGroups[0] != "gateway.networking.k8s.io" || cr.Rules[0].Resources[0] != "gateways" ||
+		cr.Rules[1].APIGroups[0] != "kuadrant.io" || cr.Rules[1].Resources[0] != "ratelimitpolicies" {
+		t.Errorf("unexpected rules %v", cr.Rules)
+	}
+
+	// the rbac is kept while any gateway or policy remains on the cluster
+	for _, name := range []string{"gateway-test-a", "ratelimitpolicy-test-rlp", "gateway-other"} {
+		if err := c.Delete(context.TODO(), &workv1.ManifestWork{ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "c1"}}); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+	if err := placement.ReconcileClusterRBAC(context.TODO(), c, "c1"); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if cr := clusterRole(t, c); len(cr.Rules) != 1 || cr.Rules[0].Resources[0] != "gateways" {
+		t.Errorf("expected only the gateways rule, got %v", cr.Rules)
+	}
+
+	// access is kept while a work is being deleted, until it's gone
+	terminating := work("ratelimitpolicy-test-terminating", "kuadrant.io", "ratelimitpolicies")
+	terminating.Finalizers = []string{"cluster.open-cluster-management.io/manifest-work-cleanup"}
+	if err := c.Create(context.TODO(), terminating); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := c.Delete(context.TODO(), terminating); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := placement.ReconcileClusterRBAC(context.TODO(), c, "c1"); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if cr := clusterRole(t, c); len(cr.Rules) != 2 || cr.Rules[1].Resources[0] != "ratelimitpolicies" {
+		t.Errorf("expected the policies rule to be kept for the work being deleted, got %v", cr.Rules)
+	}
+	if err := c.Get(context.TODO(), client.ObjectKeyFromObject(terminating), terminating); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	terminating.Finalizers = nil
+	if err := c.Update(context.TODO(), terminating); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := placement.ReconcileClusterRBAC(context.TODO(), c, "c1"); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if cr := clusterRole(t, c); len(cr.Rules) != 1 {
+		t.Errorf("expected the policies rule to be removed once the work is gone, got %v", cr.Rules)
+	}
+
+	// access is kept to the resources the work agent still reports, such as
+	// a policy removed from a consolidated work but not pruned yet
+	remaining := &workv1.ManifestWork{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Name: "gateway-test-b", Namespace: "c1"}, remaining); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	remaining.Status.ResourceStatus.Manifests = []workv1.ManifestCondition{
+		{ResourceMeta: workv1.ManifestResourceMeta{Group: "kuadrant.io", Resource: "authpolicies", Name: "test", Namespace: "test"}},
+	}
+	if err := c.Update(context.TODO(), remaining); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := placement.ReconcileClusterRBAC(context.TODO(), c, "c1"); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if cr := clusterRole(t, c); len(cr.Rules) != 2 || cr.Rules[1].Resources[0] != "authpolicies" {
+		t.Errorf("expected the rule for the policy reported by the work agent, got %v", cr.Rules)
+	}
+
+	if err := c.Delete(context.TODO(), &workv1.ManifestWork{ObjectMeta: v1.ObjectMeta{Name: "gateway-test-b", Namespace: "c1"}}); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := placement.ReconcileClusterRBAC(context.TODO(), c, "c1"); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := c.Get(context.TODO(), client.ObjectKey{Name: "gateway-rbac", Namespace: "c1"}, &workv1.ManifestWork{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected the rbac manifest work to be removed with the last gateway, got %v", err)
+	}
+}
@@ -0,0 +1,606 @@
+package placement
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/gracePeriod"
+)
+
+// WorkMode selects how the objects placed on a cluster are grouped into
+// ManifestWorks
+type WorkMode string
+
+const (
+	// WorkPerObject places each gateway, and each synced policy, in a
+	// ManifestWork of its own on each cluster
+	WorkPerObject WorkMode = "PerObject"
+	// WorkPerCluster places every gateway, secret and synced policy of a
+	// cluster in a single ManifestWork
+	WorkPerCluster WorkMode = "PerCluster"
+	// WorkPerNamespace places them in a single ManifestWork per downstream
+	// namespace of a cluster
+	WorkPerNamespace WorkMode = "PerNamespace"
+
+	// ParentAnnotation references the hub gateway a ManifestWork is placed
+	// for, to requeue it when the ManifestWork changes
+	ParentAnnotation = "kuadrant.io/parent"
+	// BundlesAnnotation records the bundles held by a consolidated
+	// ManifestWork, along with their parent gateway and their manifests
+	BundlesAnnotation = "kuadrant.io/bundles"
+	// ConsolidatedWorkLabel marks the ManifestWorks holding several bundles
+	ConsolidatedWorkLabel = "kuadrant.io/consolidated"
+
+	// WorkManifestsLimit is the maximum total size, in bytes, of the
+	// manifests of a ManifestWork accepted by the OCM hub
+	WorkManifestsLimit = 500 * 1024
+
+	consolidatedWorkName = "kuadrant"
+)
+
+// ErrWorkTooLarge is returned when the manifests of a ManifestWork, or its
+// annotations, are over the size the hub accepts
+var ErrWorkTooLarge = errors.New("manifest work too large")
+
+func ParseWorkMode(mode string) (WorkMode, error) {
+	switch WorkMode(mode) {
+	case WorkPerObject, WorkPerCluster, WorkPerNamespace:
+		return WorkMode(mode), nil
+	}
+	return "", fmt.Errorf("unknown ManifestWork mode %q, expected one of %s, %s or %s", mode, WorkPerObject, WorkPerCluster, WorkPerNamespace)
+}
+
+// Bundle is the set of manifests placed on a cluster for a gateway or for a
+// synced policy
+type Bundle struct {
+	// Key identifies the bundle, and names its ManifestWork in PerObject mode
+	Key string
+	// Parent is the namespace/name key of the hub gateway the bundle is
+	// placed for
+	Parent string
+	// Namespace is the downstream namespace of the bundle, grouping it with
+	// the other bundles of the namespace in PerNamespace mode
+	Namespace string
+
+	Manifests []workv1.Manifest
+	Configs   []workv1.ManifestConfigOption
+}
+
+// bundleRecord tracks a bundle held by a consolidated ManifestWork
+type bundleRecord struct {
+	Parent    string   `json:"parent"`
+	Manifests []string `json:"manifests"`
+	Configs   []string `json:"configs,omitempty"`
+	// RemoveAt is when the bundle is removed from the ManifestWork once its
+	// grace period expires, in unix seconds
+	RemoveAt int64 `json:"removeAt,omitempty"`
+}
+
+// Works places bundles on clusters through ManifestWorks grouped according
+// to the mode. A consolidated ManifestWork only changes the manifests of the
+// bundle being placed or removed, and the status of each bundle is mapped
+// from the status of its own manifests
+type Works struct {
+	c    client.Client
+	mode WorkMode
+}
+
+func NewWorks(c client.Client, mode WorkMode) *Works {
+	return &Works{
+		c:    c,
+		mode: mode,
+	}
+}
+
+func (w *Works) consolidated() bool {
+	return w.mode == WorkPerCluster || w.mode == WorkPerNamespace
+}
+
+func (w *Works) workName(bundle Bundle) string {
+	switch w.mode {
+	case WorkPerCluster:
+		return consolidatedWorkName
+	case WorkPerNamespace:
+		return fmt.Sprintf("%s-%s", consolidatedWorkName, bundle.Namespace)
+	}
+	return bundle.Key
+}
+
+// Apply places the bundle on the cluster, removing it from any other
+// ManifestWork holding it, for example after changing mode, once it's applied
+// from its new ManifestWork
+func (w *Works) Apply(ctx context.Context, cluster string, bundle Bundle) (*workv1.ManifestWork, error) {
+	var work *workv1.ManifestWork
+	var err error
+	if w.consolidated() {
+		work, err = w.applyConsolidated(ctx, cluster, bundle)
+	} else {
+		work, err = createOrUpdateWork(ctx, w.c, &workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        bundle.Key,
+				Namespace:   cluster,
+				Labels:      map[string]string{ManagedWorkLabel: "managed", WorkManifestLabel: bundle.Key},
+				Annotations: map[string]string{ParentAnnotation: bundle.Parent},
+			},
+			Spec: workv1.ManifestWorkSpec{
+				Workload:        workv1.ManifestsTemplate{Manifests: bundle.Manifests},
+				ManifestConfigs: bundle.Configs,
+			},
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	holding, err := w.holding(ctx, cluster, bundle.Key)
+	if err != nil {
+		return nil, err
+	}
+	for i := range holding {
+		if holding[i].Name == work.Name {
+			continue
+		}
+		// the objects of the bundle are deleted from the cluster when it's
+		// removed before they're also applied from their new ManifestWork
+		if !applied(w.AppliedCondition(work, bundle.Key), work) {
+			log.FromContext(ctx).V(3).Info("placement: keeping bundle in previous manifest work until applied", "bundle", bundle.Key, "work", holding[i].Name, "cluster", cluster)
+			break
+		}
+		if err := w.remove(ctx, &holding[i], bundle.Key, true); err != nil {
+			return nil, err
+		}
+	}
+	return work, nil
+}
+
+func (w *Works) applyConsolidated(ctx context.Context, cluster string, bundle Bundle) (*workv1.ManifestWork, error) {
+	work := &workv1.ManifestWork{}
+	if err := w.c.Get(ctx, client.ObjectKey{Name: w.workName(bundle), Namespace: cluster}, work); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, err
+		}
+		work = &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Name: w.workName(bundle), Namespace: cluster}}
+	}
+	records, err := bundleRecords(work)
+	if err != nil {
+		return nil, err
+	}
+	if records == nil {
+		records = map[string]bundleRecord{}
+	}
+
+	manifests, configs, err := workContents(work)
+	if err != nil {
+		return nil, err
+	}
+	record := bundleRecord{Parent: bundle.Parent}
+	for _, manifest := range bundle.Manifests {
+		key, err := manifestKey(manifest)
+		if err != nil {
+			return nil, err
+		}
+		manifests[key] = manifest
+		record.Manifests = append(record.Manifests, key)
+	}
+	for _, config := range bundle.Configs {
+		key := configKey(config.ResourceIdentifier)
+		configs[key] = config
+		record.Configs = append(record.Configs, key)
+	}
+	records[bundle.Key] = record
+
+	desired, err := consolidatedWork(work, records, manifests, configs)
+	if err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).V(3).Info("placement: placing bundle in consolidated manifest work", "bundle", bundle.Key, "work", desired.Name, "cluster", cluster)
+	return updateWork(ctx, w.c, work, desired)
+}
+
+// Remove removes the bundle from the cluster once its grace period expires,
+// returning gracePeriod.ErrGracePeriodNotExpired until then
+func (w *Works) Remove(ctx context.Context, cluster, key string, ignoreGrace bool) error {
+	holding, err := w.holding(ctx, cluster, key)
+	if err != nil {
+		return err
+	}
+	var graceErr error
+	for i := range holding {
+		err := w.remove(ctx, &holding[i], key, ignoreGrace)
+		if errors.Is(err, gracePeriod.ErrGracePeriodNotExpired) {
+			graceErr = err
+			continue
+		}
+		if client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return graceErr
+}
+
+func (w *Works) remove(ctx context.Context, work *workv1.ManifestWork, key string, ignoreGrace bool) error {
+	records, err := bundleRecords(work)
+	if err != nil {
+		return err
+	}
+	if records == nil {
+		return gracePeriod.GracefulDelete(ctx, w.c, work, ignoreGrace)
+	}
+
+	record := records[key]
+	if !ignoreGrace {
+		now := time.Now()
+		if record.RemoveAt == 0 {
+			log.FromContext(ctx).V(3).Info("placement: no grace period set for bundle, adding one now", "bundle", key, "work", work.Name)
+			record.RemoveAt = now.Add(gracePeriod.DefaultGracePeriod).Unix()
+			records[key] = record
+			if err := setBundleRecords(work, records); err != nil {
+				return err
+			}
+			if err := w.c.Update(ctx, work); err != nil {
+				return err
+			}
+			return gracePeriod.ErrGracePeriodNotExpired
+		}
+		if record.RemoveAt > now.Unix() {
+			return gracePeriod.ErrGracePeriodNotExpired
+		}
+	}
+
+	delete(records, key)
+	if len(records) == 0 {
+		return w.c.Delete(ctx, work)
+	}
+	manifests, configs, err := workContents(work)
+	if err != nil {
+		return err
+	}
+	desired, err := consolidatedWork(work, records, manifests, configs)
+	if err != nil {
+		return err
+	}
+	_, err = updateWork(ctx, w.c, work, desired)
+	return err
+}
+
+// Get returns the ManifestWork holding the bundle on the cluster, or a not
+// found error when it's not placed there
+func (w *Works) Get(ctx context.Context, cluster, key string) (*workv1.ManifestWork, error) {
+	if !w.consolidated() {
+		work := &workv1.ManifestWork{}
+		err := w.c.Get(ctx, client.ObjectKey{Name: key, Namespace: cluster}, work)
+		if !k8serrors.IsNotFound(err) {
+			return work, err
+		}
+	}
+	holding, err := w.holding(ctx, cluster, key)
+	if err != nil {
+		return nil, err
+	}
+	// the ManifestWork of the current mode takes precedence while changing mode
+	for i := range holding {
+		if (holding[i].Labels[ConsolidatedWorkLabel] != "") == w.consolidated() {
+			return &holding[i], nil
+		}
+	}
+	if len(holding) > 0 {
+		return &holding[0], nil
+	}
+	return nil, k8serrors.NewNotFound(workv1.Resource("manifestworks"), key)
+}
+
+// Clusters returns the ManifestWork holding the bundle on each cluster it's
+// placed on
+func (w *Works) Clusters(ctx context.Context, key string) (map[string]*workv1.ManifestWork, error) {
+	perObject := &workv1.ManifestWorkList{}
+	if err := w.c.List(ctx, perObject, client.MatchingLabels{WorkManifestLabel: key}); err != nil {
+		return nil, err
+	}
+	consolidated := &workv1.ManifestWorkList{}
+	if err := w.c.List(ctx, consolidated, client.HasLabels{ConsolidatedWorkLabel}); err != nil {
+		return nil, err
+	}
+
+	// the ManifestWorks of the current mode take precedence while changing mode
+	lists := [][]workv1.ManifestWork{perObject.Items, consolidated.Items}
+	if w.consolidated() {
+		lists[0], lists[1] = lists[1], lists[0]
+	}
+	works := map[string]*workv1.ManifestWork{}
+	for _, list := range lists {
+		for i := range list {
+			if _, ok := works[list[i].Namespace]; ok || !holds(&list[i], key) {
+				continue
+			}
+			works[list[i].Namespace] = &list[i]
+		}
+	}
+	return works, nil
+}
+
+// holding returns the ManifestWorks of the cluster holding the bundle,
+// whatever the mode they were created in
+func (w *Works) holding(ctx context.Context, cluster, key string) ([]workv1.ManifestWork, error) {
+	works := &workv1.ManifestWorkList{}
+	if err := w.c.List(ctx, works, client.InNamespace(cluster)); err != nil {
+		return nil, err
+	}
+	holding := []workv1.ManifestWork{}
+	for _, work := range works.Items {
+		if holds(&work, key) {
+			holding = append(holding, work)
+		}
+	}
+	return holding, nil
+}
+
+// Manifests returns the manifests of the bundle in the ManifestWork
+func (w *Works) Manifests(work *workv1.ManifestWork, key string) []workv1.Manifest {
+	records, err := bundleRecords(work)
+	if err != nil || records == nil {
+		return work.Spec.Workload.Manifests
+	}
+	keys := sets.New(records[key].Manifests...)
+	manifests := []workv1.Manifest{}
+	for _, manifest := range work.Spec.Workload.Manifests {
+		if manifestKey, err := manifestKey(manifest); err == nil && keys.Has(manifestKey) {
+			manifests = append(manifests, manifest)
+		}
+	}
+	return manifests
+}
+
+// ManifestStatuses returns the status, including the feedback, of the
+// manifests of the bundle in the ManifestWork
+func (w *Works) ManifestStatuses(work *workv1.ManifestWork, key string) []workv1.ManifestCondition {
+	records, err := bundleRecords(work)
+	if err != nil || records == nil {
+		return work.Status.ResourceStatus.Manifests
+	}
+	keys := sets.New(records[key].Manifests...)
+	statuses := []workv1.ManifestCondition{}
+	for _, status := range work.Status.ResourceStatus.Manifests {
+		resource := status.ResourceMeta
+		if keys.Has(objectKey(resource.Group, resource.Kind, resource.Namespace, resource.Name)) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// AppliedCondition returns the Applied condition of the bundle in the
+// ManifestWork: the condition of the ManifestWork for a ManifestWork of its
+// own, or the first condition of its manifests that isn't True otherwise.
+// It's nil until every manifest of the bundle reports it
+func (w *Works) AppliedCondition(work *workv1.ManifestWork, key string) *metav1.Condition {
+	records, err := bundleRecords(work)
+	if err != nil || records == nil {
+		return meta.FindStatusCondition(work.Status.Conditions, workv1.WorkApplied)
+	}
+	statuses := map[string]workv1.ManifestCondition{}
+	for _, status := range work.Status.ResourceStatus.Manifests {
+		resource := status.ResourceMeta
+		statuses[objectKey(resource.Group, resource.Kind, resource.Namespace, resource.Name)] = status
+	}
+	var applied *metav1.Condition
+	for _, key := range records[key].Manifests {
+		status, ok := statuses[key]
+		if !ok {
+			return nil
+		}
+		condition := meta.FindStatusCondition(status.Conditions, string(workv1.ManifestApplied))
+		if condition == nil || condition.Status != metav1.ConditionTrue {
+			return condition
+		}
+		applied = condition
+	}
+	return applied
+}
+
+// applied returns whether the Applied condition of a bundle is True for the
+// current generation of its ManifestWork
+func applied(condition *metav1.Condition, work *workv1.ManifestWork) bool {
+	workApplied := meta.FindStatusCondition(work.Status.Conditions, workv1.WorkApplied)
+	return condition != nil && condition.Status == metav1.ConditionTrue &&
+		workApplied != nil && workApplied.ObservedGeneration == work.Generation
+}
+
+// WorkParents returns the namespace/name keys of the hub gateways the
+// bundles of the ManifestWork are placed for
+func WorkParents(work metav1.Object) []string {
+	annotations := work.GetAnnotations()
+	records := map[string]bundleRecord{}
+	if err := json.Unmarshal([]byte(annotations[BundlesAnnotation]), &records); err != nil || len(records) == 0 {
+		if parent := annotations[ParentAnnotation]; parent != "" {
+			return []string{parent}
+		}
+		return nil
+	}
+	parents := sets.New[string]()
+	for _, record := range records {
+		parents.Insert(record.Parent)
+	}
+	return sets.List(parents)
+}
+
+func holds(work *workv1.ManifestWork, key string) bool {
+	records, err := bundleRecords(work)
+	if err != nil {
+		return false
+	}
+	if records == nil {
+		return work.Labels[WorkManifestLabel] == key
+	}
+	_, ok := records[key]
+	return ok
+}
+
+// bundleRecords returns the bundles held by a consolidated ManifestWork, or
+// nil for a ManifestWork holding a single bundle
+func bundleRecords(work *workv1.ManifestWork) (map[string]bundleRecord, error) {
+	raw, ok := work.Annotations[BundlesAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	records := map[string]bundleRecord{}
+	if err := json.Unmarshal([]byte(raw), &records); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation on manifest work %s/%s: %w", BundlesAnnotation, work.Namespace, work.Name, err)
+	}
+	return records, nil
+}
+
+func setBundleRecords(work *workv1.ManifestWork, records map[string]bundleRecord) error {
+	raw, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	if work.Annotations == nil {
+		work.Annotations = map[string]string{}
+	}
+	work.Annotations[BundlesAnnotation] = string(raw)
+	return nil
+}
+
+// workContents returns the manifests and manifest configs of the
+// ManifestWork by their key
+func workContents(work *workv1.ManifestWork) (map[string]workv1.Manifest, map[string]workv1.ManifestConfigOption, error) {
+	manifests := map[string]workv1.Manifest{}
+	for _, manifest := range work.Spec.Workload.Manifests {
+		key, err := manifestKey(manifest)
+		if err != nil {
+			return nil, nil, err
+		}
+		manifests[key] = manifest
+	}
+	configs := map[string]workv1.ManifestConfigOption{}
+	for _, config := range work.Spec.ManifestConfigs {
+		configs[configKey(config.ResourceIdentifier)] = config
+	}
+	return manifests, configs, nil
+}
+
+// consolidatedWork returns the ManifestWork holding the manifests and
+// manifest configs of the bundles. Manifests shared by several bundles, such
+// as their namespace, are only included once, and namespaces come first so
+// they're applied before the objects in them
+func consolidatedWork(work *workv1.ManifestWork, records map[string]bundleRecord, manifests map[string]workv1.Manifest, configs map[string]workv1.ManifestConfigOption) (*workv1.ManifestWork, error) {
+	manifestKeys := sets.New[string]()
+	configKeys := sets.New[string]()
+	for _, record := range records {
+		manifestKeys.Insert(record.Manifests...)
+		configKeys.Insert(record.Configs...)
+	}
+	sortedManifests := sets.List(manifestKeys)
+	sort.SliceStable(sortedManifests, func(i, j int) bool {
+		return isNamespaceKey(sortedManifests[i]) && !isNamespaceKey(sortedManifests[j])
+	})
+
+	desired := work.DeepCopy()
+	desired.Labels = map[string]string{ManagedWorkLabel: "managed", ConsolidatedWorkLabel: "true"}
+	desired.Spec.Workload.Manifests = []workv1.Manifest{}
+	for _, key := range sortedManifests {
+		desired.Spec.Workload.Manifests = append(desired.Spec.Workload.Manifests, manifests[key])
+	}
+	desired.Spec.ManifestConfigs = []workv1.ManifestConfigOption{}
+	for _, key := range sets.List(configKeys) {
+		desired.Spec.ManifestConfigs = append(desired.Spec.ManifestConfigs, configs[key])
+	}
+	if err := setBundleRecords(desired, records); err != nil {
+		return nil, err
+	}
+	return desired, nil
+}
+
+func createOrUpdateWork(ctx context.Context, c client.Client, work *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+	existing := &workv1.ManifestWork{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(work), existing); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, err
+		}
+		existing = &workv1.ManifestWork{}
+	}
+	return updateWork(ctx, c, existing, work)
+}
+
+// updateWork updates the existing ManifestWork, as read by the caller, to the
+// desired one, creating it when it has no resource version. The update is made
+// at the resource version that was read so that a conflicting change made
+// since then fails the update rather than being overwritten
+func updateWork(ctx context.Context, c client.Client, existing, desired *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+	if err := validateWorkSize(desired); err != nil {
+		return nil, err
+	}
+	if existing.ResourceVersion == "" {
+		log.FromContext(ctx).V(3).Info("placement: manifest not found creating it ", "cluster", desired.Namespace, "work", desired.Name)
+		return desired, c.Create(ctx, desired)
+	}
+
+	if equality.Semantic.DeepEqual(existing.Spec, desired.Spec) &&
+		equality.Semantic.DeepEqual(existing.Labels, desired.Labels) &&
+		equality.Semantic.DeepEqual(existing.Annotations, desired.Annotations) {
+		return existing, nil
+	}
+	log.FromContext(ctx).V(3).Info("placement: manifest found updating it ", "cluster", desired.Namespace, "work", desired.Name)
+	existing.Spec = desired.Spec
+	existing.Labels = desired.Labels
+	existing.Annotations = desired.Annotations
+	return existing, c.Update(ctx, existing)
+}
+
+// validateWorkSize fails with ErrWorkTooLarge when the work would be rejected
+// by the hub for the size of its manifests or of its annotations, which hold
+// the bundle records of a consolidated work
+func validateWorkSize(work *workv1.ManifestWork) error {
+	manifestsSize := 0
+	for _, manifest := range work.Spec.Workload.Manifests {
+		manifestsSize += len(manifest.Raw)
+	}
+	if manifestsSize > WorkManifestsLimit {
+		return fmt.Errorf("%w: manifests of %s/%s are %d bytes, over the %d bytes limit", ErrWorkTooLarge, work.Namespace, work.Name, manifestsSize, WorkManifestsLimit)
+	}
+	annotationsSize := 0
+	for k, v := range work.Annotations {
+		annotationsSize += len(k) + len(v)
+	}
+	if annotationsSize > apivalidation.TotalAnnotationSizeLimitB {
+		return fmt.Errorf("%w: annotations of %s/%s are %d bytes, over the %d bytes limit", ErrWorkTooLarge, work.Namespace, work.Name, annotationsSize, apivalidation.TotalAnnotationSizeLimitB)
+	}
+	return nil
+}
+
+func manifestKey(manifest workv1.Manifest) (string, error) {
+	object := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(manifest.Raw, object); err != nil {
+		return "", fmt.Errorf("invalid manifest: %w", err)
+	}
+	gvk := object.GroupVersionKind()
+	return objectKey(gvk.Group, gvk.Kind, object.Namespace, object.Name), nil
+}
+
+func objectKey(group, kind, namespace, name string) string {
+	return strings.Join([]string{group, kind, namespace, name}, "/")
+}
+
+func isNamespaceKey(key string) bool {
+	return strings.HasPrefix(key, objectKey("", "Namespace", "", ""))
+}
+
+func configKey(resource workv1.ResourceIdentifier) string {
+	return strings.Join([]string{resource.Group, resource.Resource, resource.Namespace, resource.Name}, "/")
+}
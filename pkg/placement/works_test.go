@@ -0,0 +1,409 @@
+//go:build unit
+
+package placement_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/gracePeriod"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+)
+
+func testManifest(apiVersion, kind, namespace, name string) workv1.Manifest {
+	return workv1.Manifest{RawExtension: runtime.RawExtension{
+		Raw: []byte(fmt.Sprintf(`{"apiVersion":%q,"kind":%q,"metadata":{"name":%q,"namespace":%q}}`, apiVersion, kind, name, namespace)),
+	}}
+}
+
+func testGatewayBundle(name, namespace, class string) placement.Bundle {
+	gateway := fmt.Sprintf(`{"apiVersion":"gateway.networking.k8s.io/v1","kind":"Gateway","metadata":{"name":%q,"namespace":%q},"spec":{"gatewayClassName":%q}}`, name, namespace, class)
+	return placement.Bundle{
+		Key:       fmt.Sprintf("gateway-test-%s", name),
+		Parent:    fmt.Sprintf("test/%s", name),
+		Namespace: namespace,
+		Manifests: []workv1.Manifest{
+			{RawExtension: runtime.RawExtension{Raw: []byte(gateway)}},
+			testManifest("v1", "Secret", namespace, name),
+			testManifest("v1", "Namespace", "", namespace),
+		},
+		Configs: []workv1.ManifestConfigOption{
+			{ResourceIdentifier: workv1.ResourceIdentifier{Group: "gateway.networking.k8s.io", Resource: "gateways", Name: name, Namespace: namespace}},
+		},
+	}
+}
+
+func manifestStatus(group, kind, namespace, name string, applied metav1.ConditionStatus, feedback ...workv1.FeedbackValue) workv1.ManifestCondition {
+	return workv1.ManifestCondition{
+		ResourceMeta:    workv1.ManifestResourceMeta{Group: group, Kind: kind, Namespace: namespace, Name: name},
+		Conditions:      []metav1.Condition{{Type: "Applied", Status: applied, Reason: "Test", LastTransitionTime: metav1.Now()}},
+		StatusFeedbacks: workv1.StatusFeedbackResult{Values: feedback},
+	}
+}
+
+func TestWorksConsolidated(t *testing.T) {
+	ctx := context.TODO()
+	c := fake.NewClientBuilder().Build()
+	works := placement.NewWorks(c, placement.WorkPerCluster)
+	getWork := func(t *testing.T) *workv1.ManifestWork {
+		t.Helper()
+		work := &workv1.ManifestWork{}
+		if err := c.Get(ctx, client.ObjectKey{Name: "kuadrant", Namespace: "c1"}, work); err != nil {
+			t.Fatalf("expected the consolidated manifest work, got %s", err)
+		}
+		return work
+	}
+
+	a := testGatewayBundle("a", "kuadrant-test", "istio")
+	b := testGatewayBundle("b", "kuadrant-test", "istio")
+	for _, bundle := range []placement.Bundle{a, b} {
+		if _, err := works.Apply(ctx, "c1", bundle); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+
+	// a single manifest work holds both gateways, sharing their namespace
+	work := getWork(t)
+	if len(work.Spec.Workload.Manifests) != 5 || len(work.Spec.ManifestConfigs) != 2 {
+		t.Fatalf("expected 5 manifests and 2 configs, got %v", work.Spec)
+	}
+	if first := work.Spec.Workload.Manifests[0]; string(first.Raw) != string(a.Manifests[2].Raw) {
+		t.Errorf("expected the namespace to be applied first, got %s", first.Raw)
+	}
+	if parents := placement.WorkParents(work); len(parents) != 2 || parents[0] != "test/a" || parents[1] != "test/b" {
+		t.Errorf("expected both gateways as parents, got %v", parents)
+	}
+
+	// placing an unchanged bundle doesn't update the manifest work, and
+	// updating one bundle leaves the manifests of the others as they are
+	if _, err := works.Apply(ctx, "c1", a); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if updated := getWork(t); updated.ResourceVersion != work.ResourceVersion {
+		t.Errorf("expected the manifest work not to be updated")
+	}
+	if _, err := works.Apply(ctx, "c1", testGatewayBundle("a", "kuadrant-test", "aws-lb")); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	work = getWork(t)
+	bManifests := map[string]bool{}
+	for _, manifest := range works.Manifests(work, b.Key) {
+		bManifests[string(manifest.Raw)] = true
+	}
+	if len(bManifests) != 3 || !bManifests[string(b.Manifests[0].Raw)] {
+		t.Errorf("expected the manifests of b to be unchanged, got %v", bManifests)
+	}
+
+	// the status of each bundle is mapped from its own manifests
+	addresses := `[{"value":"1.1.1.1"}]`
+	work.Status.ResourceStatus.Manifests = []workv1.ManifestCondition{
+		manifestStatus("", "Namespace", "", "kuadrant-test", metav1.ConditionTrue),
+		manifestStatus("", "Secret", "kuadrant-test", "a", metav1.ConditionTrue),
+		manifestStatus("gateway.networking.k8s.io", "Gateway", "kuadrant-test", "a", metav1.ConditionTrue,
+			workv1.FeedbackValue{Name: "addresses", Value: workv1.FieldValue{Type: workv1.JsonRaw, JsonRaw: &addresses}}),
+		manifestStatus("", "Secret", "kuadrant-test", "b", metav1.ConditionFalse),
+	}
+	if err := c.Update(ctx, work); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if statuses := works.ManifestStatuses(work, a.Key); len(statuses) != 3 {
+		t.Errorf("expected the statuses of the manifests of a, got %v", statuses)
+	}
+	if applied := works.AppliedCondition(work, a.Key); applied == nil || applied.Status != metav1.ConditionTrue {
+		t.Errorf("expected a to be applied, got %v", applied)
+	}
+	if applied := works.AppliedCondition(work, b.Key); applied != nil {
+		t.Errorf("expected b to be pending until its gateway reports, got %v", applied)
+	}
+
+	clusters, err := works.Clusters(ctx, a.Key)
+	if err != nil || len(clusters) != 1 || clusters["c1"] == nil {
+		t.Errorf("expected a to be placed on c1, got %v (%v)", clusters, err)
+	}
+
+	// removed once its grace period expires, keeping the shared namespace
+	if err := works.Remove(ctx, "c1", a.Key, false); !errors.Is(err, gracePeriod.ErrGracePeriodNotExpired) {
+		t.Errorf("expected the grace period not to have expired, got %v", err)
+	}
+	if len(works.Manifests(getWork(t), a.Key)) != 3 {
+		t.Errorf("expected a to remain during its grace period")
+	}
+	if err := works.Remove(ctx, "c1", a.Key, true); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	work = getWork(t)
+	if len(work.Spec.Workload.Manifests) != 3 || len(work.Spec.ManifestConfigs) != 1 {
+		t.Errorf("expected only b to remain, got %v", work.Spec)
+	}
+	if _, err := works.Get(ctx, "c1", a.Key); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected a not to be found, got %v", err)
+	}
+
+	if err := works.Remove(ctx, "c1", b.Key, true); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Name: "kuadrant", Namespace: "c1"}, &workv1.ManifestWork{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected the manifest work to be removed with its last bundle, got %v", err)
+	}
+}
+
+// applyWork reports every manifest of the ManifestWork applied, as the work
+// agent of the cluster does
+func applyWork(t *testing.T, c client.Client, name string) {
+	t.Helper()
+	work := &workv1.ManifestWork{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Name: name, Namespace: "c1"}, work); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	work.Status.Conditions = []metav1.Condition{{Type: workv1.WorkApplied, Status: metav1.ConditionTrue, Reason: "Test", ObservedGeneration: work.Generation, LastTransitionTime: metav1.Now()}}
+	work.Status.ResourceStatus.Manifests = []workv1.ManifestCondition{}
+	for _, manifest := range work.Spec.Workload.Manifests {
+		object := &unstructured.Unstructured{}
+		if err := object.UnmarshalJSON(manifest.Raw); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		gvk := object.GroupVersionKind()
+		work.Status.ResourceStatus.Manifests = append(work.Status.ResourceStatus.Manifests, manifestStatus(gvk.Group, gvk.Kind, object.GetNamespace(), object.GetName(), metav1.ConditionTrue))
+	}
+	if err := c.Update(context.TODO(), work); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+}
+
+func workNames(t *testing.T, c client.Client) []string {
+	t.Helper()
+	list := &workv1.ManifestWorkList{}
+	if err := c.List(context.TODO(), list, client.InNamespace("c1")); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	names := []string{}
+	for _, work := range list.Items {
+		names = append(names, work.Name)
+	}
+	return names
+}
+
+func TestWorksPerNamespace(t *testing.T) {
+	ctx := context.TODO()
+	c := fake.NewClientBuilder().Build()
+
+	// a gateway placed in a manifest work of its own
+	a := testGatewayBundle("a", "team-a", "istio")
+	if _, err := placement.NewWorks(c, placement.WorkPerObject).Apply(ctx, "c1", a); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	// moves into the manifest work of its namespace when changing mode, once
+	// applied from it
+	works := placement.NewWorks(c, placement.WorkPerNamespace)
+	if _, err := works.Apply(ctx, "c1", a); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if _, err := works.Apply(ctx, "c1", testGatewayBundle("b", "team-b", "istio")); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if names := workNames(t, c); len(names) != 3 || names[0] != "gateway-test-a" {
+		t.Errorf("expected the manifest work of a to be kept until applied from its namespace, got %v", names)
+	}
+	applyWork(t, c, "kuadrant-team-a")
+	if _, err := works.Apply(ctx, "c1", a); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if names := workNames(t, c); len(names) != 2 || names[0] != "kuadrant-team-a" || names[1] != "kuadrant-team-b" {
+		t.Errorf("expected a manifest work per namespace, got %v", names)
+	}
+
+	// and follows its namespace
+	if _, err := works.Apply(ctx, "c1", testGatewayBundle("a", "team-b", "istio")); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	teamB := &workv1.ManifestWork{}
+	if err := c.Get(ctx, client.ObjectKey{Name: "kuadrant-team-b", Namespace: "c1"}, teamB); err != nil || len(placement.WorkParents(teamB)) != 2 {
+		t.Errorf("expected a in the manifest work of team-b, got %v (%v)", placement.WorkParents(teamB), err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Name: "kuadrant-team-a", Namespace: "c1"}, &workv1.ManifestWork{}); err != nil {
+		t.Errorf("expected the manifest work of team-a to be kept until a is applied from team-b, got %s", err)
+	}
+	applyWork(t, c, "kuadrant-team-b")
+	if _, err := works.Apply(ctx, "c1", testGatewayBundle("a", "team-b", "istio")); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Name: "kuadrant-team-a", Namespace: "c1"}, &workv1.ManifestWork{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected the manifest work of team-a to be removed, got %v", err)
+	}
+	work, err := works.Get(ctx, "c1", a.Key)
+	if err != nil || work.Name != "kuadrant-team-b" {
+		t.Errorf("expected a in the manifest work of team-b, got %v (%v)", work, err)
+	}
+}
+
+func TestWorksConflict(t *testing.T) {
+	ctx := context.TODO()
+	base := fake.NewClientBuilder().Build()
+	works := placement.NewWorks(base, placement.WorkPerCluster)
+	if _, err := works.Apply(ctx, "c1", testGatewayBundle("a", "kuadrant-test", "istio")); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	// the consolidated work changes after being read to place another bundle
+	changed := false
+	c := interceptor.NewClient(base.(client.WithWatch), interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if err := c.Get(ctx, key, obj, opts...); err != nil || changed {
+				return err
+			}
+			changed = true
+			concurrent := obj.DeepCopyObject().(client.Object)
+			concurrent.SetAnnotations(map[string]string{"concurrent": "change"})
+			return c.Update(ctx, concurrent)
+		},
+	})
+	_, err := placement.NewWorks(c, placement.WorkPerCluster).Apply(ctx, "c1", testGatewayBundle("b", "kuadrant-test", "istio"))
+	if !k8serrors.IsConflict(err) {
+		t.Fatalf("expected a conflict placing from a stale read, got %v", err)
+	}
+	work := &workv1.ManifestWork{}
+	if err := base.Get(ctx, client.ObjectKey{Name: "kuadrant", Namespace: "c1"}, work); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if work.Annotations["concurrent"] != "change" {
+		t.Errorf("expected the concurrent change to be kept, got %v", work.Annotations)
+	}
+}
+
+func TestWorksTooLarge(t *testing.T) {
+	ctx := context.TODO()
+	large := testGatewayBundle("large", "kuadrant-test", "istio")
+	large.Manifests = append(large.Manifests, workv1.Manifest{RawExtension: runtime.RawExtension{
+		Raw: []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"large","namespace":"kuadrant-test"},"data":{"large":%q}}`, strings.Repeat("x", placement.WorkManifestsLimit))),
+	}})
+	many := testGatewayBundle("many", "kuadrant-test", "istio")
+	for i := 0; i < 2500; i++ {
+		many.Manifests = append(many.Manifests, testManifest("v1", "ConfigMap", "kuadrant-test", fmt.Sprintf("%s-%d", strings.Repeat("x", 100), i)))
+	}
+
+	for _, mode := range []placement.WorkMode{placement.WorkPerObject, placement.WorkPerCluster} {
+		for _, bundle := range []placement.Bundle{large, many} {
+			// the records of a consolidated work go over the annotations limit
+			// before its manifests go over theirs
+			if mode == placement.WorkPerObject && bundle.Key == many.Key {
+				continue
+			}
+			c := fake.NewClientBuilder().Build()
+			_, err := placement.NewWorks(c, mode).Apply(ctx, "c1", bundle)
+			if !errors.Is(err, placement.ErrWorkTooLarge) {
+				t.Errorf("%s: expected %s placing %s, got %v", mode, placement.ErrWorkTooLarge, bundle.Key, err)
+			}
+			list := &workv1.ManifestWorkList{}
+			if err := c.List(ctx, list); err != nil || len(list.Items) != 0 {
+				t.Errorf("%s: expected no manifest work for %s, got %v (%v)", mode, bundle.Key, list.Items, err)
+			}
+		}
+	}
+}
+
+func TestWorksModeSwitch(t *testing.T) {
+	ctx := context.TODO()
+	c := fake.NewClientBuilder().Build()
+	a := testGatewayBundle("a", "kuadrant-test", "istio")
+	b := testGatewayBundle("b", "kuadrant-test", "istio")
+	for _, bundle := range []placement.Bundle{a, b} {
+		if _, err := placement.NewWorks(c, placement.WorkPerCluster).Apply(ctx, "c1", bundle); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+	applyWork(t, c, "kuadrant")
+
+	// the gateway stays in the consolidated manifest work while its own
+	// manifest work isn't applied, so that its objects aren't deleted
+	works := placement.NewWorks(c, placement.WorkPerObject)
+	for i := 0; i < 2; i++ {
+		if _, err := works.Apply(ctx, "c1", a); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+	consolidated := &workv1.ManifestWork{}
+	if err := c.Get(ctx, client.ObjectKey{Name: "kuadrant", Namespace: "c1"}, consolidated); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if parents := placement.WorkParents(consolidated); len(parents) != 2 {
+		t.Errorf("expected a to be kept in the consolidated manifest work, got %v", parents)
+	}
+	if work, err := works.Get(ctx, "c1", a.Key); err != nil || work.Name != a.Key {
+		t.Errorf("expected the manifest work of a to take precedence, got %v (%v)", work, err)
+	}
+
+	// and is removed from it once applied from its own
+	applyWork(t, c, a.Key)
+	if _, err := works.Apply(ctx, "c1", a); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Name: "kuadrant", Namespace: "c1"}, consolidated); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if parents := placement.WorkParents(consolidated); len(parents) != 1 || parents[0] != b.Parent {
+		t.Errorf("expected only b in the consolidated manifest work, got %v", parents)
+	}
+}
+
+func TestOCMPlacerConsolidatedStatus(t *testing.T) {
+	ctx := context.TODO()
+	c := fake.NewClientBuilder().Build()
+	works := placement.NewWorks(c, placement.WorkPerCluster)
+
+	// gateways of the same name from two hub namespaces, placed on the same cluster
+	gateway := func(namespace string) *gatewayapiv1.Gateway {
+		return &gatewayapiv1.Gateway{
+			TypeMeta:   metav1.TypeMeta{Kind: "Gateway", APIVersion: "gateway.networking.k8s.io/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: namespace},
+		}
+	}
+	statuses := []workv1.ManifestCondition{}
+	for _, namespace := range []string{"a", "b"} {
+		bundle := testGatewayBundle("gateway", "kuadrant-"+namespace, "istio")
+		bundle.Key = placement.WorkName(gateway(namespace))
+		if _, err := works.Apply(ctx, "c1", bundle); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		addresses := fmt.Sprintf(`[{"value":"%s.example.com"}]`, namespace)
+		statuses = append(statuses, manifestStatus("gateway.networking.k8s.io", "Gateway", "kuadrant-"+namespace, "gateway", metav1.ConditionTrue,
+			workv1.FeedbackValue{Name: "addresses", Value: workv1.FieldValue{Type: workv1.JsonRaw, JsonRaw: &addresses}}))
+	}
+	work, err := works.Get(ctx, "c1", placement.WorkName(gateway("a")))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	work.Status.ResourceStatus.Manifests = statuses
+	if err := c.Update(ctx, work); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	p := placement.NewOCMPlacerWithWorkMode(c, placement.WorkPerCluster)
+	for _, namespace := range []string{"a", "b"} {
+		addresses, err := p.GetAddresses(ctx, gateway(namespace), "c1")
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if len(addresses) != 1 || addresses[0].Value != namespace+".example.com" {
+			t.Errorf("expected the address of the gateway from %s, got %v", namespace, addresses)
+		}
+		placed, err := p.GetPlacedGateway(ctx, gateway(namespace), "c1")
+		if err != nil || placed == nil || placed.Namespace != "kuadrant-"+namespace {
+			t.Errorf("expected the gateway placed from %s, got %v (%v)", namespace, placed, err)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-logr/logr"
 
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/cache"
@@ -27,44 +28,47 @@ var _ cache.ResourceEventHandler = &ResourceEventHandler{}
 
 func (h *ResourceEventHandler) OnAdd(reqObj interface{}, _ bool) {
 	h.Log.Info("Got watch event for policy", "obj", reqObj)
+	h.syncPolicy(reqObj)
+}
 
-	ctx := context.Background()
+func (h *ResourceEventHandler) OnDelete(reqObj interface{}) {
+	h.Log.Info("Got watch event for policy", "obj", reqObj)
 
-	obj, ok := reqObj.(client.Object)
-	if !ok {
-		h.Log.Error(fmt.Errorf("object %v does not inplement client.Object", reqObj), "")
-		return
+	if tombstone, ok := reqObj.(cache.DeletedFinalStateUnknown); ok {
+		reqObj = tombstone.Obj
 	}
-
-	if err := h.Client.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
-		h.Log.Error(err, "failed to get object", "object", obj)
+	if obj, ok := reqObj.(runtime.Object); ok {
+		reqObj = obj.DeepCopyObject()
 	}
 
-	policy, err := NewPolicyFor(obj)
+	policy, err := NewPolicyFor(reqObj)
 	if err != nil {
-		h.Log.Error(err, "failed to build policy from watched object", "object", obj)
+		h.Log.Error(err, "failed to build policy from deleted object", "object", reqObj)
 		return
 	}
 
-	if err := h.Syncer.SyncPolicy(ctx, h.Client, policy); err != nil {
-		h.Log.Error(err, "failed to sync policy", "policy", policy)
+	if err := h.Syncer.RemovePolicy(context.Background(), h.Client, policy); err != nil {
+		h.Log.Error(err, "failed to remove policy", "policy", policy)
 	}
 }
 
-func (h *ResourceEventHandler) OnDelete(obj interface{}) {
-	h.Log.Info("Got watch event for policy", "obj", obj)
-}
-
 func (h *ResourceEventHandler) OnUpdate(_ interface{}, reqObj interface{}) {
 	h.Log.Info("Got watch event for policy", "obj", reqObj)
+	h.syncPolicy(reqObj)
+}
 
+// syncPolicy syncs the latest version of the watched object. The object is
+// shared with the informer cache, so it is copied before being read into and
+// wrapped as a policy
+func (h *ResourceEventHandler) syncPolicy(reqObj interface{}) {
 	ctx := context.Background()
 
-	obj, ok := reqObj.(client.Object)
+	cached, ok := reqObj.(client.Object)
 	if !ok {
 		h.Log.Error(fmt.Errorf("object %v does not inplement client.Object", reqObj), "")
 		return
 	}
+	obj := cached.DeepCopyObject().(client.Object)
 
 	if err := h.Client.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
 		h.Log.Error(err, "failed to get object", "object", obj)
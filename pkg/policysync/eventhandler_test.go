@@ -0,0 +1,62 @@
+package policysync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// recordingSyncer records the policies it syncs and labels them, as a syncer
+// writing to the policy would
+type recordingSyncer struct {
+	synced []Policy
+}
+
+func (s *recordingSyncer) SyncPolicy(_ context.Context, _ client.Client, policy Policy) error {
+	policy.SetLabels(map[string]string{"synced": "true"})
+	s.synced = append(s.synced, policy)
+	return nil
+}
+
+func (s *recordingSyncer) RemovePolicy(_ context.Context, _ client.Client, policy Policy) error {
+	return s.SyncPolicy(context.Background(), nil, policy)
+}
+
+func TestResourceEventHandler_copiesCachedObject(t *testing.T) {
+	latest := testPolicy("gateway")
+	if err := unstructured.SetNestedField(latest.Object, "latest", "spec", "limits", "name"); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(latest).Build()
+
+	syncer := &recordingSyncer{}
+	handler := &ResourceEventHandler{Log: logr.Discard(), Client: c, Syncer: syncer}
+
+	for _, event := range []func(obj *unstructured.Unstructured){
+		func(obj *unstructured.Unstructured) { handler.OnAdd(obj, false) },
+		func(obj *unstructured.Unstructured) { handler.OnUpdate(obj, obj) },
+		func(obj *unstructured.Unstructured) { handler.OnDelete(obj) },
+	} {
+		cached := testPolicy("gateway")
+		event(cached)
+		if !equality.Semantic.DeepEqual(cached, testPolicy("gateway")) {
+			t.Errorf("expected the cached object not to be modified, got %v", cached.Object)
+		}
+	}
+
+	if len(syncer.synced) != 3 {
+		t.Fatalf("expected 3 policies to be synced, got %d", len(syncer.synced))
+	}
+	for _, policy := range syncer.synced[:2] {
+		if name, _, _ := unstructured.NestedString(policy.(*UnstructuredPolicy).Object, "spec", "limits", "name"); name != "latest" {
+			t.Errorf("expected the latest version of the policy to be synced, got %v", policy.(*UnstructuredPolicy).Object)
+		}
+	}
+}
@@ -0,0 +1,555 @@
+package policysync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+)
+
+const (
+	// SyncedConditionType is the condition of a hub policy reporting whether
+	// it's synced to the clusters of the gateway it targets
+	SyncedConditionType = "Synced"
+
+	// ParentAnnotation references the hub gateway targeted by the synced
+	// policy from its ManifestWork, so that the gateway controller is
+	// notified when the work changes
+	ParentAnnotation = placement.ParentAnnotation
+
+	// ClusterConditionTypePrefix prefixes the name of the cluster in the
+	// type of the condition of a hub policy reporting whether the policy is
+	// enforced on that cluster, e.g. cluster.kuadrant.io/cluster-a
+	ClusterConditionTypePrefix = "cluster.kuadrant.io/"
+
+	// EnforcedConditionType is the condition of a hub policy aggregating
+	// the Enforced condition of the policy on each of its clusters
+	EnforcedConditionType = "Enforced"
+
+	SyncedReason      = "Synced"
+	SyncPendingReason = "SyncPending"
+	SyncFailedReason  = "SyncFailed"
+	NoClustersReason  = "NoClusters"
+
+	EnforcedReason           = "Enforced"
+	NotEnforcedReason        = "NotEnforced"
+	EnforcementPendingReason = "EnforcementPending"
+
+	conditionsFeedback = "conditions"
+)
+
+// ClusterStatus is the status of a synced policy on one of its clusters, as
+// reported back through ManifestWork status feedback
+type ClusterStatus struct {
+	Cluster    string             `json:"cluster"`
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// DownstreamGatewayFunc returns the clusters a hub gateway is placed on and
+// the name of its downstream gateway on those clusters
+type DownstreamGatewayFunc func(ctx context.Context, c client.Client, gateway *gatewayapiv1.Gateway) ([]string, types.NamespacedName, error)
+
+// ManifestWorkSyncer syncs policies targeting a multi-cluster gateway to its
+// clusters through ManifestWorks, either a ManifestWork per policy and
+// cluster, or the ManifestWorks consolidating the objects of each cluster
+// according to the work mode
+type ManifestWorkSyncer struct {
+	DownstreamGateway DownstreamGatewayFunc
+	WorkMode          placement.WorkMode
+}
+
+var _ Syncer = &ManifestWorkSyncer{}
+
+func NewManifestWorkSyncer(downstreamGateway DownstreamGatewayFunc, workMode placement.WorkMode) *ManifestWorkSyncer {
+	return &ManifestWorkSyncer{
+		DownstreamGateway: downstreamGateway,
+		WorkMode:          workMode,
+	}
+}
+
+// SyncPolicy places the policy, targeting the downstream gateway, onto the
+// clusters of the gateway it targets, and removes it from the clusters it's
+// no longer targeted to. The outcome on each cluster is reported by the
+// Synced condition of the hub policy, and the status of the policy on each
+// cluster by a condition per cluster and the Enforced condition
+func (s *ManifestWorkSyncer) SyncPolicy(ctx context.Context, apiclient client.Client, policy Policy) error {
+	log := crlog.FromContext(ctx)
+
+	gateway, err := targetGateway(ctx, apiclient, policy)
+	if err != nil {
+		return err
+	}
+
+	var clusters []string
+	var downstream types.NamespacedName
+	if gateway != nil {
+		clusters, downstream, err = s.DownstreamGateway(ctx, apiclient, gateway)
+		if err != nil {
+			return err
+		}
+	}
+
+	gvk, err := policyGVK(apiclient, policy)
+	if err != nil {
+		return err
+	}
+	workName := policyWorkName(gvk, policy)
+	placedWorks := placement.NewWorks(apiclient, s.WorkMode)
+
+	works := map[string]*workv1.ManifestWork{}
+	syncErrs := map[string]error{}
+	if len(clusters) > 0 {
+		manifest, err := downstreamPolicyManifest(gvk, policy, downstream)
+		if err != nil {
+			return err
+		}
+		parent, err := cache.MetaNamespaceKeyFunc(gateway)
+		if err != nil {
+			return err
+		}
+		bundle := placement.Bundle{
+			Key:       workName,
+			Parent:    parent,
+			Namespace: downstream.Namespace,
+			Manifests: []workv1.Manifest{manifest},
+			Configs:   []workv1.ManifestConfigOption{policyManifestConfig(gvk, policy.GetName(), downstream.Namespace)},
+		}
+		for _, cluster := range clusters {
+			log.V(3).Info("syncing policy to cluster", "policy", workName, "cluster", cluster)
+			work, err := placedWorks.Apply(ctx, cluster, bundle)
+			if err != nil {
+				syncErrs[cluster] = err
+				continue
+			}
+			works[cluster] = work
+		}
+	}
+
+	removed, err := removeWorks(ctx, placedWorks, workName, sets.New(clusters...))
+	if err != nil {
+		return err
+	}
+	if err := reconcileRBAC(ctx, apiclient, removed.Insert(clusters...)); err != nil {
+		return err
+	}
+
+	// only policies targeting a multi-cluster gateway report their sync status
+	if gateway == nil {
+		return nil
+	}
+	applied := map[string]*metav1.Condition{}
+	feedback := map[string][]workv1.ManifestCondition{}
+	for cluster, work := range works {
+		applied[cluster] = placedWorks.AppliedCondition(work, workName)
+		feedback[cluster] = placedWorks.ManifestStatuses(work, workName)
+	}
+	statuses, err := clusterStatuses(clusters, feedback)
+	if err != nil {
+		return err
+	}
+	conditions := []metav1.Condition{
+		syncedCondition(policy.GetGeneration(), clusters, applied, syncErrs),
+		enforcedCondition(policy.GetGeneration(), statuses),
+	}
+	for _, status := range statuses {
+		conditions = append(conditions, clusterCondition(policy.GetGeneration(), status))
+	}
+	return updateStatus(ctx, apiclient, policy, conditions)
+}
+
+// RemovePolicy removes the policy from every cluster it was synced to
+func (s *ManifestWorkSyncer) RemovePolicy(ctx context.Context, apiclient client.Client, policy Policy) error {
+	gvk, err := policyGVK(apiclient, policy)
+	if err != nil {
+		return err
+	}
+	removed, err := removeWorks(ctx, placement.NewWorks(apiclient, s.WorkMode), policyWorkName(gvk, policy), sets.New[string]())
+	if err != nil {
+		return err
+	}
+	return reconcileRBAC(ctx, apiclient, removed)
+}
+
+// targetGateway returns the gateway targeted by the policy, or nil when it
+// doesn't target an existing gateway
+func targetGateway(ctx context.Context, apiclient client.Client, policy Policy) (*gatewayapiv1.Gateway, error) {
+	targetRef := policy.GetTargetRef()
+	if targetRef == nil || string(targetRef.Group) != gatewayapiv1.GroupName || string(targetRef.Kind) != "Gateway" {
+		return nil, nil
+	}
+	namespace := policy.GetNamespace()
+	if targetRef.Namespace != nil {
+		namespace = string(*targetRef.Namespace)
+	}
+
+	gateway := &gatewayapiv1.Gateway{}
+	if err := apiclient.Get(ctx, client.ObjectKey{Name: string(targetRef.Name), Namespace: namespace}, gateway); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return gateway, nil
+}
+
+func policyGVK(apiclient client.Client, policy Policy) (schema.GroupVersionKind, error) {
+	obj, ok := policy.(runtime.Object)
+	if !ok {
+		return schema.GroupVersionKind{}, fmt.Errorf("policy %s/%s is not a runtime object", policy.GetNamespace(), policy.GetName())
+	}
+	return apiutil.GVKForObject(obj, apiclient.Scheme())
+}
+
+// policyWorkName names the ManifestWork of the policy by its kind and a hash
+// of its namespace and name. Joining the namespace and name, which may both
+// contain dashes, would name the works of different policies the same, and
+// the hash keeps the name short enough to label the work with
+func policyWorkName(gvk schema.GroupVersionKind, policy Policy) string {
+	sum := sha256.Sum256([]byte(policy.GetNamespace() + "/" + policy.GetName()))
+	return fmt.Sprintf("%s-%s", strings.ToLower(gvk.Kind), hex.EncodeToString(sum[:])[:16])
+}
+
+// policyManifestConfig requests the conditions of the synced policy to be
+// fed back into the status of its ManifestWork
+func policyManifestConfig(gvk schema.GroupVersionKind, name, namespace string) workv1.ManifestConfigOption {
+	resource, _ := meta.UnsafeGuessKindToResource(gvk)
+	return workv1.ManifestConfigOption{
+		ResourceIdentifier: workv1.ResourceIdentifier{
+			Group:     gvk.Group,
+			Resource:  resource.Resource,
+			Name:      name,
+			Namespace: namespace,
+		},
+		FeedbackRules: []workv1.FeedbackRule{
+			{
+				Type: workv1.JSONPathsType,
+				JsonPaths: []workv1.JsonPath{
+					{
+						Name: conditionsFeedback,
+						Path: ".status.conditions",
+					},
+				},
+			},
+		},
+	}
+}
+
+// downstreamPolicyManifest returns the manifest of the policy to sync to the
+// clusters, in the namespace of the downstream gateway and targeting it
+func downstreamPolicyManifest(gvk schema.GroupVersionKind, policy Policy, downstream types.NamespacedName) (workv1.Manifest, error) {
+	obj, ok := policy.(runtime.Object)
+	if !ok {
+		return workv1.Manifest{}, fmt.Errorf("policy %s/%s is not a runtime object", policy.GetNamespace(), policy.GetName())
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj.DeepCopyObject())
+	if err != nil {
+		return workv1.Manifest{}, err
+	}
+
+	downstreamPolicy := &UnstructuredPolicy{Unstructured: &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": content["spec"],
+	}}}
+	downstreamPolicy.SetGroupVersionKind(gvk)
+	downstreamPolicy.SetName(policy.GetName())
+	downstreamPolicy.SetNamespace(downstream.Namespace)
+	downstreamPolicy.SetLabels(policy.GetLabels())
+	downstreamPolicy.UpdateTargetRef(func(targetRef *gatewayapiv1alpha2.PolicyTargetReference) {
+		namespace := gatewayapiv1.Namespace(downstream.Namespace)
+		targetRef.Name = gatewayapiv1.ObjectName(downstream.Name)
+		targetRef.Namespace = &namespace
+	})
+
+	raw, err := json.Marshal(downstreamPolicy.Object)
+	if err != nil {
+		return workv1.Manifest{}, err
+	}
+	return workv1.Manifest{RawExtension: runtime.RawExtension{Raw: raw}}, nil
+}
+
+// removeWorks removes the policy from the clusters other than the ones to
+// keep, returning the clusters it was removed from
+func removeWorks(ctx context.Context, works *placement.Works, workName string, keep sets.Set[string]) (sets.Set[string], error) {
+	removed := sets.New[string]()
+	clusters, err := works.Clusters(ctx, workName)
+	if err != nil {
+		return removed, err
+	}
+	for cluster := range clusters {
+		if keep.Has(cluster) {
+			continue
+		}
+		crlog.FromContext(ctx).V(3).Info("removing policy from cluster", "policy", workName, "cluster", cluster)
+		if err := works.Remove(ctx, cluster, workName, true); err != nil {
+			return removed, err
+		}
+		removed.Insert(cluster)
+	}
+	return removed, nil
+}
+
+// reconcileRBAC grants the work agent of the clusters access to the policies
+// synced to them, and revokes it from the clusters with no policy or gateway
+// left
+func reconcileRBAC(ctx context.Context, apiclient client.Client, clusters sets.Set[string]) error {
+	for _, cluster := range sets.List(clusters) {
+		if err := placement.ReconcileClusterRBAC(ctx, apiclient, cluster); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncedCondition reports the policy as synced when it's applied on every
+// cluster, naming the clusters where it's not otherwise
+func syncedCondition(generation int64, clusters []string, applied map[string]*metav1.Condition, syncErrs map[string]error) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               SyncedConditionType,
+		ObservedGeneration: generation,
+	}
+	if len(clusters) == 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = NoClustersReason
+		condition.Message = "target gateway is not placed on any cluster"
+		return condition
+	}
+
+	sorted := append([]string{}, clusters...)
+	sort.Strings(sorted)
+
+	failed := []string{}
+	pending := []string{}
+	for _, cluster := range sorted {
+		if err, ok := syncErrs[cluster]; ok {
+			failed = append(failed, fmt.Sprintf("%s: %s", cluster, err))
+			continue
+		}
+		switch condition := applied[cluster]; {
+		case condition == nil || condition.Status == metav1.ConditionUnknown:
+			pending = append(pending, fmt.Sprintf("%s: waiting to be applied", cluster))
+		case condition.Status == metav1.ConditionFalse:
+			failed = append(failed, fmt.Sprintf("%s: not applied %s (%s)", cluster, condition.Reason, condition.Message))
+		}
+	}
+
+	switch {
+	case len(failed) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = SyncFailedReason
+		condition.Message = strings.Join(append(failed, pending...), "; ")
+	case len(pending) > 0:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = SyncPendingReason
+		condition.Message = strings.Join(pending, "; ")
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = SyncedReason
+		condition.Message = fmt.Sprintf("policy synced to clusters %v", sorted)
+	}
+	return condition
+}
+
+// clusterStatuses returns the status of the policy on each cluster, from the
+// conditions fed back into the status of its manifest on the cluster
+func clusterStatuses(clusters []string, feedback map[string][]workv1.ManifestCondition) ([]ClusterStatus, error) {
+	sorted := append([]string{}, clusters...)
+	sort.Strings(sorted)
+
+	statuses := make([]ClusterStatus, 0, len(sorted))
+	for _, cluster := range sorted {
+		status := ClusterStatus{Cluster: cluster}
+		if manifests, ok := feedback[cluster]; ok {
+			for _, m := range manifests {
+				for _, value := range m.StatusFeedbacks.Values {
+					if value.Name != conditionsFeedback || value.Value.JsonRaw == nil {
+						continue
+					}
+					if err := json.Unmarshal([]byte(*value.Value.JsonRaw), &status.Conditions); err != nil {
+						return nil, fmt.Errorf("invalid %s status feedback from cluster %s: %w", value.Name, cluster, err)
+					}
+				}
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// enforcedCondition reports the policy as enforced when it's enforced on
+// every cluster. A policy rejected or not enforced on any cluster makes it
+// False, naming each cluster and its reasons, and a cluster that hasn't
+// reported the status of the policy yet makes it Unknown
+func enforcedCondition(generation int64, statuses []ClusterStatus) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               EnforcedConditionType,
+		ObservedGeneration: generation,
+	}
+	if len(statuses) == 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = NoClustersReason
+		condition.Message = "target gateway is not placed on any cluster"
+		return condition
+	}
+
+	notEnforced := []string{}
+	unknown := []string{}
+	enforced := []string{}
+	for _, status := range statuses {
+		cluster := clusterCondition(generation, status)
+		switch cluster.Status {
+		case metav1.ConditionFalse:
+			notEnforced = append(notEnforced, fmt.Sprintf("%s: %s", status.Cluster, cluster.Message))
+		case metav1.ConditionUnknown:
+			unknown = append(unknown, fmt.Sprintf("%s: %s", status.Cluster, cluster.Message))
+		default:
+			enforced = append(enforced, status.Cluster)
+		}
+	}
+
+	switch {
+	case len(notEnforced) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = NotEnforcedReason
+		condition.Message = strings.Join(append(notEnforced, unknown...), "; ")
+	case len(unknown) > 0:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = EnforcementPendingReason
+		condition.Message = strings.Join(unknown, "; ")
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = EnforcedReason
+		condition.Message = fmt.Sprintf("policy enforced on clusters %v", enforced)
+	}
+	return condition
+}
+
+// clusterCondition reports whether the policy is enforced on a cluster, from
+// the conditions of the policy fed back by the cluster. It's False when the
+// policy is rejected or not enforced there, listing the reasons, and Unknown
+// until the cluster reports the status of the policy
+func clusterCondition(generation int64, status ClusterStatus) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               ClusterConditionTypePrefix + status.Cluster,
+		ObservedGeneration: generation,
+	}
+
+	problems := []string{}
+	if accepted := meta.FindStatusCondition(status.Conditions, "Accepted"); accepted != nil && accepted.Status == metav1.ConditionFalse {
+		problems = append(problems, conditionProblem("not Accepted", accepted))
+	}
+	downstream := meta.FindStatusCondition(status.Conditions, EnforcedConditionType)
+	switch {
+	case downstream != nil && downstream.Status == metav1.ConditionFalse:
+		problems = append(problems, conditionProblem("not "+EnforcedConditionType, downstream))
+	case len(problems) == 0 && (downstream == nil || downstream.Status == metav1.ConditionUnknown):
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = EnforcementPendingReason
+		condition.Message = fmt.Sprintf("%s not reported", EnforcedConditionType)
+		return condition
+	}
+
+	if len(problems) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = NotEnforcedReason
+		condition.Message = strings.Join(problems, ", ")
+		return condition
+	}
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = EnforcedReason
+	condition.Message = downstream.Message
+	return condition
+}
+
+func conditionProblem(prefix string, condition *metav1.Condition) string {
+	problem := fmt.Sprintf("%s %s", prefix, condition.Reason)
+	if condition.Message != "" {
+		problem += fmt.Sprintf(" (%s)", condition.Message)
+	}
+	return problem
+}
+
+// updateStatus sets the conditions in the status of the hub policy when they
+// changed, removing the conditions of the clusters the policy is no longer
+// synced to
+func updateStatus(ctx context.Context, apiclient client.Client, policy Policy, newConditions []metav1.Condition) error {
+	obj, ok := policy.(*UnstructuredPolicy)
+	if !ok {
+		return nil
+	}
+
+	rawConditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return err
+	}
+	conditions := []metav1.Condition{}
+	for _, rawCondition := range rawConditions {
+		rawMap, ok := rawCondition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		c := metav1.Condition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawMap, &c); err != nil {
+			return err
+		}
+		conditions = append(conditions, c)
+	}
+
+	changed := false
+	for _, condition := range slices.Clone(conditions) {
+		if strings.HasPrefix(condition.Type, ClusterConditionTypePrefix) && meta.FindStatusCondition(newConditions, condition.Type) == nil {
+			meta.RemoveStatusCondition(&conditions, condition.Type)
+			changed = true
+		}
+	}
+	for _, condition := range newConditions {
+		previous := meta.FindStatusCondition(conditions, condition.Type)
+		if previous != nil && previous.Status == condition.Status && previous.Reason == condition.Reason &&
+			previous.Message == condition.Message && previous.ObservedGeneration == condition.ObservedGeneration {
+			continue
+		}
+		meta.SetStatusCondition(&conditions, condition)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	updated := make([]interface{}, 0, len(conditions))
+	for i := range conditions {
+		c, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&conditions[i])
+		if err != nil {
+			return err
+		}
+		updated = append(updated, c)
+	}
+	// the policy read back from the update has a null status until set
+	if _, ok := obj.Object["status"].(map[string]interface{}); !ok {
+		obj.Object["status"] = map[string]interface{}{}
+	}
+	if err := unstructured.SetNestedSlice(obj.Object, updated, "status", "conditions"); err != nil {
+		return err
+	}
+	return apiclient.Status().Update(ctx, obj.Unstructured)
+}
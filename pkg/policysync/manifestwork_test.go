@@ -0,0 +1,417 @@
+package policysync
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+)
+
+var testPolicyGVK = schema.GroupVersionKind{Group: "kuadrant.io", Version: "v1beta2", Kind: "RateLimitPolicy"}
+
+func testPolicy(target string) *unstructured.Unstructured {
+	policy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"targetRef": map[string]interface{}{
+				"group": gatewayapiv1.GroupName,
+				"kind":  "Gateway",
+				"name":  target,
+			},
+			"limits": map[string]interface{}{},
+		},
+	}}
+	policy.SetGroupVersionKind(testPolicyGVK)
+	policy.SetName("policy")
+	policy.SetNamespace("test")
+	return policy
+}
+
+func TestManifestWorkSyncer(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, gatewayapiv1.AddToScheme, workv1.AddToScheme} {
+		if err := addToScheme(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gateway := &gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "test"}}
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(gateway, testPolicy("gateway")).
+		WithStatusSubresource(testPolicy("gateway")).
+		Build()
+
+	clusters := []string{"cluster-a", "cluster-b"}
+	syncer := NewManifestWorkSyncer(func(_ context.Context, _ client.Client, _ *gatewayapiv1.Gateway) ([]string, types.NamespacedName, error) {
+		return clusters, types.NamespacedName{Name: "gateway", Namespace: "kuadrant-test"}, nil
+	}, placement.WorkPerObject)
+
+	sync := func() *unstructured.Unstructured {
+		t.Helper()
+		obj := testPolicy("")
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		policy, err := NewPolicyFor(obj)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if err := syncer.SyncPolicy(ctx, c, policy); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		return obj
+	}
+
+	testWorkPolicy, err := NewPolicyFor(testPolicy(""))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	workName := policyWorkName(testPolicyGVK, testWorkPolicy)
+
+	// synced to every cluster of the gateway, targeting the downstream gateway
+	obj := sync()
+	works := &workv1.ManifestWorkList{}
+	if err := c.List(ctx, works, client.MatchingLabels{placement.WorkManifestLabel: workName}); err != nil || len(works.Items) != 2 {
+		t.Fatalf("expected a manifest work per cluster, got %v (%v)", works.Items, err)
+	}
+	synced := &unstructured.Unstructured{}
+	if err := json.Unmarshal(works.Items[0].Spec.Workload.Manifests[0].Raw, &synced.Object); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	targetRef, _, _ := unstructured.NestedStringMap(synced.Object, "spec", "targetRef")
+	if synced.GetNamespace() != "kuadrant-test" || targetRef["name"] != "gateway" || targetRef["namespace"] != "kuadrant-test" {
+		t.Errorf("expected the policy to target the downstream gateway, got %v in %s", targetRef, synced.GetNamespace())
+	}
+	if _, ok := synced.Object["status"]; ok {
+		t.Errorf("expected the synced policy not to have a status")
+	}
+	if configs := works.Items[0].Spec.ManifestConfigs; len(configs) != 1 || configs[0].ResourceIdentifier.Resource != "ratelimitpolicies" ||
+		configs[0].ResourceIdentifier.Namespace != "kuadrant-test" || configs[0].FeedbackRules[0].JsonPaths[0].Path != ".status.conditions" {
+		t.Errorf("expected the conditions of the synced policy to be fed back, got %v", configs)
+	}
+	rbacWork := &workv1.ManifestWork{}
+	if err := c.Get(ctx, client.ObjectKey{Name: "gateway-rbac", Namespace: "cluster-a"}, rbacWork); err != nil {
+		t.Errorf("expected the work agent to be granted access to the policy, got %s", err)
+	} else if !strings.Contains(string(rbacWork.Spec.Workload.Manifests[0].Raw), "ratelimitpolicies") {
+		t.Errorf("expected the cluster role to include the policy resource, got %s", rbacWork.Spec.Workload.Manifests[0].Raw)
+	}
+	assertSyncedCondition(t, obj, metav1.ConditionUnknown, "cluster-a: waiting to be applied; cluster-b: waiting to be applied")
+
+	// the status of each cluster is reported
+	for i, status := range []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse} {
+		works.Items[i].Status.Conditions = []metav1.Condition{{Type: workv1.WorkApplied, Status: status, Reason: "Test", LastTransitionTime: metav1.Now()}}
+		if err := c.Update(ctx, &works.Items[i]); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+	obj = sync()
+	assertSyncedCondition(t, obj, metav1.ConditionFalse, "cluster-b: not applied Test ()")
+
+	// the status of the policy on each cluster is fed back
+	feedback := `[{"type":"Enforced","status":"True","reason":"Enforced","message":"enforced","lastTransitionTime":"2024-01-01T00:00:00Z"}]`
+	works.Items[0].Status.ResourceStatus.Manifests = []workv1.ManifestCondition{
+		{
+			ResourceMeta: workv1.ManifestResourceMeta{Group: "kuadrant.io", Resource: "ratelimitpolicies", Name: "policy", Namespace: "kuadrant-test"},
+			StatusFeedbacks: workv1.StatusFeedbackResult{Values: []workv1.FeedbackValue{
+				{Name: "conditions", Value: workv1.FieldValue{Type: workv1.JsonRaw, JsonRaw: &feedback}},
+			}},
+		},
+	}
+	if err := c.Update(ctx, &works.Items[0]); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	obj = sync()
+	assertPolicyCondition(t, obj, EnforcedConditionType, metav1.ConditionUnknown, "cluster-b: Enforced not reported")
+	assertPolicyCondition(t, obj, ClusterConditionTypePrefix+"cluster-a", metav1.ConditionTrue, "enforced")
+	assertPolicyCondition(t, obj, ClusterConditionTypePrefix+"cluster-b", metav1.ConditionUnknown, "Enforced not reported")
+	if len(obj.GetAnnotations()) != 0 {
+		t.Errorf("expected the status of each cluster only to be set in the status of the policy, got annotations %v", obj.GetAnnotations())
+	}
+
+	// unchanged statuses aren't written again
+	resourceVersion := obj.GetResourceVersion()
+	if obj = sync(); obj.GetResourceVersion() != resourceVersion {
+		t.Errorf("expected the policy not to be updated, got resource version %s from %s", obj.GetResourceVersion(), resourceVersion)
+	}
+
+	// removed from the clusters the gateway is no longer placed on
+	clusters = []string{"cluster-a"}
+	obj = sync()
+	if err := c.List(ctx, works, client.MatchingLabels{placement.WorkManifestLabel: workName}); err != nil || len(works.Items) != 1 || works.Items[0].Namespace != "cluster-a" {
+		t.Fatalf("expected the policy to only remain on cluster-a, got %v (%v)", works.Items, err)
+	}
+	assertSyncedCondition(t, obj, metav1.ConditionTrue, "policy synced to clusters [cluster-a]")
+	assertPolicyCondition(t, obj, EnforcedConditionType, metav1.ConditionTrue, "policy enforced on clusters [cluster-a]")
+	rawConditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, rawCondition := range rawConditions {
+		if conditionType := rawCondition.(map[string]interface{})["type"]; conditionType == ClusterConditionTypePrefix+"cluster-b" {
+			t.Errorf("expected the condition of cluster-b to be removed, got %v", rawConditions)
+		}
+	}
+
+	// removed from every cluster when retargeted to a gateway that isn't placed
+	if err := unstructured.SetNestedField(obj.Object, "other", "spec", "targetRef", "name"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Update(ctx, obj); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	sync()
+	if err := c.List(ctx, works); err != nil || len(works.Items) != 0 {
+		t.Fatalf("expected the policy to be removed from every cluster, got %v (%v)", works.Items, err)
+	}
+
+	// removed from every cluster when deleted
+	if err := unstructured.SetNestedField(obj.Object, "gateway", "spec", "targetRef", "name"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Update(ctx, obj); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	sync()
+	policy, _ := NewPolicyFor(obj)
+	if err := syncer.RemovePolicy(ctx, c, policy); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := c.List(ctx, works); err != nil || len(works.Items) != 0 {
+		t.Fatalf("expected the policy to be removed from every cluster, got %v (%v)", works.Items, err)
+	}
+}
+
+func TestManifestWorkSyncerConsolidated(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, gatewayapiv1.AddToScheme, workv1.AddToScheme} {
+		if err := addToScheme(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gateway := &gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "test"}}
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(gateway, testPolicy("gateway")).
+		WithStatusSubresource(testPolicy("gateway")).
+		Build()
+
+	// the gateway is already placed in the ManifestWork of the cluster
+	works := placement.NewWorks(c, placement.WorkPerCluster)
+	if _, err := works.Apply(ctx, "cluster-a", placement.Bundle{
+		Key:       "gateway-test-gateway",
+		Parent:    "test/gateway",
+		Namespace: "kuadrant-test",
+		Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{
+			Raw: []byte(`{"apiVersion":"gateway.networking.k8s.io/v1","kind":"Gateway","metadata":{"name":"gateway","namespace":"kuadrant-test"}}`),
+		}}},
+	}); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	syncer := NewManifestWorkSyncer(func(_ context.Context, _ client.Client, _ *gatewayapiv1.Gateway) ([]string, types.NamespacedName, error) {
+		return []string{"cluster-a"}, types.NamespacedName{Name: "gateway", Namespace: "kuadrant-test"}, nil
+	}, placement.WorkPerCluster)
+	sync := func() *unstructured.Unstructured {
+		t.Helper()
+		obj := testPolicy("")
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		policy, err := NewPolicyFor(obj)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if err := syncer.SyncPolicy(ctx, c, policy); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		return obj
+	}
+
+	// synced into the ManifestWork of the cluster, along with the gateway
+	sync()
+	work := &workv1.ManifestWork{}
+	if err := c.Get(ctx, client.ObjectKey{Name: "kuadrant", Namespace: "cluster-a"}, work); err != nil {
+		t.Fatalf("expected the consolidated manifest work, got %s", err)
+	}
+	if len(work.Spec.Workload.Manifests) != 2 || len(work.Spec.ManifestConfigs) != 1 {
+		t.Fatalf("expected the gateway and the policy in the manifest work, got %v", work.Spec)
+	}
+	if parents := placement.WorkParents(work); len(parents) != 1 || parents[0] != "test/gateway" {
+		t.Errorf("expected the gateway to be requeued when the manifest work changes, got %v", parents)
+	}
+
+	// only the status of the policy manifest is mapped to the policy
+	feedback := `[{"type":"Enforced","status":"True","reason":"Enforced","message":"enforced","lastTransitionTime":"2024-01-01T00:00:00Z"}]`
+	applied := []metav1.Condition{{Type: "Applied", Status: metav1.ConditionTrue, Reason: "Test", LastTransitionTime: metav1.Now()}}
+	work.Status.Conditions = []metav1.Condition{{Type: workv1.WorkApplied, Status: metav1.ConditionFalse, Reason: "Test", LastTransitionTime: metav1.Now()}}
+	work.Status.ResourceStatus.Manifests = []workv1.ManifestCondition{
+		{
+			ResourceMeta: workv1.ManifestResourceMeta{Group: "gateway.networking.k8s.io", Kind: "Gateway", Name: "gateway", Namespace: "kuadrant-test"},
+			Conditions:   []metav1.Condition{{Type: "Applied", Status: metav1.ConditionFalse, Reason: "Test", LastTransitionTime: metav1.Now()}},
+		},
+		{
+			ResourceMeta: workv1.ManifestResourceMeta{Group: "kuadrant.io", Kind: "RateLimitPolicy", Resource: "ratelimitpolicies", Name: "policy", Namespace: "kuadrant-test"},
+			Conditions:   applied,
+			StatusFeedbacks: workv1.StatusFeedbackResult{Values: []workv1.FeedbackValue{
+				{Name: "conditions", Value: workv1.FieldValue{Type: workv1.JsonRaw, JsonRaw: &feedback}},
+			}},
+		},
+	}
+	if err := c.Update(ctx, work); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	obj := sync()
+	assertSyncedCondition(t, obj, metav1.ConditionTrue, "policy synced to clusters [cluster-a]")
+	assertPolicyCondition(t, obj, EnforcedConditionType, metav1.ConditionTrue, "policy enforced on clusters [cluster-a]")
+
+	// removing the policy leaves the gateway in place
+	policy, _ := NewPolicyFor(obj)
+	if err := syncer.RemovePolicy(ctx, c, policy); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Name: "kuadrant", Namespace: "cluster-a"}, work); err != nil {
+		t.Fatalf("expected the consolidated manifest work to remain, got %s", err)
+	}
+	if len(work.Spec.Workload.Manifests) != 1 || len(work.Spec.ManifestConfigs) != 0 {
+		t.Errorf("expected only the gateway to remain in the manifest work, got %v", work.Spec)
+	}
+}
+
+func Test_enforcedCondition(t *testing.T) {
+	enforced := metav1.Condition{Type: EnforcedConditionType, Status: metav1.ConditionTrue, Reason: "Enforced"}
+
+	testCases := []struct {
+		name            string
+		statuses        []ClusterStatus
+		expectedStatus  metav1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:            "no clusters",
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  NoClustersReason,
+			expectedMessage: "target gateway is not placed on any cluster",
+		},
+		{
+			name: "enforced on every cluster",
+			statuses: []ClusterStatus{
+				{Cluster: "cluster-a", Conditions: []metav1.Condition{enforced}},
+				{Cluster: "cluster-b", Conditions: []metav1.Condition{enforced}},
+			},
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  EnforcedReason,
+			expectedMessage: "policy enforced on clusters [cluster-a cluster-b]",
+		},
+		{
+			name: "rejected on a cluster",
+			statuses: []ClusterStatus{
+				{Cluster: "cluster-a", Conditions: []metav1.Condition{enforced}},
+				{Cluster: "cluster-b", Conditions: []metav1.Condition{
+					{Type: "Accepted", Status: metav1.ConditionFalse, Reason: "Conflicted", Message: "already targeted"},
+				}},
+				{Cluster: "cluster-c"},
+			},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  NotEnforcedReason,
+			expectedMessage: "cluster-b: not Accepted Conflicted (already targeted); cluster-c: Enforced not reported",
+		},
+		{
+			name: "not enforced on a cluster",
+			statuses: []ClusterStatus{
+				{Cluster: "cluster-a", Conditions: []metav1.Condition{
+					{Type: EnforcedConditionType, Status: metav1.ConditionFalse, Reason: "Unknown"},
+				}},
+			},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  NotEnforcedReason,
+			expectedMessage: "cluster-a: not Enforced Unknown",
+		},
+		{
+			name: "not reported",
+			statuses: []ClusterStatus{
+				{Cluster: "cluster-a", Conditions: []metav1.Condition{enforced}},
+				{Cluster: "cluster-b", Conditions: []metav1.Condition{{Type: EnforcedConditionType, Status: metav1.ConditionUnknown}}},
+			},
+			expectedStatus:  metav1.ConditionUnknown,
+			expectedReason:  EnforcementPendingReason,
+			expectedMessage: "cluster-b: Enforced not reported",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := enforcedCondition(1, testCase.statuses)
+			if got.Status != testCase.expectedStatus || got.Reason != testCase.expectedReason || got.Message != testCase.expectedMessage {
+				t.Errorf("expected %s %s %q, got %s %s %q", testCase.expectedStatus, testCase.expectedReason, testCase.expectedMessage, got.Status, got.Reason, got.Message)
+			}
+		})
+	}
+}
+
+func assertSyncedCondition(t *testing.T, obj *unstructured.Unstructured, status metav1.ConditionStatus, message string) {
+	t.Helper()
+	assertPolicyCondition(t, obj, SyncedConditionType, status, message)
+}
+
+func assertPolicyCondition(t *testing.T, obj *unstructured.Unstructured, conditionType string, status metav1.ConditionStatus, message string) {
+	t.Helper()
+	rawConditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	conditions := []metav1.Condition{}
+	for _, rawCondition := range rawConditions {
+		condition := metav1.Condition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawCondition.(map[string]interface{}), &condition); err != nil {
+			t.Fatal(err)
+		}
+		conditions = append(conditions, condition)
+	}
+	condition := meta.FindStatusCondition(conditions, conditionType)
+	if condition == nil {
+		t.Fatalf("expected a %s condition, got %v", conditionType, conditions)
+	}
+	if condition.Status != status || !strings.Contains(condition.Message, message) {
+		t.Errorf("expected %s condition %s %q, got %s %q", conditionType, status, message, condition.Status, condition.Message)
+	}
+}
+
+func TestPolicyWorkName(t *testing.T) {
+	policy := func(namespace, name string) Policy {
+		obj := testPolicy("")
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		p, err := NewPolicyFor(obj)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		return p
+	}
+
+	a := policyWorkName(testPolicyGVK, policy("a-b", "c"))
+	b := policyWorkName(testPolicyGVK, policy("a", "b-c"))
+	if a == b {
+		t.Errorf("expected policies a-b/c and a/b-c to have different work names, got %s", a)
+	}
+	if !strings.HasPrefix(a, "ratelimitpolicy-") {
+		t.Errorf("expected the work name to start with the kind, got %s", a)
+	}
+	if long := policyWorkName(testPolicyGVK, policy(strings.Repeat("n", 63), strings.Repeat("p", 253))); len(long) > 63 {
+		t.Errorf("expected the work name to fit in a label value, got %s", long)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The MultiCluster Traffic Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storageversion migrates the stored objects of a CustomResourceDefinition to its
+// current storage version, so that old versions can be removed from status.storedVersions and
+// eventually stop being served.
+package storageversion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var ErrNoStorageVersion = errors.New("no storage version")
+
+// KuadrantCRDs are the CustomResourceDefinitions served in more than one version by the policy controller.
+var KuadrantCRDs = []string{
+	"dnspolicies.kuadrant.io",
+	"tlspolicies.kuadrant.io",
+	"dnsrecords.kuadrant.io",
+	"managedzones.kuadrant.io",
+	"dnshealthcheckprobes.kuadrant.io",
+}
+
+type Migrator struct {
+	Client client.Client
+	// PageSize is the number of objects listed per request. Defaults to 500.
+	PageSize int64
+}
+
+// Migrate rewrites every object of the named CRD so that the API server persists it in the
+// current storage version, then sets status.storedVersions to only contain that version.
+// Objects are written back unchanged; the API server performs the conversion.
+func (m *Migrator) Migrate(ctx context.Context, crdName string) error {
+	logger := log.FromContext(ctx).WithValues("crd", crdName)
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := m.Client.Get(ctx, client.ObjectKey{Name: crdName}, crd); err != nil {
+		return err
+	}
+
+	storageVersion := ""
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			storageVersion = version.Name
+		}
+	}
+	if storageVersion == "" {
+		return fmt.Errorf("%w : crd %s", ErrNoStorageVersion, crdName)
+	}
+
+	if len(crd.Status.StoredVersions) == 1 && crd.Status.StoredVersions[0] == storageVersion {
+		logger.Info("objects already stored in storage version, nothing to migrate", "version", storageVersion)
+		return nil
+	}
+
+	gvk := schema.GroupVersionKind{
+		Group:   crd.Spec.Group,
+		Version: storageVersion,
+		Kind:    crd.Spec.Names.ListKind,
+	}
+	pageSize := m.PageSize
+	if pageSize == 0 {
+		pageSize = 500
+	}
+
+	migrated := 0
+	continueToken := ""
+	for {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+		if err := m.Client.List(ctx, list, client.Limit(pageSize), client.Continue(continueToken)); err != nil {
+			return err
+		}
+		for i := range list.Items {
+			if err := m.migrateObject(ctx, &list.Items[i]); err != nil {
+				return fmt.Errorf("failed to migrate %s %s/%s: %w", crd.Spec.Names.Kind, list.Items[i].GetNamespace(), list.Items[i].GetName(), err)
+			}
+			migrated++
+		}
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			break
+		}
+	}
+	logger.Info("migrated objects to storage version", "version", storageVersion, "count", migrated)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := m.Client.Get(ctx, client.ObjectKey{Name: crdName}, crd); err != nil {
+			return err
+		}
+		crd.Status.StoredVersions = []string{storageVersion}
+		return m.Client.Status().Update(ctx, crd)
+	})
+}
+
+// migrateObject performs a no-op update of obj, re-reading it on conflict. Objects deleted in
+// the meantime are skipped.
+func (m *Migrator) migrateObject(ctx context.Context, obj *unstructured.Unstructured) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := m.Client.Update(ctx, obj)
+		if k8serrors.IsConflict(err) {
+			if getErr := m.Client.Get(ctx, client.ObjectKeyFromObject(obj), obj); getErr != nil {
+				return getErr
+			}
+		}
+		return err
+	})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+//go:build unit
+
+package storageversion
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1beta1"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func TestMigrator_Migrate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{apiextensionsv1.AddToScheme, v1alpha1.AddToScheme, v1beta1.AddToScheme} {
+		if err := addToScheme(scheme); err != nil {
+			t.Fatalf("failed to add scheme %s", err)
+		}
+	}
+
+	crd := func(storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "dnspolicies.kuadrant.io"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "kuadrant.io",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "DNSPolicy", ListKind: "DNSPolicyList"},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1alpha1", Served: true},
+					{Name: "v1beta1", Served: true, Storage: true},
+				},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+		}
+	}
+	policy := &v1beta1.DNSPolicy{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", ResourceVersion: "1"}}
+
+	testCases := []struct {
+		name                   string
+		crd                    *apiextensionsv1.CustomResourceDefinition
+		expectedStoredVersions []string
+		expectMigrated         bool
+		Assert                 func(t *testing.T, err error)
+	}{
+		{
+			name:                   "migrates objects and prunes stored versions",
+			crd:                    crd("v1alpha1", "v1beta1"),
+			expectedStoredVersions: []string{"v1beta1"},
+			expectMigrated:         true,
+			Assert:                 testutil.AssertError(""),
+		},
+		{
+			name:                   "nothing to do when already migrated",
+			crd:                    crd("v1beta1"),
+			expectedStoredVersions: []string{"v1beta1"},
+			Assert:                 testutil.AssertError(""),
+		},
+		{
+			name: "error when there is no storage version",
+			crd: func() *apiextensionsv1.CustomResourceDefinition {
+				c := crd("v1alpha1")
+				c.Spec.Versions[1].Storage = false
+				return c
+			}(),
+			expectedStoredVersions: []string{"v1alpha1"},
+			Assert:                 testutil.AssertError("no storage version"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(testCase.crd, policy.DeepCopy()).
+				WithStatusSubresource(testCase.crd).
+				Build()
+
+			err := (&Migrator{Client: c}).Migrate(context.TODO(), testCase.crd.Name)
+			testCase.Assert(t, err)
+
+			gotCRD := &apiextensionsv1.CustomResourceDefinition{}
+			if err := c.Get(context.TODO(), client.ObjectKeyFromObject(testCase.crd), gotCRD); err != nil {
+				t.Fatalf("failed to get crd %s", err)
+			}
+			if !reflect.DeepEqual(gotCRD.Status.StoredVersions, testCase.expectedStoredVersions) {
+				t.Errorf("expected stored versions %v, got %v", testCase.expectedStoredVersions, gotCRD.Status.StoredVersions)
+			}
+
+			gotPolicy := &v1beta1.DNSPolicy{}
+			if err := c.Get(context.TODO(), client.ObjectKeyFromObject(policy), gotPolicy); err != nil {
+				t.Fatalf("failed to get policy %s", err)
+			}
+			if migrated := gotPolicy.ResourceVersion != policy.ResourceVersion; migrated != testCase.expectMigrated {
+				t.Errorf("expected migrated %v, resource version %s", testCase.expectMigrated, gotPolicy.ResourceVersion)
+			}
+		})
+	}
+}
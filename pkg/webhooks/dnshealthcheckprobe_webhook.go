@@ -0,0 +1,62 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+)
+
+//+kubebuilder:webhook:path=/mutate-kuadrant-io-v1alpha1-dnshealthcheckprobe,mutating=true,failurePolicy=fail,sideEffects=None,groups=kuadrant.io,resources=dnshealthcheckprobes,verbs=create;update,versions=v1alpha1,name=mdnshealthcheckprobe.kuadrant.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-kuadrant-io-v1alpha1-dnshealthcheckprobe,mutating=false,failurePolicy=fail,sideEffects=None,groups=kuadrant.io,resources=dnshealthcheckprobes,verbs=create;update,versions=v1alpha1,name=vdnshealthcheckprobe.kuadrant.io,admissionReviewVersions=v1
+
+// DNSHealthCheckProbeWebhook defaults and validates DNSHealthCheckProbe resources on admission
+type DNSHealthCheckProbeWebhook struct{}
+
+var _ webhook.CustomDefaulter = &DNSHealthCheckProbeWebhook{}
+var _ webhook.CustomValidator = &DNSHealthCheckProbeWebhook{}
+
+func (w *DNSHealthCheckProbeWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.DNSHealthCheckProbe{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+func (w *DNSHealthCheckProbeWebhook) Default(_ context.Context, obj runtime.Object) error {
+	probe, ok := obj.(*v1alpha1.DNSHealthCheckProbe)
+	if !ok {
+		return fmt.Errorf("expected a DNSHealthCheckProbe but got %T", obj)
+	}
+	probe.Default()
+	return nil
+}
+
+func (w *DNSHealthCheckProbeWebhook) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, validateDNSHealthCheckProbe(obj)
+}
+
+func (w *DNSHealthCheckProbeWebhook) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	if isDeleting(newObj) {
+		return nil, nil
+	}
+	return nil, validateDNSHealthCheckProbe(newObj)
+}
+
+func (w *DNSHealthCheckProbeWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateDNSHealthCheckProbe(obj runtime.Object) error {
+	probe, ok := obj.(*v1alpha1.DNSHealthCheckProbe)
+	if !ok {
+		return fmt.Errorf("expected a DNSHealthCheckProbe but got %T", obj)
+	}
+	return probe.Validate()
+}
@@ -0,0 +1,62 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+)
+
+//+kubebuilder:webhook:path=/mutate-kuadrant-io-v1alpha1-dnspolicy,mutating=true,failurePolicy=fail,sideEffects=None,groups=kuadrant.io,resources=dnspolicies,verbs=create;update,versions=v1alpha1,name=mdnspolicy.kuadrant.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-kuadrant-io-v1alpha1-dnspolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=kuadrant.io,resources=dnspolicies,verbs=create;update,versions=v1alpha1,name=vdnspolicy.kuadrant.io,admissionReviewVersions=v1
+
+// DNSPolicyWebhook defaults and validates DNSPolicy resources on admission
+type DNSPolicyWebhook struct{}
+
+var _ webhook.CustomDefaulter = &DNSPolicyWebhook{}
+var _ webhook.CustomValidator = &DNSPolicyWebhook{}
+
+func (w *DNSPolicyWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.DNSPolicy{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+func (w *DNSPolicyWebhook) Default(_ context.Context, obj runtime.Object) error {
+	dnsPolicy, ok := obj.(*v1alpha1.DNSPolicy)
+	if !ok {
+		return fmt.Errorf("expected a DNSPolicy but got %T", obj)
+	}
+	dnsPolicy.Default()
+	return nil
+}
+
+func (w *DNSPolicyWebhook) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, validateDNSPolicy(obj)
+}
+
+func (w *DNSPolicyWebhook) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	if isDeleting(newObj) {
+		return nil, nil
+	}
+	return nil, validateDNSPolicy(newObj)
+}
+
+func (w *DNSPolicyWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateDNSPolicy(obj runtime.Object) error {
+	dnsPolicy, ok := obj.(*v1alpha1.DNSPolicy)
+	if !ok {
+		return fmt.Errorf("expected a DNSPolicy but got %T", obj)
+	}
+	return dnsPolicy.Validate()
+}
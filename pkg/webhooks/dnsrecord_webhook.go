@@ -0,0 +1,50 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+)
+
+//+kubebuilder:webhook:path=/validate-kuadrant-io-v1alpha1-dnsrecord,mutating=false,failurePolicy=fail,sideEffects=None,groups=kuadrant.io,resources=dnsrecords,verbs=create;update,versions=v1alpha1,name=vdnsrecord.kuadrant.io,admissionReviewVersions=v1
+
+// DNSRecordWebhook validates DNSRecord resources on admission
+type DNSRecordWebhook struct{}
+
+var _ webhook.CustomValidator = &DNSRecordWebhook{}
+
+func (w *DNSRecordWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.DNSRecord{}).
+		WithValidator(w).
+		Complete()
+}
+
+func (w *DNSRecordWebhook) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, validateDNSRecord(obj)
+}
+
+func (w *DNSRecordWebhook) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	if isDeleting(newObj) {
+		return nil, nil
+	}
+	return nil, validateDNSRecord(newObj)
+}
+
+func (w *DNSRecordWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateDNSRecord(obj runtime.Object) error {
+	dnsRecord, ok := obj.(*v1alpha1.DNSRecord)
+	if !ok {
+		return fmt.Errorf("expected a DNSRecord but got %T", obj)
+	}
+	return dnsRecord.Validate()
+}
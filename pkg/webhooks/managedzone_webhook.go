@@ -0,0 +1,70 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+)
+
+//+kubebuilder:webhook:path=/validate-kuadrant-io-v1alpha1-managedzone,mutating=false,failurePolicy=fail,sideEffects=None,groups=kuadrant.io,resources=managedzones,verbs=create;update,versions=v1alpha1,name=vmanagedzone.kuadrant.io,admissionReviewVersions=v1
+
+// ManagedZoneWebhook validates ManagedZone resources on admission
+type ManagedZoneWebhook struct {
+	client.Client
+}
+
+var _ webhook.CustomValidator = &ManagedZoneWebhook{}
+
+func (w *ManagedZoneWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.ManagedZone{}).
+		WithValidator(w).
+		Complete()
+}
+
+func (w *ManagedZoneWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return w.validate(ctx, obj)
+}
+
+func (w *ManagedZoneWebhook) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	if isDeleting(newObj) {
+		return nil, nil
+	}
+	return w.validate(ctx, newObj)
+}
+
+func (w *ManagedZoneWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (w *ManagedZoneWebhook) validate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	managedZone, ok := obj.(*v1alpha1.ManagedZone)
+	if !ok {
+		return nil, fmt.Errorf("expected a ManagedZone but got %T", obj)
+	}
+	if err := managedZone.Validate(); err != nil {
+		return nil, err
+	}
+	if managedZone.Spec.ParentManagedZone == nil {
+		return nil, nil
+	}
+
+	// the parent zone may not exist yet, in which case it can only be checked by the managed zone controller
+	parent := &v1alpha1.ManagedZone{}
+	parentKey := client.ObjectKey{Namespace: managedZone.Namespace, Name: managedZone.Spec.ParentManagedZone.Name}
+	if err := w.Get(ctx, parentKey, parent); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return admission.Warnings{fmt.Sprintf("parent managed zone %s not found", parentKey.Name)}, nil
+		}
+		return nil, err
+	}
+	return nil, managedZone.ValidateParent(parent)
+}
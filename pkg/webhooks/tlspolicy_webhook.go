@@ -0,0 +1,50 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+)
+
+//+kubebuilder:webhook:path=/validate-kuadrant-io-v1alpha1-tlspolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=kuadrant.io,resources=tlspolicies,verbs=create;update,versions=v1alpha1,name=vtlspolicy.kuadrant.io,admissionReviewVersions=v1
+
+// TLSPolicyWebhook validates TLSPolicy resources on admission
+type TLSPolicyWebhook struct{}
+
+var _ webhook.CustomValidator = &TLSPolicyWebhook{}
+
+func (w *TLSPolicyWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.TLSPolicy{}).
+		WithValidator(w).
+		Complete()
+}
+
+func (w *TLSPolicyWebhook) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, validateTLSPolicy(obj)
+}
+
+func (w *TLSPolicyWebhook) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	if isDeleting(newObj) {
+		return nil, nil
+	}
+	return nil, validateTLSPolicy(newObj)
+}
+
+func (w *TLSPolicyWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateTLSPolicy(obj runtime.Object) error {
+	tlsPolicy, ok := obj.(*v1alpha1.TLSPolicy)
+	if !ok {
+		return fmt.Errorf("expected a TLSPolicy but got %T", obj)
+	}
+	return tlsPolicy.Validate()
+}
@@ -0,0 +1,35 @@
+package webhooks
+
+import (
+	k8smeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// SetupWithManager registers the validating and defaulting webhooks for all kuadrant.io resources with the manager
+// webhook server
+func SetupWithManager(mgr ctrl.Manager) error {
+	if err := (&DNSPolicyWebhook{}).SetupWithManager(mgr); err != nil {
+		return err
+	}
+	if err := (&TLSPolicyWebhook{}).SetupWithManager(mgr); err != nil {
+		return err
+	}
+	if err := (&DNSRecordWebhook{}).SetupWithManager(mgr); err != nil {
+		return err
+	}
+	if err := (&ManagedZoneWebhook{Client: mgr.GetClient()}).SetupWithManager(mgr); err != nil {
+		return err
+	}
+	return (&DNSHealthCheckProbeWebhook{}).SetupWithManager(mgr)
+}
+
+// isDeleting returns true if the object is being deleted. Updates to objects that are being deleted are not validated
+// so that finalizers can always be removed, even from objects that would no longer pass validation.
+func isDeleting(obj runtime.Object) bool {
+	objMeta, err := k8smeta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	return objMeta.GetDeletionTimestamp() != nil
+}
@@ -0,0 +1,258 @@
+//go:build unit
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func TestDNSRecordWebhook_ValidateCreate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		endpoints []*v1alpha1.Endpoint
+		Assert    func(t *testing.T, err error)
+	}{
+		{
+			name: "valid loadbalanced record",
+			endpoints: []*v1alpha1.Endpoint{
+				{DNSName: "test.example.com", RecordType: "CNAME", Targets: []string{"lb-a1b2.test.example.com"}},
+				{DNSName: "default.lb-a1b2.test.example.com", RecordType: "CNAME", SetIdentifier: "aws.lb.com", Targets: []string{"aws.lb.com"}},
+				{DNSName: "default.lb-a1b2.test.example.com", RecordType: "CNAME", SetIdentifier: "ab1.lb-a1b2.test.example.com", Targets: []string{"ab1.lb-a1b2.test.example.com"}},
+				{DNSName: "ab1.lb-a1b2.test.example.com", RecordType: "A", Targets: []string{"172.32.200.1", "172.32.200.2"}},
+			},
+			Assert: testutil.AssertError(""),
+		},
+		{
+			name: "valid wildcard record",
+			endpoints: []*v1alpha1.Endpoint{
+				{DNSName: "*.example.com", RecordType: "A", Targets: []string{"172.32.200.1"}},
+			},
+			Assert: testutil.AssertError(""),
+		},
+		{
+			name: "A record with hostname target",
+			endpoints: []*v1alpha1.Endpoint{
+				{DNSName: "test.example.com", RecordType: "A", Targets: []string{"aws.lb.com"}},
+			},
+			Assert: testutil.AssertError("not a valid IPv4 address"),
+		},
+		{
+			name: "CNAME record with multiple targets",
+			endpoints: []*v1alpha1.Endpoint{
+				{DNSName: "test.example.com", RecordType: "CNAME", Targets: []string{"a.lb.com", "b.lb.com"}},
+			},
+			Assert: testutil.AssertError("exactly one target"),
+		},
+		{
+			name: "CNAME record alongside another record type",
+			endpoints: []*v1alpha1.Endpoint{
+				{DNSName: "test.example.com", RecordType: "CNAME", Targets: []string{"a.lb.com"}},
+				{DNSName: "test.example.com", RecordType: "A", SetIdentifier: "a", Targets: []string{"172.32.200.1"}},
+			},
+			Assert: testutil.AssertError("has a CNAME record and other record types"),
+		},
+		{
+			name: "duplicate endpoint",
+			endpoints: []*v1alpha1.Endpoint{
+				{DNSName: "test.example.com", RecordType: "A", Targets: []string{"172.32.200.1"}},
+				{DNSName: "test.example.com", RecordType: "A", Targets: []string{"172.32.200.2"}},
+			},
+			Assert: testutil.AssertError("duplicate endpoint"),
+		},
+		{
+			name: "label too long",
+			endpoints: []*v1alpha1.Endpoint{
+				{DNSName: "a1b2.lb-a1b2.averyveryveryveryveryveryveryveryveryveryveryveryveryveryveryverylonglabel.example.com", RecordType: "A", Targets: []string{"172.32.200.1"}},
+			},
+			Assert: testutil.AssertError("must be no more than 63 characters"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			record := &v1alpha1.DNSRecord{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+				Spec: v1alpha1.DNSRecordSpec{
+					ManagedZoneRef: &v1alpha1.ManagedZoneReference{Name: "mz"},
+					Endpoints:      testCase.endpoints,
+				},
+			}
+			_, err := (&DNSRecordWebhook{}).ValidateCreate(context.TODO(), record)
+			testCase.Assert(t, err)
+		})
+	}
+}
+
+func TestManagedZoneWebhook_ValidateCreate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add scheme %s", err)
+	}
+	parent := &v1alpha1.ManagedZone{
+		ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "test"},
+		Spec: v1alpha1.ManagedZoneSpec{
+			DomainName: "example.com",
+			SecretRef:  &v1alpha1.SecretRef{Name: "secret"},
+		},
+	}
+	managedZone := func(domain, parentName string) *v1alpha1.ManagedZone {
+		mz := &v1alpha1.ManagedZone{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec: v1alpha1.ManagedZoneSpec{
+				DomainName: domain,
+				SecretRef:  &v1alpha1.SecretRef{Name: "secret"},
+			},
+		}
+		if parentName != "" {
+			mz.Spec.ParentManagedZone = &v1alpha1.ManagedZoneReference{Name: parentName}
+		}
+		return mz
+	}
+
+	testCases := []struct {
+		name         string
+		managedZone  *v1alpha1.ManagedZone
+		wantWarnings int
+		Assert       func(t *testing.T, err error)
+	}{
+		{
+			name:        "valid managed zone",
+			managedZone: managedZone("sub.example.com", ""),
+			Assert:      testutil.AssertError(""),
+		},
+		{
+			name:        "invalid domain name",
+			managedZone: managedZone("sub_.example.com", ""),
+			Assert:      testutil.AssertError("invalid spec.domainName"),
+		},
+		{
+			name:        "top level domain",
+			managedZone: managedZone("com", ""),
+			Assert:      testutil.AssertError("can not be a top level domain"),
+		},
+		{
+			name:        "parent is an ancestor domain",
+			managedZone: managedZone("sub.example.com", "parent"),
+			Assert:      testutil.AssertError(""),
+		},
+		{
+			name:        "parent is not an ancestor domain",
+			managedZone: managedZone("sub.other.com", "parent"),
+			Assert:      testutil.AssertError("is not a subdomain of the parent domain"),
+		},
+		{
+			name:         "parent does not exist",
+			managedZone:  managedZone("sub.example.com", "missing"),
+			wantWarnings: 1,
+			Assert:       testutil.AssertError(""),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			f := fake.NewClientBuilder().WithScheme(scheme).WithObjects(parent).Build()
+			warnings, err := (&ManagedZoneWebhook{Client: f}).ValidateCreate(context.TODO(), testCase.managedZone)
+			testCase.Assert(t, err)
+			if len(warnings) != testCase.wantWarnings {
+				t.Errorf("expected %d warnings, got %v", testCase.wantWarnings, warnings)
+			}
+		})
+	}
+}
+
+func TestDNSHealthCheckProbeWebhook(t *testing.T) {
+	probe := &v1alpha1.DNSHealthCheckProbe{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec: v1alpha1.DNSHealthCheckProbeSpec{
+			Host:     "test.example.com",
+			Address:  "172.32.200.1",
+			Interval: metav1.Duration{Duration: time.Second * 30},
+		},
+	}
+	w := &DNSHealthCheckProbeWebhook{}
+
+	if err := w.Default(context.TODO(), probe); err != nil {
+		t.Fatalf("unexpected error defaulting probe: %s", err)
+	}
+	if probe.Spec.Protocol != v1alpha1.HttpProtocol {
+		t.Errorf("expected protocol to be defaulted to %s, got %s", v1alpha1.HttpProtocol, probe.Spec.Protocol)
+	}
+	if _, err := w.ValidateCreate(context.TODO(), probe); err != nil {
+		t.Errorf("unexpected error validating probe: %s", err)
+	}
+
+	invalid := probe.DeepCopy()
+	invalid.Spec.Interval = metav1.Duration{Duration: time.Second}
+	_, err := w.ValidateUpdate(context.TODO(), probe, invalid)
+	testutil.AssertError("cannot be shorter than 5s")(t, err)
+
+	timeout := probe.DeepCopy()
+	timeout.Spec.Timeout = &metav1.Duration{Duration: time.Minute}
+	_, err = w.ValidateCreate(context.TODO(), timeout)
+	testutil.AssertError("cannot be longer than spec.interval")(t, err)
+	timeout.Spec.Timeout = &metav1.Duration{Duration: 0}
+	_, err = w.ValidateCreate(context.TODO(), timeout)
+	testutil.AssertError("it must be positive")(t, err)
+
+	damping := probe.DeepCopy()
+	damping.Spec.SuccessThreshold = testutil.Pointer(0)
+	_, err = w.ValidateCreate(context.TODO(), damping)
+	testutil.AssertError("invalid value for spec.successThreshold 0")(t, err)
+	damping.Spec.SuccessThreshold = testutil.Pointer(2)
+	damping.Spec.FlapDamping = &v1alpha1.FlapDamping{Transitions: 1, Window: metav1.Duration{Duration: time.Minute}, CoolDown: metav1.Duration{Duration: time.Minute}}
+	_, err = w.ValidateCreate(context.TODO(), damping)
+	testutil.AssertError("invalid value for spec.flapDamping.transitions 1")(t, err)
+	damping.Spec.FlapDamping.Transitions = 3
+	_, err = w.ValidateCreate(context.TODO(), damping)
+	testutil.AssertError("")(t, err)
+
+	certificates := probe.DeepCopy()
+	certificates.Spec.CACertificateRef = &v1alpha1.CertificateRef{Name: "ca"}
+	_, err = w.ValidateCreate(context.TODO(), certificates)
+	testutil.AssertError("only supported for HTTPS, TLS and GRPC over TLS")(t, err)
+	certificates.Spec.Protocol = v1alpha1.HttpsProtocol
+	certificates.Spec.ClientCertificateRef = &v1alpha1.CertificateRef{}
+	_, err = w.ValidateCreate(context.TODO(), certificates)
+	testutil.AssertError("spec.clientCertificateRef.name is required")(t, err)
+	certificates.Spec.ClientCertificateRef.Name = "client-cert"
+	_, err = w.ValidateCreate(context.TODO(), certificates)
+	testutil.AssertError("")(t, err)
+
+	quorum := probe.DeepCopy()
+	quorum.Spec.VantagePointQuorum = testutil.Pointer(0)
+	_, err = w.ValidateCreate(context.TODO(), quorum)
+	testutil.AssertError("invalid value for spec.vantagePointQuorum 0")(t, err)
+
+	tcp := probe.DeepCopy()
+	tcp.Spec.Protocol = v1alpha1.TcpProtocol
+	_, err = w.ValidateCreate(context.TODO(), tcp)
+	testutil.AssertError("spec.port is required for TCP probes")(t, err)
+	tcp.Spec.Port = 5432
+	_, err = w.ValidateCreate(context.TODO(), tcp)
+	testutil.AssertError("")(t, err)
+
+	assertions := probe.DeepCopy()
+	assertions.Spec.ResponseAssertions = &v1alpha1.ResponseAssertions{
+		Body: []v1alpha1.BodyAssertion{{Matches: "("}},
+	}
+	_, err = w.ValidateCreate(context.TODO(), assertions)
+	testutil.AssertError("invalid value for spec.responseAssertions.body[0].matches")(t, err)
+	assertions.Spec.ResponseAssertions.Body[0] = v1alpha1.BodyAssertion{Contains: "ok", JSONPath: "{.status}"}
+	_, err = w.ValidateCreate(context.TODO(), assertions)
+	testutil.AssertError("must set exactly one of contains, matches or jsonPath")(t, err)
+
+	deleting := invalid.DeepCopy()
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+	_, err = w.ValidateUpdate(context.TODO(), invalid, deleting)
+	testutil.AssertError("")(t, err)
+}
@@ -393,6 +393,22 @@ var _ = Describe("GatewayController", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 			m1AddressesJsonString := string(m1AddressesJson)
+			downstreamConditionsJson, err := json.Marshal([]metav1.Condition{
+				{
+					Type:               string(gatewayapiv1.GatewayConditionAccepted),
+					Status:             metav1.ConditionTrue,
+					LastTransitionTime: metav1.Now(),
+					Reason:             string(gatewayapiv1.GatewayReasonAccepted),
+				},
+				{
+					Type:               string(gatewayapiv1.GatewayConditionProgrammed),
+					Status:             metav1.ConditionTrue,
+					LastTransitionTime: metav1.Now(),
+					Reason:             string(gatewayapiv1.GatewayReasonProgrammed),
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			downstreamConditionsJsonString := string(downstreamConditionsJson)
 			manifest1.Status = ocmworkv1.ManifestWorkStatus{
 				Conditions: []metav1.Condition{
 					{
@@ -420,6 +436,13 @@ var _ = Describe("GatewayController", func() {
 											JsonRaw: &m1AddressesJsonString,
 										},
 									},
+									{
+										Name: "conditions",
+										Value: ocmworkv1.FieldValue{
+											Type:    ocmworkv1.JsonRaw,
+											JsonRaw: &downstreamConditionsJsonString,
+										},
+									},
 									{
 										Name: "listenerdefaultAttachedRoutes",
 										Value: ocmworkv1.FieldValue{
@@ -485,6 +508,13 @@ var _ = Describe("GatewayController", func() {
 											JsonRaw: &m2AddressesJsonString,
 										},
 									},
+									{
+										Name: "conditions",
+										Value: ocmworkv1.FieldValue{
+											Type:    ocmworkv1.JsonRaw,
+											JsonRaw: &downstreamConditionsJsonString,
+										},
+									},
 									{
 										Name: "listenerdefaultAttachedRoutes",
 										Value: ocmworkv1.FieldValue{
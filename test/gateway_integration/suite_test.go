@@ -25,6 +25,7 @@ import (
 	. "github.com/onsi/gomega"
 	ocmclusterv1 "open-cluster-management.io/api/cluster/v1"
 	ocmclusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	ocmclusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 	ocmworkv1 "open-cluster-management.io/api/work/v1"
 
 	"k8s.io/client-go/kubernetes/scheme"
@@ -117,6 +118,9 @@ var _ = BeforeSuite(func() {
 
 	err = ocmclusterv1beta1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
+
+	err = ocmclusterv1beta2.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
 	//+kubebuilder:scaffold:scheme
 
 	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})